@@ -45,9 +45,44 @@ const (
 	// schema is applied to the consumer cluster.
 	APIServiceBindingConditionSchemaInSync conditionsapi.ConditionType = "SchemaInSync"
 
+	// APIServiceBindingConditionPaused is set to true while syncing is paused by spec.paused.
+	APIServiceBindingConditionPaused conditionsapi.ConditionType = "Paused"
+
+	// PausedAnnotationKey can be set to "true" on downstream objects to pause syncing them.
+	PausedAnnotationKey = "kube-bind.appscode.com/paused"
+
+	// DeletionPolicyAnnotationKey can be set on downstream objects to override the deletion policy of
+	// the APIServiceBinding for them.
+	DeletionPolicyAnnotationKey = "kube-bind.appscode.com/deletion-policy"
+
+	// ReleasedAnnotationKey is set on upstream objects which have been released by the konnector,
+	// i.e. their downstream object has been deleted with the "Orphan" or "Retain" deletion policy.
+	// Its value is the deletion policy.
+	ReleasedAnnotationKey = "kube-bind.appscode.com/released"
+
 	// DownstreamFinalizer is put on downstream objects to block their deletion until
 	// the upstream object has been deleted.
 	DownstreamFinalizer = "kubebind.io/syncer"
+
+	// DownstreamConditionSpecInSync is set to false on downstream objects when their spec
+	// cannot be applied to the upstream object, e.g. due to field manager conflicts.
+	DownstreamConditionSpecInSync = "kube-bind.appscode.com/SpecInSync"
+
+	// DownstreamConditionStatusResourcesInSync is set to false on downstream objects when resources
+	// referenced by the upstream status cannot be copied, e.g. because they do not exist yet.
+	DownstreamConditionStatusResourcesInSync = "kube-bind.appscode.com/StatusResourcesInSync"
+
+	// DownstreamConditionSyncConflict is set on downstream objects whose upstream spec has been changed
+	// in the service provider cluster since the last sync. It is true while the conflict is not resolved.
+	DownstreamConditionSyncConflict = "kube-bind.appscode.com/SyncConflict"
+
+	// DownstreamConditionProviderDeleted is set on downstream objects whose upstream object has been
+	// deleted in the service provider cluster with the "MarkDeleted" provider deletion policy.
+	DownstreamConditionProviderDeleted = "kube-bind.appscode.com/ProviderDeleted"
+
+	// DownstreamConditionPaused is set to true on downstream objects while syncing them is paused,
+	// either by the kube-bind.appscode.com/paused annotation or by the APIServiceBinding.
+	DownstreamConditionPaused = "kube-bind.appscode.com/Paused"
 )
 
 // APIServiceBinding binds an API service represented by a APIServiceExport
@@ -63,6 +98,7 @@ const (
 // +kubebuilder:printcolumn:name="Resources",type="string",JSONPath=`.metadata.annotations.kube-bind\.appscode\.com/resources`,priority=1
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`,priority=0
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=0
+// +kubebuilder:printcolumn:name="Shard",type="string",JSONPath=`.status.shard`,priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`,priority=0
 type APIServiceBinding struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -89,8 +125,48 @@ type APIServiceBindingSpec struct {
 	// +kubebuilder:validation:Required
 	// Providers contains the provider ClusterIdentity and KubeconfigSecretRef of the provider cluster
 	Providers []Provider `json:"providers,omitempty"`
+
+	// deletionPolicy decides what happens to upstream objects in the service provider cluster when
+	// their downstream objects are deleted. "Delete" deletes them, "Orphan" leaves them alone for
+	// good, and "Retain" keeps them to be adopted again by a downstream object of the same name.
+	// It can be overridden per object with the kube-bind.appscode.com/deletion-policy annotation.
+	//
+	// +optional
+	// +kubebuilder:default=Delete
+	// +kubebuilder:validation:Enum=Delete;Orphan;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// paused stops syncing all objects of this binding, e.g. during maintenance of the service
+	// provider. When unpaused, all objects are reconciled again. Single objects can be paused
+	// with the kube-bind.appscode.com/paused annotation.
+	//
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// heartbeatInterval overrides the heartbeat interval of the konnector for the service provider
+	// clusters of this binding. An interval suggested by the service provider in its ClusterBinding
+	// takes precedence. If multiple bindings share a service provider cluster, the shortest
+	// interval is used.
+	//
+	// +optional
+	HeartbeatInterval *metav1.Duration `json:"heartbeatInterval,omitempty"`
 }
 
+// DeletionPolicy decides what happens to upstream objects when their downstream objects are deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the upstream object.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyOrphan leaves the upstream object alone. It is not adopted again.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+
+	// DeletionPolicyRetain keeps the upstream object. It is adopted again when a downstream object
+	// of the same name is created.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
 type Provider struct {
 	ClusterIdentity `json:",inline"`
 	RemoteNamespace string `json:"remoteNamespace,omitempty"`
@@ -106,6 +182,12 @@ type ClusterIdentity struct {
 type APIServiceBindingStatus struct {
 	// conditions is a list of conditions that apply to the APIServiceBinding.
 	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
+
+	// shard is the identity of the konnector replica syncing this APIServiceBinding when the
+	// konnector runs sharded. It is empty otherwise.
+	//
+	// +optional
+	Shard string `json:"shard,omitempty"`
 }
 
 // APIServiceBindingList is a list of APIServiceBindings.
@@ -31,6 +31,32 @@ const (
 
 const (
 	SourceSpecHashAnnotationKey = "kube-bind.appscode.com/source-spec-hash"
+
+	// ConsumerScopeAnnotationKey is set on a namespaced CRD in the service provider cluster to
+	// export it as cluster-scoped to consumers, using the Namespaced cluster-scoped isolation.
+	ConsumerScopeAnnotationKey = "kube-bind.appscode.com/consumer-scope"
+
+	// RequestedVersionsAnnotationKey holds the comma separated list of versions requested
+	// through a APIServiceExportRequest. If it is not set, all served versions are exported.
+	RequestedVersionsAnnotationKey = "kube-bind.appscode.com/requested-versions"
+
+	// RelatedResourceOwnerLabelKey is set on copies of resources referenced by the upstream status in
+	// the consumer cluster. Its value is the UID of the object owning the copy. Secrets and ConfigMaps
+	// synced into the service provider cluster as related resources by older konnectors carry it too.
+	RelatedResourceOwnerLabelKey = "kube-bind.appscode.com/related-to"
+
+	// RelatedResourceOwnerLabelPrefix prefixes the UIDs of the objects referencing a Secret or ConfigMap
+	// synced into the service provider cluster as related resource, one label per object. Objects
+	// in the same namespace may reference the same resource, which is deleted with the last of them.
+	RelatedResourceOwnerLabelPrefix = "related-to.kube-bind.appscode.com/"
+
+	// LastSyncedSpecHashAnnotationKey is set on downstream and upstream objects to the hash of the
+	// spec last synced between them. It is used to detect changes in the service provider cluster.
+	LastSyncedSpecHashAnnotationKey = "kube-bind.appscode.com/last-synced-spec-hash"
+
+	// DefaultStatusResourceNameTemplate is the default name template of copies of resources referenced
+	// by the status of upstream objects.
+	DefaultStatusResourceNameTemplate = "{{.Name}}-{{.ClusterID}}"
 )
 
 const (
@@ -44,6 +70,10 @@ const (
 	// APIServiceExportConditionConsumerInSync is set to true when the APIServiceExport's
 	// schema is applied to the consumer cluster.
 	APIServiceExportConditionConsumerInSync conditionsapi.ConditionType = "ConsumerInSync"
+
+	// APIServiceExportConditionStorageVersionInSync is set to true when the storage version
+	// of the CRD in the consumer cluster matches the storage version in the service provider cluster.
+	APIServiceExportConditionStorageVersionInSync conditionsapi.ConditionType = "StorageVersionInSync"
 )
 
 // APIServiceExport specifies the resource to be exported. It is mostly a CRD:
@@ -102,6 +132,131 @@ type APIServiceExportSpec struct {
 	// ClusterScopedIsolation specifies how cluster scoped service objects are isolated between multiple consumers on the provider side.
 	// It can be "Prefixed", "Namespaced", or "None".
 	ClusterScopedIsolation Isolation `json:"clusterScopedIsolation,omitempty"`
+
+	// fieldOwnership declares fields of the exported objects which are synced against the
+	// default direction, i.e. spec fields owned by the service provider and status fields
+	// owned by the consumer.
+	//
+	// +optional
+	FieldOwnership *APIServiceExportFieldOwnership `json:"fieldOwnership,omitempty"`
+
+	// conflictPolicy decides how the spec of an upstream object is reconciled after it has been
+	// changed in the service provider cluster since the last sync. "ConsumerWins" overwrites the
+	// upstream spec with the downstream one, "ProviderWins" overwrites the downstream spec with the
+	// upstream one, and "Halt" stops syncing the object until both sides agree again. In every case,
+	// the conflict is reported on the downstream object.
+	//
+	// +optional
+	// +kubebuilder:default=ConsumerWins
+	// +kubebuilder:validation:Enum=ConsumerWins;ProviderWins;Halt
+	ConflictPolicy ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// providerDeletionPolicy decides what happens to downstream objects whose upstream objects have
+	// been deleted in the service provider cluster. "Recreate" recreates the upstream object,
+	// "Propagate" deletes the downstream object, and "MarkDeleted" keeps the downstream object
+	// with the ProviderDeleted condition without recreating the upstream object.
+	//
+	// +optional
+	// +kubebuilder:default=Recreate
+	// +kubebuilder:validation:Enum=Recreate;Propagate;MarkDeleted
+	ProviderDeletionPolicy ProviderDeletionPolicy `json:"providerDeletionPolicy,omitempty"`
+
+	// relatedResources declares Secrets and ConfigMaps referenced by the exported objects. They
+	// are synced from the namespace of a referencing object in the consumer cluster into the
+	// corresponding namespace in the service provider cluster, and deleted when not referenced
+	// anymore. Only references of namespaced objects are synced.
+	//
+	// +optional
+	// +listType=atomic
+	RelatedResources []APIServiceExportRelatedResource `json:"relatedResources,omitempty"`
+
+	// statusResources declares Secrets and ConfigMaps referenced by the status of the upstream
+	// objects, e.g. credentials returned by the service provider. They are copied from the
+	// namespace of a referencing object in the service provider cluster into the namespace of
+	// the downstream object, and the references in the downstream status point to the copies.
+	//
+	// If empty, the Secret referenced by status.secretRef.name is propagated.
+	//
+	// +optional
+	// +listType=atomic
+	StatusResources []APIServiceExportStatusResource `json:"statusResources,omitempty"`
+}
+
+// APIServiceExportStatusResource describes a reference from the status of an exported object to a
+// Secret or ConfigMap in the same namespace.
+type APIServiceExportStatusResource struct {
+	// kind is the kind of the referenced object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Kind RelatedResourceKind `json:"kind"`
+
+	// namePath is the dot separated path of the status field holding the name of the
+	// referenced object, e.g. "status.secretRef.name".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^status([.][^.]+)+$`
+	NamePath string `json:"namePath"`
+
+	// nameTemplate is a Go template for the name of the copy in the consumer cluster.
+	// Available fields are .Name, the name of the referenced object, .ObjectName, the name
+	// of the referencing object, and .ClusterID, the ID of the service provider cluster.
+	// The name must include .ClusterID. Defaults to "{{.Name}}-{{.ClusterID}}".
+	//
+	// +optional
+	NameTemplate string `json:"nameTemplate,omitempty"`
+}
+
+// RelatedResourceKind is the kind of a related resource.
+//
+// +kubebuilder:validation:Enum=Secret;ConfigMap
+type RelatedResourceKind string
+
+const (
+	RelatedResourceKindSecret    RelatedResourceKind = "Secret"
+	RelatedResourceKindConfigMap RelatedResourceKind = "ConfigMap"
+)
+
+// APIServiceExportRelatedResource describes a reference from an exported object to a Secret or ConfigMap
+// in the same namespace.
+type APIServiceExportRelatedResource struct {
+	// kind is the kind of the referenced object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Kind RelatedResourceKind `json:"kind"`
+
+	// namePath is the dot separated path of the field holding the name of the referenced
+	// object, e.g. "spec.authSecret.name".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[^.]+([.][^.]+)+$`
+	NamePath string `json:"namePath"`
+}
+
+// APIServiceExportFieldOwnership declares the owners of individual fields of exported objects.
+// Paths are dot separated field paths, e.g. "spec.replicas". Lists and maps below a path are
+// owned as a whole.
+type APIServiceExportFieldOwnership struct {
+	// providerOwnedPaths are paths below spec owned by the service provider, e.g. because they are
+	// defaulted by a service provider controller. They are synced from the upstream object into
+	// the downstream object, and never from the downstream to the upstream object.
+	//
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:XValidation:rule=`self.all(p, p.matches('^spec([.][^.]+)+$'))`,message="providerOwnedPaths must be below spec"
+	ProviderOwnedPaths []string `json:"providerOwnedPaths,omitempty"`
+
+	// consumerOwnedPaths are paths below status owned by the consumer. They are synced from the
+	// downstream object into the upstream object, and never from the upstream to the downstream
+	// object.
+	//
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:XValidation:rule=`self.all(p, p.matches('^status([.][^.]+)+$'))`,message="consumerOwnedPaths must be below status"
+	ConsumerOwnedPaths []string `json:"consumerOwnedPaths,omitempty"`
 }
 
 // Isolation is an enum defining the different ways to isolate cluster scoped objects
@@ -120,6 +275,36 @@ const (
 	IsolationNone Isolation = "None"
 )
 
+// ConflictPolicy decides how conflicting changes of downstream and upstream objects are resolved.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyConsumerWins overwrites the upstream spec with the downstream spec.
+	ConflictPolicyConsumerWins ConflictPolicy = "ConsumerWins"
+
+	// ConflictPolicyProviderWins overwrites the downstream spec with the upstream spec.
+	ConflictPolicyProviderWins ConflictPolicy = "ProviderWins"
+
+	// ConflictPolicyHalt stops syncing the spec until downstream and upstream agree again.
+	ConflictPolicyHalt ConflictPolicy = "Halt"
+)
+
+// ProviderDeletionPolicy decides what happens to downstream objects whose upstream objects have
+// been deleted in the service provider cluster.
+type ProviderDeletionPolicy string
+
+const (
+	// ProviderDeletionPolicyRecreate recreates the upstream object from the downstream object.
+	ProviderDeletionPolicyRecreate ProviderDeletionPolicy = "Recreate"
+
+	// ProviderDeletionPolicyPropagate deletes the downstream object.
+	ProviderDeletionPolicyPropagate ProviderDeletionPolicy = "Propagate"
+
+	// ProviderDeletionPolicyMarkDeleted keeps the downstream object, marked with the ProviderDeleted
+	// condition, and does not recreate the upstream object.
+	ProviderDeletionPolicyMarkDeleted ProviderDeletionPolicy = "MarkDeleted"
+)
+
 type APIServiceExportCRDSpec struct {
 	// group is the API group of the defined custom resource. Empty string means the
 	// core API group. 	The resources are served under `/apis/<group>/...` or `/api` for the core group.
@@ -149,6 +334,35 @@ type APIServiceExportCRDSpec struct {
 	// +listMapKey=name
 	// +kubebuilder:validation:MinItems=1
 	Versions []APIServiceExportVersion `json:"versions"`
+
+	// conversion describes the conversion webhook of the custom resource in the service provider cluster.
+	// If set, the konnector serves conversion requests in the consumer cluster by forwarding them
+	// to this webhook through the service provider API server. If unset, versions are converted
+	// with the None strategy.
+	//
+	// The konnector is granted create and get on the services/proxy subresource of the service and
+	// port, which the API server does not restrict by path. The service port must serve nothing but
+	// the conversion webhook.
+	//
+	// +optional
+	Conversion *APIServiceExportConversion `json:"conversion,omitempty"`
+}
+
+// APIServiceExportConversion describes the conversion webhook of an exported resource.
+type APIServiceExportConversion struct {
+	// service references the conversion webhook service in the service provider cluster.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Service apiextensionsv1.ServiceReference `json:"service"`
+
+	// conversionReviewVersions is an ordered list of preferred `ConversionReview`
+	// versions the webhook expects.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +listType=atomic
+	ConversionReviewVersions []string `json:"conversionReviewVersions"`
 }
 
 // APIServiceExportVersion describes one API version of a resource.
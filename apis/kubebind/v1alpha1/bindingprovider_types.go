@@ -47,6 +47,8 @@ type AuthenticationMethod struct {
 	// method is the name of the authentication method. The follow methods are supported:
 	//
 	// - "OAuth2CodeGrant"
+	// - "OAuth2DeviceGrant"
+	// - "BindToken"
 	//
 	// The list is ordered by preference by the service provider. The consumer should
 	// try to use the first method in the list that matches the capabilities of the
@@ -55,11 +57,19 @@ type AuthenticationMethod struct {
 	//
 	// +required
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=OAuth2CodeGrant
+	// +kubebuilder:validation:Enum=OAuth2CodeGrant;OAuth2DeviceGrant;BindToken
 	Method string `json:"method,omitempty"`
 
 	// OAuth2CodeGrant is the configuration for the OAuth2 code grant flow.
 	OAuth2CodeGrant *OAuth2CodeGrant `json:"oauth2CodeGrant,omitempty"`
+
+	// OAuth2DeviceGrant is the configuration for the OAuth2 device authorization
+	// grant flow, for consumers without a local web browser.
+	OAuth2DeviceGrant *OAuth2DeviceGrant `json:"oauth2DeviceGrant,omitempty"`
+
+	// BindToken is the configuration for binding non-interactively with a bind token
+	// pre-issued by the service provider, e.g. in GitOps or CI.
+	BindToken *BindToken `json:"bindToken,omitempty"`
 }
 
 type OAuth2CodeGrant struct {
@@ -71,3 +81,54 @@ type OAuth2CodeGrant struct {
 	// +kubebuilder:validation:MinLength=1
 	AuthenticatedURL string `json:"authenticatedURL"`
 }
+
+type OAuth2DeviceGrant struct {
+	// deviceAuthorizationURL is the service provider url that the service consumer
+	// requests a device code and a user code from, e.g: www.mangodb.com/kubernetes/device/authorize.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	DeviceAuthorizationURL string `json:"deviceAuthorizationURL"`
+
+	// tokenURL is the service provider url that the service consumer polls with the
+	// device code until the user has authorized, returning the BindingResponse,
+	// e.g: www.mangodb.com/kubernetes/device/token.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	TokenURL string `json:"tokenURL"`
+}
+
+type BindToken struct {
+	// bindURL is the service provider url that the service consumer posts the bind token
+	// to as bearer token, returning the BindingResponse, e.g: www.mangodb.com/kubernetes/bind-token.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	BindURL string `json:"bindURL"`
+}
+
+// OAuth2DeviceAuthorization is returned by the device authorization url of the
+// OAuth2DeviceGrant method. The fields follow RFC 8628.
+type OAuth2DeviceAuthorization struct {
+	// deviceCode is the secret the service consumer polls the token url with.
+	DeviceCode string `json:"device_code"`
+
+	// userCode is the code the user enters at the verification url.
+	UserCode string `json:"user_code"`
+
+	// verificationURI is the service provider url the user visits in a web browser.
+	VerificationURI string `json:"verification_uri"`
+
+	// verificationURIComplete is the verification url including the user code.
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+
+	// expiresIn is the lifetime of the device code and user code in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+
+	// interval is the minimal number of seconds between polls of the token url.
+	Interval int64 `json:"interval,omitempty"`
+}
@@ -59,6 +59,12 @@ type BindingResponseAuthentication struct {
 	// +optional
 	// +kubebuilder:validation:Optional
 	OAuth2CodeGrant *BindingResponseAuthenticationOAuth2CodeGrant `json:"oauth2CodeGrant,omitempty"`
+
+	// bindToken is the data returned when binding with a bind token.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	BindToken *BindingResponseAuthenticationBindToken `json:"bindToken,omitempty"`
 }
 
 // BindingResponseAuthenticationOAuth2CodeGrant contains the authentication data which is passed back to
@@ -72,3 +78,15 @@ type BindingResponseAuthenticationOAuth2CodeGrant struct {
 	// id is the ID of the authenticated user. It is for informational purposes only.
 	ID string `json:"id"`
 }
+
+// BindingResponseAuthenticationBindToken contains the authentication data which is passed back to
+// the consumer as BindingResponse.Authentication when binding with a bind token.
+type BindingResponseAuthenticationBindToken struct {
+	// sessionID is the session ID that was originally passed from the consumer to
+	// the service provider. It must be checked to equal the original value.
+	SessionID string `json:"sid"`
+
+	// consumer is the name of the consumer the bind token was issued for. It is for
+	// informational purposes only.
+	Consumer string `json:"consumer"`
+}
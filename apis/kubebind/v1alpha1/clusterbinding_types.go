@@ -117,6 +117,13 @@ type ClusterBindingSpec struct {
 	// binding request. The service providers decide what they need and what to configure based on what then include in
 	// this field, such as service region, type, tiers, etc...
 	ServiceProviderSpec runtime.RawExtension `json:"serviceProviderSpec,omitempty"`
+
+	// heartbeatInterval is the interval between heartbeats the service provider suggests to the
+	// konnector. If set, the konnector adopts it over its own configuration and reports it in
+	// status.heartbeatInterval.
+	//
+	// +optional
+	HeartbeatInterval *metav1.Duration `json:"heartbeatInterval,omitempty"`
 }
 
 // ClusterBindingStatus stores status information about a service binding. It is
@@ -138,6 +145,20 @@ type ClusterBindingStatus struct {
 	// consumer cluster.
 	KonnectorVersion string `json:"konnectorVersion,omitempty"`
 
+	// credentialsExpiration is the time the credentials in the kubeconfig secret
+	// expire. It is set by the service provider.
+	//
+	// +optional
+	CredentialsExpiration *metav1.Time `json:"credentialsExpiration,omitempty"`
+
+	// credentialsRenewalRequestTime is set by the konnector to ask the service
+	// provider for new client certificate credentials before the current ones
+	// expire. The service provider clears it when it has renewed the kubeconfig
+	// secret.
+	//
+	// +optional
+	CredentialsRenewalRequestTime *metav1.Time `json:"credentialsRenewalRequestTime,omitempty"`
+
 	// conditions is a list of conditions that apply to the ClusterBinding. It is
 	// updated by the konnector and the service provider.
 	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
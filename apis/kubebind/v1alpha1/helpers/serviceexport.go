@@ -21,12 +21,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	runtime2 "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/yaml"
 )
 
@@ -73,23 +76,46 @@ func ServiceExportToCRD(export *kubebindv1alpha1.APIServiceExport) (*apiextensio
 	return crd, nil
 }
 
-// CRDToServiceExport converts a CRD to a APIServiceExport.
-func CRDToServiceExport(crd *apiextensionsv1.CustomResourceDefinition) (*kubebindv1alpha1.APIServiceExportCRDSpec, error) {
+// CRDToServiceExport converts a CRD to a APIServiceExport. If versions is not empty, only
+// these versions are exported, and all of them must be served by the CRD.
+func CRDToServiceExport(crd *apiextensionsv1.CustomResourceDefinition, versions []string) (*kubebindv1alpha1.APIServiceExportCRDSpec, error) {
+	if unserved := UnservedVersions(crd, versions); len(unserved) > 0 {
+		return nil, fmt.Errorf("versions %s of CRD %s are not served", strings.Join(unserved, ", "), crd.Name)
+	}
+
 	spec := &kubebindv1alpha1.APIServiceExportCRDSpec{
 		Group: crd.Spec.Group,
 		Names: crd.Spec.Names,
 		Scope: crd.Spec.Scope,
 	}
+	if IsNamespacedIsolationCRD(crd) {
+		spec.Scope = apiextensionsv1.ClusterScoped
+	}
 
-	onlyFirstServingVersion := crd.Spec.Conversion != nil && crd.Spec.Conversion.Strategy == apiextensionsv1.WebhookConverter
-	// TODO: come up with an API to select versions
+	// Conversion webhooks referencing a service are served to consumers by the konnector. Others
+	// cannot be reached from the consumer cluster, so we export only one version.
+	requested := sets.New[string](versions...)
+	onlyFirstServingVersion := false
+	if crd.Spec.Conversion != nil && crd.Spec.Conversion.Strategy == apiextensionsv1.WebhookConverter {
+		if webhook := crd.Spec.Conversion.Webhook; webhook != nil && webhook.ClientConfig != nil && webhook.ClientConfig.Service != nil {
+			spec.Conversion = &kubebindv1alpha1.APIServiceExportConversion{
+				Service:                  *webhook.ClientConfig.Service,
+				ConversionReviewVersions: webhook.ConversionReviewVersions,
+			}
+		} else {
+			onlyFirstServingVersion = true
+		}
+	}
 	for i := range crd.Spec.Versions {
 		crdVersion := crd.Spec.Versions[i]
 
-		// skip non-served versions
+		// skip non-served and non-requested versions
 		if !crdVersion.Served {
 			continue
 		}
+		if requested.Len() > 0 && !requested.Has(crdVersion.Name) {
+			continue
+		}
 
 		apiResourceVersion := kubebindv1alpha1.APIServiceExportVersion{
 			Name:                     crdVersion.Name,
@@ -120,9 +146,63 @@ func CRDToServiceExport(crd *apiextensionsv1.CustomResourceDefinition) (*kubebin
 		}
 	}
 
+	// the storage version might not be among the requested ones
+	hasStorage := false
+	for _, v := range spec.Versions {
+		hasStorage = hasStorage || v.Storage
+	}
+	if !hasStorage && len(spec.Versions) > 0 {
+		spec.Versions[0].Storage = true
+	}
+
 	return spec, nil
 }
 
+// ConversionServiceProxyName returns the name of the conversion webhook service as used
+// in the services/proxy subresource of the service provider API server.
+func ConversionServiceProxyName(conversion *kubebindv1alpha1.APIServiceExportConversion) string {
+	port := int32(443)
+	if conversion.Service.Port != nil {
+		port = *conversion.Service.Port
+	}
+	return fmt.Sprintf("https:%s:%d", conversion.Service.Name, port)
+}
+
+// UnservedVersions returns those of the given versions that are not served by the CRD.
+func UnservedVersions(crd *apiextensionsv1.CustomResourceDefinition, versions []string) []string {
+	served := sets.New[string]()
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			served.Insert(v.Name)
+		}
+	}
+	var unserved []string
+	for _, v := range versions {
+		if !served.Has(v) {
+			unserved = append(unserved, v)
+		}
+	}
+	return unserved
+}
+
+// RequestedVersions returns the versions selected for the APIServiceExport, or nil if all served
+// versions are exported.
+func RequestedVersions(export *kubebindv1alpha1.APIServiceExport) []string {
+	value := export.Annotations[kubebindv1alpha1.RequestedVersionsAnnotationKey]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// IsNamespacedIsolationCRD returns true if the given CRD in the service provider cluster is namespaced,
+// but exported as cluster-scoped to consumers. The CRD must be installed that way by the service
+// provider. A cluster-scoped CRD cannot be turned into one, as both share the same name.
+func IsNamespacedIsolationCRD(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return crd.Spec.Scope == apiextensionsv1.NamespaceScoped &&
+		crd.Annotations[kubebindv1alpha1.ConsumerScopeAnnotationKey] == string(apiextensionsv1.ClusterScoped)
+}
+
 func APIServiceExportCRDSpecHash(obj *kubebindv1alpha1.APIServiceExportCRDSpec) string {
 	bs, err := json.Marshal(obj)
 	if err != nil {
@@ -142,3 +222,27 @@ func toBase62(hash [28]byte) string {
 	i.SetBytes(hash[:])
 	return i.Text(62)
 }
+
+// RelatedResourceGVR returns the resource of the given related resource kind.
+func RelatedResourceGVR(kind kubebindv1alpha1.RelatedResourceKind) schema.GroupVersionResource {
+	switch kind {
+	case kubebindv1alpha1.RelatedResourceKindConfigMap:
+		return schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	default:
+		return schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	}
+}
+
+// StatusResources returns the resources referenced by the status of the exported objects. Without
+// declaration, the Secret referenced by status.secretRef.name is propagated.
+func StatusResources(export *kubebindv1alpha1.APIServiceExport) []kubebindv1alpha1.APIServiceExportStatusResource {
+	if len(export.Spec.StatusResources) > 0 {
+		return export.Spec.StatusResources
+	}
+	return []kubebindv1alpha1.APIServiceExportStatusResource{
+		{
+			Kind:     kubebindv1alpha1.RelatedResourceKindSecret,
+			NamePath: "status.secretRef.name",
+		},
+	}
+}
@@ -19,8 +19,11 @@ package helpers
 import (
 	"testing"
 
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
 
@@ -51,7 +54,7 @@ func TestWebhookCRDStorageVersion(t *testing.T) {
 		},
 	}
 
-	output, err := CRDToServiceExport(&input)
+	output, err := CRDToServiceExport(&input, nil)
 
 	require.NoError(t, err)
 
@@ -63,3 +66,100 @@ func TestWebhookCRDStorageVersion(t *testing.T) {
 		t.Fatal("returned ResourceExport has no storage version", output)
 	}
 }
+
+func TestNamespacedIsolationProviderCRD(t *testing.T) {
+	crd := &v1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foos.example.com",
+			Annotations: map[string]string{
+				kubebindv1alpha1.ConsumerScopeAnnotationKey: string(v1.ClusterScoped),
+			},
+		},
+		Spec: v1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: v1.CustomResourceDefinitionNames{Plural: "foos", Kind: "Foo"},
+			Scope: v1.NamespaceScoped,
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+	require.True(t, IsNamespacedIsolationCRD(crd))
+
+	spec, err := CRDToServiceExport(crd, nil)
+	require.NoError(t, err)
+	require.Equal(t, v1.ClusterScoped, spec.Scope)
+
+	delete(crd.Annotations, kubebindv1alpha1.ConsumerScopeAnnotationKey)
+	require.False(t, IsNamespacedIsolationCRD(crd))
+
+	spec, err = CRDToServiceExport(crd, nil)
+	require.NoError(t, err)
+	require.Equal(t, v1.NamespaceScoped, spec.Scope)
+}
+
+func TestCRDToServiceExportVersionSelection(t *testing.T) {
+	input := v1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foos.example.com",
+		},
+		Spec: v1.CustomResourceDefinitionSpec{
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v0", Served: false},
+			},
+		},
+	}
+
+	output, err := CRDToServiceExport(&input, nil)
+	require.NoError(t, err)
+	require.Len(t, output.Versions, 3)
+
+	output, err = CRDToServiceExport(&input, []string{"v1beta1", "v1"})
+	require.NoError(t, err)
+	require.Len(t, output.Versions, 2)
+	require.Equal(t, "v1beta1", output.Versions[0].Name)
+	require.True(t, output.Versions[1].Storage)
+
+	output, err = CRDToServiceExport(&input, []string{"v1alpha1"})
+	require.NoError(t, err)
+	require.Len(t, output.Versions, 1)
+	require.True(t, output.Versions[0].Storage, "the only requested version must become the storage version")
+
+	_, err = CRDToServiceExport(&input, []string{"v1", "v0"})
+	require.Error(t, err)
+	require.Equal(t, []string{"v0", "v2"}, UnservedVersions(&input, []string{"v1", "v0", "v2"}))
+}
+
+func TestWebhookServiceCRDKeepsVersions(t *testing.T) {
+	input := v1.CustomResourceDefinition{
+		Spec: v1.CustomResourceDefinitionSpec{
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{Served: true, Name: "v1alpha1"},
+				{Served: true, Name: "v1", Storage: true},
+			},
+			Conversion: &v1.CustomResourceConversion{
+				Strategy: v1.WebhookConverter,
+				Webhook: &v1.WebhookConversion{
+					ClientConfig: &v1.WebhookClientConfig{
+						Service: &v1.ServiceReference{
+							Namespace: "foo-system",
+							Name:      "foo-webhook",
+							Path:      ptr.To("/convert"),
+						},
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+		},
+	}
+
+	output, err := CRDToServiceExport(&input, nil)
+	require.NoError(t, err)
+	require.Len(t, output.Versions, 2)
+	require.NotNil(t, output.Conversion)
+	require.Equal(t, "foo-webhook", output.Conversion.Service.Name)
+	require.Equal(t, "https:foo-webhook:443", ConversionServiceProxyName(output.Conversion))
+}
@@ -23,8 +23,9 @@ package v1alpha1
 
 import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
-	v1 "kmodules.xyz/client-go/api/v1"
+	apiv1 "kmodules.xyz/client-go/api/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -96,6 +97,11 @@ func (in *APIServiceBindingSpec) DeepCopyInto(out *APIServiceBindingSpec) {
 		*out = make([]Provider, len(*in))
 		copy(*out, *in)
 	}
+	if in.HeartbeatInterval != nil {
+		in, out := &in.HeartbeatInterval, &out.HeartbeatInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -114,7 +120,7 @@ func (in *APIServiceBindingStatus) DeepCopyInto(out *APIServiceBindingStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make(v1.Conditions, len(*in))
+		*out = make(apiv1.Conditions, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -171,6 +177,11 @@ func (in *APIServiceExportCRDSpec) DeepCopyInto(out *APIServiceExportCRDSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conversion != nil {
+		in, out := &in.Conversion, &out.Conversion
+		*out = new(APIServiceExportConversion)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -184,6 +195,54 @@ func (in *APIServiceExportCRDSpec) DeepCopy() *APIServiceExportCRDSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceExportConversion) DeepCopyInto(out *APIServiceExportConversion) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	if in.ConversionReviewVersions != nil {
+		in, out := &in.ConversionReviewVersions, &out.ConversionReviewVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceExportConversion.
+func (in *APIServiceExportConversion) DeepCopy() *APIServiceExportConversion {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceExportConversion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceExportFieldOwnership) DeepCopyInto(out *APIServiceExportFieldOwnership) {
+	*out = *in
+	if in.ProviderOwnedPaths != nil {
+		in, out := &in.ProviderOwnedPaths, &out.ProviderOwnedPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConsumerOwnedPaths != nil {
+		in, out := &in.ConsumerOwnedPaths, &out.ConsumerOwnedPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceExportFieldOwnership.
+func (in *APIServiceExportFieldOwnership) DeepCopy() *APIServiceExportFieldOwnership {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceExportFieldOwnership)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServiceExportList) DeepCopyInto(out *APIServiceExportList) {
 	*out = *in
@@ -217,6 +276,22 @@ func (in *APIServiceExportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceExportRelatedResource) DeepCopyInto(out *APIServiceExportRelatedResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceExportRelatedResource.
+func (in *APIServiceExportRelatedResource) DeepCopy() *APIServiceExportRelatedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceExportRelatedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServiceExportRequest) DeepCopyInto(out *APIServiceExportRequest) {
 	*out = *in
@@ -361,7 +436,7 @@ func (in *APIServiceExportRequestStatus) DeepCopyInto(out *APIServiceExportReque
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make(v1.Conditions, len(*in))
+		*out = make(apiv1.Conditions, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -400,6 +475,21 @@ func (in *APIServiceExportSchema) DeepCopy() *APIServiceExportSchema {
 func (in *APIServiceExportSpec) DeepCopyInto(out *APIServiceExportSpec) {
 	*out = *in
 	in.APIServiceExportCRDSpec.DeepCopyInto(&out.APIServiceExportCRDSpec)
+	if in.FieldOwnership != nil {
+		in, out := &in.FieldOwnership, &out.FieldOwnership
+		*out = new(APIServiceExportFieldOwnership)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RelatedResources != nil {
+		in, out := &in.RelatedResources, &out.RelatedResources
+		*out = make([]APIServiceExportRelatedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatusResources != nil {
+		in, out := &in.StatusResources, &out.StatusResources
+		*out = make([]APIServiceExportStatusResource, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -424,7 +514,7 @@ func (in *APIServiceExportStatus) DeepCopyInto(out *APIServiceExportStatus) {
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make(v1.Conditions, len(*in))
+		*out = make(apiv1.Conditions, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -442,6 +532,22 @@ func (in *APIServiceExportStatus) DeepCopy() *APIServiceExportStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServiceExportStatusResource) DeepCopyInto(out *APIServiceExportStatusResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServiceExportStatusResource.
+func (in *APIServiceExportStatusResource) DeepCopy() *APIServiceExportStatusResource {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServiceExportStatusResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIServiceExportVersion) DeepCopyInto(out *APIServiceExportVersion) {
 	*out = *in
@@ -571,6 +677,16 @@ func (in *AuthenticationMethod) DeepCopyInto(out *AuthenticationMethod) {
 		*out = new(OAuth2CodeGrant)
 		**out = **in
 	}
+	if in.OAuth2DeviceGrant != nil {
+		in, out := &in.OAuth2DeviceGrant, &out.OAuth2DeviceGrant
+		*out = new(OAuth2DeviceGrant)
+		**out = **in
+	}
+	if in.BindToken != nil {
+		in, out := &in.BindToken, &out.BindToken
+		*out = new(BindToken)
+		**out = **in
+	}
 	return
 }
 
@@ -584,6 +700,22 @@ func (in *AuthenticationMethod) DeepCopy() *AuthenticationMethod {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindToken) DeepCopyInto(out *BindToken) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindToken.
+func (in *BindToken) DeepCopy() *BindToken {
+	if in == nil {
+		return nil
+	}
+	out := new(BindToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BindingProvider) DeepCopyInto(out *BindingProvider) {
 	*out = *in
@@ -662,6 +794,11 @@ func (in *BindingResponseAuthentication) DeepCopyInto(out *BindingResponseAuthen
 		*out = new(BindingResponseAuthenticationOAuth2CodeGrant)
 		**out = **in
 	}
+	if in.BindToken != nil {
+		in, out := &in.BindToken, &out.BindToken
+		*out = new(BindingResponseAuthenticationBindToken)
+		**out = **in
+	}
 	return
 }
 
@@ -675,6 +812,22 @@ func (in *BindingResponseAuthentication) DeepCopy() *BindingResponseAuthenticati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingResponseAuthenticationBindToken) DeepCopyInto(out *BindingResponseAuthenticationBindToken) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingResponseAuthenticationBindToken.
+func (in *BindingResponseAuthenticationBindToken) DeepCopy() *BindingResponseAuthenticationBindToken {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingResponseAuthenticationBindToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BindingResponseAuthenticationOAuth2CodeGrant) DeepCopyInto(out *BindingResponseAuthenticationOAuth2CodeGrant) {
 	*out = *in
@@ -757,6 +910,11 @@ func (in *ClusterBindingSpec) DeepCopyInto(out *ClusterBindingSpec) {
 	*out = *in
 	out.KubeconfigSecretRef = in.KubeconfigSecretRef
 	in.ServiceProviderSpec.DeepCopyInto(&out.ServiceProviderSpec)
+	if in.HeartbeatInterval != nil {
+		in, out := &in.HeartbeatInterval, &out.HeartbeatInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -780,9 +938,17 @@ func (in *ClusterBindingStatus) DeepCopyInto(out *ClusterBindingStatus) {
 	}
 	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
 	out.HeartbeatInterval = in.HeartbeatInterval
+	if in.CredentialsExpiration != nil {
+		in, out := &in.CredentialsExpiration, &out.CredentialsExpiration
+		*out = (*in).DeepCopy()
+	}
+	if in.CredentialsRenewalRequestTime != nil {
+		in, out := &in.CredentialsRenewalRequestTime, &out.CredentialsRenewalRequestTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make(v1.Conditions, len(*in))
+		*out = make(apiv1.Conditions, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -897,6 +1063,38 @@ func (in *OAuth2CodeGrant) DeepCopy() *OAuth2CodeGrant {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2DeviceAuthorization) DeepCopyInto(out *OAuth2DeviceAuthorization) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2DeviceAuthorization.
+func (in *OAuth2DeviceAuthorization) DeepCopy() *OAuth2DeviceAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2DeviceAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2DeviceGrant) DeepCopyInto(out *OAuth2DeviceGrant) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2DeviceGrant.
+func (in *OAuth2DeviceGrant) DeepCopy() *OAuth2DeviceGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2DeviceGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
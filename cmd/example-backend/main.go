@@ -73,6 +73,7 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error: %v", err) // nolint: errcheck
 		os.Exit(1)
 	}
+	server.StartHealthServer(ctx)
 	server.OptionallyStartInformers(ctx)
 	if err := server.Run(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v", err) // nolint: errcheck
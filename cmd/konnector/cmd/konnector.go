@@ -66,8 +66,16 @@ func New(ctx context.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			prepared.StartMetricsServer(ctx)
+			prepared.StartHealthServer(ctx)
+			prepared.StartConversionWebhook(ctx)
 			prepared.OptionallyStartInformers(ctx)
 
+			if options.Sharding {
+				logger.Info("starting sharded konnector controller", "identity", options.LeaseLockIdentity)
+				return prepared.Run(ctx)
+			}
+
 			logger.Info("trying to acquire the lock")
 			lock := NewLock(config.KubeClient, options.LeaseLockNamespace, options.LeaseLockName, options.LeaseLockIdentity)
 			runLeaderElection(ctx, lock, options.LeaseLockIdentity, func(ctx context.Context) {
@@ -21,10 +21,13 @@ import (
 
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
 	"go.bytebuilders.dev/kube-bind/contrib/example-backend/options"
 
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	kubeinformers "k8s.io/client-go/informers"
 	kubernetesclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -42,6 +45,8 @@ type Config struct {
 	KubeInformers          kubeinformers.SharedInformerFactory
 	BindInformers          bindinformers.SharedInformerFactory
 	ApiextensionsInformers apiextensionsinformers.SharedInformerFactory
+	// BindTokenInformers watches the bind token Secrets, nil if bind tokens are disabled.
+	BindTokenInformers kubeinformers.SharedInformerFactory
 }
 
 func NewConfig(options *options.CompletedOptions) (*Config, error) {
@@ -74,6 +79,14 @@ func NewConfig(options *options.CompletedOptions) (*Config, error) {
 	config.KubeInformers = kubeinformers.NewSharedInformerFactory(config.KubeClient, time.Minute*30)
 	config.BindInformers = bindinformers.NewSharedInformerFactory(config.BindClient, time.Minute*30)
 	config.ApiextensionsInformers = apiextensionsinformers.NewSharedInformerFactory(config.ApiextensionsClient, time.Minute*30)
+	if options.BindTokenNamespace != "" {
+		config.BindTokenInformers = kubeinformers.NewSharedInformerFactoryWithOptions(config.KubeClient, time.Minute*30,
+			kubeinformers.WithNamespace(options.BindTokenNamespace),
+			kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.FieldSelector = fields.OneTermEqualSelector("type", resources.BindTokenType).String()
+			}),
+		)
+	}
 
 	return config, nil
 }
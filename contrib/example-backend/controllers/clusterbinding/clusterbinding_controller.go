@@ -54,10 +54,12 @@ const (
 func NewController(
 	config *rest.Config,
 	scope v1alpha1.Scope,
+	heartbeatInterval time.Duration,
 	clusterBindingInformer bindinformers.ClusterBindingInformer,
 	serviceExportInformer bindinformers.APIServiceExportInformer,
 	clusterRoleInformer rbacinformers.ClusterRoleInformer,
 	clusterRoleBindingInformer rbacinformers.ClusterRoleBindingInformer,
+	roleInformer rbacinformers.RoleInformer,
 	roleBindingInformer rbacinformers.RoleBindingInformer,
 	namespaceInformer kubeinformers.NamespaceInformer,
 ) (*Controller, error) {
@@ -98,7 +100,8 @@ func NewController(
 		namespaceIndexer: namespaceInformer.Informer().GetIndexer(),
 
 		reconciler: reconciler{
-			scope: scope,
+			scope:             scope,
+			heartbeatInterval: heartbeatInterval,
 			listServiceExports: func(ns string) ([]*v1alpha1.APIServiceExport, error) {
 				return serviceExportInformer.Lister().APIServiceExports(ns).List(labels.Everything())
 			},
@@ -111,6 +114,9 @@ func NewController(
 			updateClusterRole: func(ctx context.Context, binding *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error) {
 				return kubeClient.RbacV1().ClusterRoles().Update(ctx, binding, metav1.UpdateOptions{})
 			},
+			deleteClusterRole: func(ctx context.Context, name string) error {
+				return kubeClient.RbacV1().ClusterRoles().Delete(ctx, name, metav1.DeleteOptions{})
+			},
 			getClusterRoleBinding: func(name string) (*rbacv1.ClusterRoleBinding, error) {
 				return clusterRoleBindingInformer.Lister().Get(name)
 			},
@@ -135,6 +141,24 @@ func NewController(
 			getRoleBinding: func(ns, name string) (*rbacv1.RoleBinding, error) {
 				return roleBindingInformer.Lister().RoleBindings(ns).Get(name)
 			},
+			deleteRoleBinding: func(ctx context.Context, ns, name string) error {
+				return kubeClient.RbacV1().RoleBindings(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			listRoles: func(selector labels.Selector) ([]*rbacv1.Role, error) {
+				return roleInformer.Lister().List(selector)
+			},
+			getRole: func(ns, name string) (*rbacv1.Role, error) {
+				return roleInformer.Lister().Roles(ns).Get(name)
+			},
+			createRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Create(ctx, role, metav1.CreateOptions{})
+			},
+			updateRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Update(ctx, role, metav1.UpdateOptions{})
+			},
+			deleteRole: func(ctx context.Context, ns, name string) error {
+				return kubeClient.RbacV1().Roles(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
 		},
 
 		commit: committer.NewCommitter[*v1alpha1.ClusterBinding, *v1alpha1.ClusterBindingSpec, *v1alpha1.ClusterBindingStatus](
@@ -23,26 +23,31 @@ import (
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
 	kuberesources "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	conditionsapi "kmodules.xyz/client-go/api/v1"
 	"kmodules.xyz/client-go/conditions"
 )
 
 type reconciler struct {
-	scope v1alpha1.Scope
+	scope             v1alpha1.Scope
+	heartbeatInterval time.Duration
 
 	listServiceExports func(ns string) ([]*v1alpha1.APIServiceExport, error)
 
 	getClusterRole    func(name string) (*rbacv1.ClusterRole, error)
 	createClusterRole func(ctx context.Context, binding *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error)
 	updateClusterRole func(ctx context.Context, binding *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error)
+	deleteClusterRole func(ctx context.Context, name string) error
 
 	getClusterRoleBinding    func(name string) (*rbacv1.ClusterRoleBinding, error)
 	createClusterRoleBinding func(ctx context.Context, binding *rbacv1.ClusterRoleBinding) (*rbacv1.ClusterRoleBinding, error)
@@ -52,6 +57,13 @@ type reconciler struct {
 	getRoleBinding    func(ns, name string) (*rbacv1.RoleBinding, error)
 	createRoleBinding func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 	updateRoleBinding func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
+	deleteRoleBinding func(ctx context.Context, ns, name string) error
+
+	listRoles  func(selector labels.Selector) ([]*rbacv1.Role, error)
+	getRole    func(ns, name string) (*rbacv1.Role, error)
+	createRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+	updateRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+	deleteRole func(ctx context.Context, ns, name string) error
 
 	getNamespace func(name string) (*corev1.Namespace, error)
 }
@@ -59,6 +71,7 @@ type reconciler struct {
 func (r *reconciler) reconcile(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
 	var errs []error
 
+	r.ensureHeartbeatInterval(clusterBinding)
 	r.ensureClusterBindingConditions(clusterBinding)
 	if err := r.ensureRBACRoleBinding(ctx, clusterBinding); err != nil {
 		errs = append(errs, err)
@@ -69,12 +82,23 @@ func (r *reconciler) reconcile(ctx context.Context, clusterBinding *v1alpha1.Clu
 	if err := r.ensureRBACClusterRoleBinding(ctx, clusterBinding); err != nil {
 		errs = append(errs, err)
 	}
+	if err := r.ensureRBACConversionProxy(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
 
 	conditions.SetSummary(clusterBinding)
 
 	return utilerrors.NewAggregate(errs)
 }
 
+// ensureHeartbeatInterval suggests the configured heartbeat interval to the konnector.
+func (r *reconciler) ensureHeartbeatInterval(clusterBinding *v1alpha1.ClusterBinding) {
+	if r.heartbeatInterval == 0 {
+		return
+	}
+	clusterBinding.Spec.HeartbeatInterval = &metav1.Duration{Duration: r.heartbeatInterval}
+}
+
 func (r *reconciler) ensureClusterBindingConditions(clusterBinding *v1alpha1.ClusterBinding) {
 	if clusterBinding.Status.LastHeartbeatTime.IsZero() {
 		conditions.MarkFalse(clusterBinding,
@@ -149,6 +173,27 @@ func (r *reconciler) ensureRBACClusterRole(ctx context.Context, clusterBinding *
 			Resources: []string{export.Spec.Names.Plural},
 			Verbs:     []string{"get", "list", "watch", "update", "patch", "delete", "create"},
 		})
+		if export.Spec.FieldOwnership != nil && len(export.Spec.FieldOwnership.ConsumerOwnedPaths) > 0 {
+			expected.Rules = append(expected.Rules, rbacv1.PolicyRule{
+				APIGroups: []string{export.Spec.Group},
+				Resources: []string{export.Spec.Names.Plural + "/status"},
+				Verbs:     []string{"get", "update", "patch"},
+			})
+		}
+	}
+	watchesAllNamespaces := false
+	for _, export := range exports {
+		watchesAllNamespaces = watchesAllNamespaces || kuberesources.WatchesAllNamespaces(export)
+	}
+	if r.scope == v1alpha1.ClusterScope && watchesAllNamespaces {
+		// Events about exported objects are mirrored to the consumer cluster. Usually they are
+		// read in the service namespaces through the namespaced Role, but the konnector watches
+		// them cluster-wide for objects it watches cluster-wide.
+		expected.Rules = append(expected.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"get", "list", "watch"},
+		})
 	}
 
 	if role == nil {
@@ -177,6 +222,7 @@ func (r *reconciler) ensureRBACClusterRoleBinding(ctx context.Context, clusterBi
 		if err := r.deleteClusterRoleBinding(ctx, name); err != nil && !errors.IsNotFound(err) {
 			return fmt.Errorf("failed to delete ClusterRoleBinding %s: %w", name, err)
 		}
+		return nil
 	}
 
 	ns, err := r.getNamespace(clusterBinding.Namespace)
@@ -227,6 +273,159 @@ func (r *reconciler) ensureRBACClusterRoleBinding(ctx context.Context, clusterBi
 	return nil
 }
 
+// ensureRBACConversionProxy allows the konnector to call the conversion webhook services of
+// exported CRDs through the services/proxy subresource. The webhook services usually live
+// outside of the cluster namespace, hence a Role and RoleBinding are created in each namespace
+// of a webhook service, restricted by name. RBAC cannot restrict the proxied path, so the
+// konnector can reach every path on the webhook service port, not only the conversion path.
+func (r *reconciler) ensureRBACConversionProxy(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	name := "kube-binder-" + clusterBinding.Namespace + "-conversion"
+
+	// older versions granted the access cluster-wide
+	if binding, err := r.getClusterRoleBinding(name); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ClusterRoleBinding %s: %w", name, err)
+	} else if binding != nil {
+		if err := r.deleteClusterRoleBinding(ctx, name); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ClusterRoleBinding %s: %w", name, err)
+		}
+	}
+	if role, err := r.getClusterRole(name); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ClusterRole %s: %w", name, err)
+	} else if role != nil {
+		if err := r.deleteClusterRole(ctx, name); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ClusterRole %s: %w", name, err)
+		}
+	}
+
+	exports, err := r.listServiceExports(clusterBinding.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list APIServiceExports: %w", err)
+	}
+	proxyNames := map[string][]string{}
+	for _, export := range exports {
+		if export.Spec.Conversion != nil {
+			serviceNamespace := export.Spec.Conversion.Service.Namespace
+			proxyNames[serviceNamespace] = append(proxyNames[serviceNamespace], helpers.ConversionServiceProxyName(export.Spec.Conversion))
+		}
+	}
+
+	var errs []error
+	roles, err := r.listRoles(labels.SelectorFromSet(labels.Set{kuberesources.ConversionProxyLabelKey: clusterBinding.Namespace}))
+	if err != nil {
+		return fmt.Errorf("failed to list conversion proxy Roles: %w", err)
+	}
+	for _, role := range roles {
+		if _, found := proxyNames[role.Namespace]; found {
+			continue
+		}
+		if err := r.deleteRoleBinding(ctx, role.Namespace, role.Name); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete RoleBinding %s/%s: %w", role.Namespace, role.Name, err))
+			continue
+		}
+		if err := r.deleteRole(ctx, role.Namespace, role.Name); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete Role %s/%s: %w", role.Namespace, role.Name, err))
+		}
+	}
+	if len(proxyNames) == 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	ns, err := r.getNamespace(clusterBinding.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get Namespace %s: %w", clusterBinding.Namespace, err)
+	}
+	objectMeta := metav1.ObjectMeta{
+		Name: name,
+		Labels: map[string]string{
+			kuberesources.ConversionProxyLabelKey: clusterBinding.Namespace,
+		},
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				APIVersion: "v1",
+				Kind:       "Namespace",
+				Name:       clusterBinding.Namespace,
+				Controller: ptr.To(true),
+				UID:        ns.UID,
+			},
+		},
+	}
+	for _, serviceNamespace := range sets.List(sets.KeySet(proxyNames)) {
+		meta := *objectMeta.DeepCopy()
+		meta.Namespace = serviceNamespace
+		if err := r.ensureRBACConversionProxyRole(ctx, clusterBinding, meta, proxyNames[serviceNamespace]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ensureRBACConversionProxyRole grants the konnector access to the given conversion webhook
+// services in the namespace of objectMeta.
+func (r *reconciler) ensureRBACConversionProxyRole(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding, objectMeta metav1.ObjectMeta, proxyNames []string) error {
+	role, err := r.getRole(objectMeta.Namespace, objectMeta.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Role %s/%s: %w", objectMeta.Namespace, objectMeta.Name, err)
+	}
+	expectedRules := []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"services/proxy"},
+			ResourceNames: proxyNames,
+			Verbs:         []string{"create", "get"},
+		},
+	}
+	if role == nil {
+		expected := &rbacv1.Role{
+			ObjectMeta: objectMeta,
+			Rules:      expectedRules,
+		}
+		if _, err := r.createRole(ctx, expected); err != nil {
+			return fmt.Errorf("failed to create Role %s/%s: %w", objectMeta.Namespace, objectMeta.Name, err)
+		}
+	} else if !reflect.DeepEqual(role.Rules, expectedRules) {
+		role = role.DeepCopy()
+		role.Rules = expectedRules
+		if _, err := r.updateRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to update Role %s/%s: %w", objectMeta.Namespace, objectMeta.Name, err)
+		}
+	}
+
+	binding, err := r.getRoleBinding(objectMeta.Namespace, objectMeta.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get RoleBinding %s/%s: %w", objectMeta.Namespace, objectMeta.Name, err)
+	}
+	expectedSubjects := []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Namespace: clusterBinding.Namespace,
+			Name:      kuberesources.ServiceAccountName,
+		},
+	}
+	if binding == nil {
+		expected := &rbacv1.RoleBinding{
+			ObjectMeta: objectMeta,
+			Subjects:   expectedSubjects,
+			RoleRef: rbacv1.RoleRef{
+				Kind:     "Role",
+				Name:     objectMeta.Name,
+				APIGroup: "rbac.authorization.k8s.io",
+			},
+		}
+		if _, err := r.createRoleBinding(ctx, objectMeta.Namespace, expected); err != nil {
+			return fmt.Errorf("failed to create RoleBinding %s/%s: %w", objectMeta.Namespace, objectMeta.Name, err)
+		}
+	} else if !reflect.DeepEqual(binding.Subjects, expectedSubjects) {
+		binding = binding.DeepCopy()
+		binding.Subjects = expectedSubjects
+		if _, err := r.updateRoleBinding(ctx, objectMeta.Namespace, binding); err != nil {
+			return fmt.Errorf("failed to update RoleBinding %s/%s: %w", objectMeta.Namespace, objectMeta.Name, err)
+		}
+	}
+
+	return nil
+}
+
 func (r *reconciler) ensureRBACRoleBinding(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
 	binding, err := r.getRoleBinding(clusterBinding.Namespace, "kube-binder")
 	if err != nil && !errors.IsNotFound(err) {
@@ -0,0 +1,251 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfigrotation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions/kubebind/v1alpha1"
+	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
+	kuberesources "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
+	"go.bytebuilders.dev/kube-bind/pkg/committer"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	controllerName = "kube-bind-example-backend-kubeconfigrotation"
+)
+
+// RotateFunc writes a kubeconfig with fresh credentials into the given kubeconfig Secret.
+type RotateFunc func(ctx context.Context, ns, kubeconfigSecretName string) (*corev1.Secret, error)
+
+// NewController returns a new controller rotating the kubeconfigs of ClusterBindings before
+// their credentials expire.
+func NewController(
+	config *rest.Config,
+	credentials kuberesources.CredentialsType,
+	lifetime time.Duration,
+	rotate RotateFunc,
+	clusterBindingInformer bindinformers.ClusterBindingInformer,
+) (*Controller, error) {
+	queue := workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{
+		Name: controllerName,
+	})
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, controllerName)
+
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Controller{
+		queue: queue,
+
+		clusterBindingLister:  clusterBindingInformer.Lister(),
+		clusterBindingIndexer: clusterBindingInformer.Informer().GetIndexer(),
+
+		reconciler: reconciler{
+			credentials: credentials,
+			lifetime:    lifetime,
+			now:         time.Now,
+
+			getSecret: func(ctx context.Context, ns, name string) (*corev1.Secret, error) {
+				return kubeClient.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+			},
+			rotateKubeconfig: rotate,
+			deleteLegacySASecret: func(ctx context.Context, ns string) error {
+				return kuberesources.DeleteLegacySASecret(ctx, kubeClient, ns, kuberesources.ServiceAccountName)
+			},
+		},
+
+		commit: committer.NewCommitter[*v1alpha1.ClusterBinding, *v1alpha1.ClusterBindingSpec, *v1alpha1.ClusterBindingStatus](
+			func(ns string) committer.Patcher[*v1alpha1.ClusterBinding] {
+				return bindClient.KubeBindV1alpha1().ClusterBindings(ns)
+			},
+		),
+	}
+
+	_, err = clusterBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueClusterBinding(logger, obj)
+		},
+		UpdateFunc: func(old, newObj interface{}) {
+			// heartbeats update the ClusterBinding all the time. Only a changed secret or a renewal
+			// request matter.
+			oldBinding, ok := old.(*v1alpha1.ClusterBinding)
+			if !ok {
+				return
+			}
+			newBinding, ok := newObj.(*v1alpha1.ClusterBinding)
+			if !ok {
+				return
+			}
+			if oldBinding.Spec.KubeconfigSecretRef != newBinding.Spec.KubeconfigSecretRef ||
+				!oldBinding.Status.CredentialsRenewalRequestTime.Equal(newBinding.Status.CredentialsRenewalRequestTime) {
+				c.enqueueClusterBinding(logger, newObj)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+type (
+	Resource   = committer.Resource[*v1alpha1.ClusterBindingSpec, *v1alpha1.ClusterBindingStatus]
+	CommitFunc = func(context.Context, *Resource, *Resource) error
+)
+
+// Controller rotates the kubeconfig Secrets referenced by ClusterBindings, for tokens when a third of
+// the lifetime is left, and for client certificates when the konnector requests renewal. The
+// konnector picks up the new kubeconfig through the ClusterBinding.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	clusterBindingLister  bindlisters.ClusterBindingLister
+	clusterBindingIndexer cache.Indexer
+
+	reconciler
+
+	commit CommitFunc
+}
+
+func (c *Controller) enqueueClusterBinding(logger klog.Logger, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger.V(2).Info("queueing ClusterBinding", "key", key)
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(2).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.process(ctx, key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeueAfter > 0 {
+		logger.V(2).Info("requeueing for next rotation", "after", requeueAfter)
+		c.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, key string) (time.Duration, error) {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return 0, nil // we cannot do anything
+	}
+
+	obj, err := c.clusterBindingLister.ClusterBindings(ns).Get(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	} else if errors.IsNotFound(err) {
+		return 0, nil // nothing to rotate
+	}
+
+	old := obj
+	obj = obj.DeepCopy()
+
+	var errs []error
+	requeueAfter, err := c.reconcile(ctx, obj)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// Regardless of whether reconcile returned an error or not, always try to patch status if needed. Return the
+	// reconciliation error at the end.
+
+	// If the object being reconciled changed as a result, update it.
+	oldResource := &Resource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
+	if err := c.commit(ctx, oldResource, newResource); err != nil {
+		errs = append(errs, err)
+	}
+
+	return requeueAfter, utilerrors.NewAggregate(errs)
+}
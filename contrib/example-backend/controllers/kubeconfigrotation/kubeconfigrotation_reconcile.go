@@ -0,0 +1,119 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfigrotation
+
+import (
+	"context"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	kuberesources "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+type reconciler struct {
+	credentials kuberesources.CredentialsType
+	lifetime    time.Duration
+	now         func() time.Time
+
+	getSecret            func(ctx context.Context, ns, name string) (*corev1.Secret, error)
+	rotateKubeconfig     func(ctx context.Context, ns, name string) (*corev1.Secret, error)
+	deleteLegacySASecret func(ctx context.Context, ns string) error
+}
+
+// reconcile rotates the kubeconfig of the ClusterBinding if due, and returns after how long the
+// ClusterBinding must be reconciled again.
+func (r *reconciler) reconcile(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+
+	ns := clusterBinding.Namespace
+	secret, err := r.getSecret(ctx, ns, clusterBinding.Spec.KubeconfigSecretRef.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	} else if errors.IsNotFound(err) {
+		return 0, nil // the backend writes it when binding
+	}
+
+	now := r.now()
+	expiration, found := kuberesources.TokenExpiration(secret)
+	lifetime := r.credentialsLifetime(secret, expiration)
+	certificate := r.credentials == kuberesources.ClientCertificateCredentials
+	rotate := !found || kuberesources.HasClientCertificate(secret) != certificate
+	if certificate {
+		// the konnector requests renewal when a third of the lifetime is left. If it does not, e.g.
+		// because it is down, the backend renews on its own before the certificate expires.
+		rotate = rotate || clusterBinding.Status.CredentialsRenewalRequestTime != nil || !now.Before(certificateRenewalDue(expiration, lifetime))
+	} else {
+		rotate = rotate || !now.Before(rotationDue(expiration, lifetime))
+	}
+	if rotate {
+		logger.Info("rotating kubeconfig", "secret", secret.Name, "credentials", r.credentials, "expiration", expiration)
+		if secret, err = r.rotateKubeconfig(ctx, ns, secret.Name); err != nil {
+			return 0, err
+		}
+		if expiration, found = kuberesources.TokenExpiration(secret); !found {
+			return 0, nil // cannot happen
+		}
+		lifetime = r.credentialsLifetime(secret, expiration)
+	}
+	clusterBinding.Status.CredentialsExpiration = &metav1.Time{Time: expiration}
+	clusterBinding.Status.CredentialsRenewalRequestTime = nil
+
+	// the legacy token of older backends is revoked once the konnector had the time of a rotation
+	// to switch to the issued credentials.
+	legacyDue := expiration.Add(-lifetime).Add(lifetime / 3)
+	if !now.Before(legacyDue) {
+		if err := r.deleteLegacySASecret(ctx, ns); err != nil {
+			return 0, err
+		}
+	}
+
+	if !certificate {
+		return rotationDue(expiration, lifetime).Sub(now), nil
+	}
+	if now.Before(legacyDue) {
+		return legacyDue.Sub(now), nil
+	}
+	return certificateRenewalDue(expiration, lifetime).Sub(now), nil
+}
+
+// credentialsLifetime returns the lifetime of the credentials in the given kubeconfig Secret. The
+// API server might have capped it below the configured lifetime. Secrets of older backends do not
+// record the issue time, and are assumed to have the configured lifetime.
+func (r *reconciler) credentialsLifetime(secret *corev1.Secret, expiration time.Time) time.Duration {
+	issued, found := kuberesources.TokenIssued(secret)
+	if !found || !issued.Before(expiration) {
+		return r.lifetime
+	}
+	return expiration.Sub(issued)
+}
+
+// rotationDue returns when the kubeconfig with credentials of the given expiration and lifetime must
+// be rotated, i.e. when a third of the lifetime is left.
+func rotationDue(expiration time.Time, lifetime time.Duration) time.Time {
+	return expiration.Add(-lifetime / 3)
+}
+
+// certificateRenewalDue returns when the backend renews a client certificate of the given expiration
+// and lifetime without a renewal request of the konnector, i.e. when a sixth of the lifetime is left.
+func certificateRenewalDue(expiration time.Time, lifetime time.Duration) time.Time {
+	return expiration.Add(-lifetime / 6)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfigrotation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	kuberesources "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func kubeconfigSecret(issued, expiration *time.Time, certificate bool) *corev1.Secret {
+	authInfo := &clientcmdapi.AuthInfo{Token: "token"}
+	if certificate {
+		authInfo = &clientcmdapi.AuthInfo{ClientCertificateData: []byte("cert"), ClientKeyData: []byte("key")}
+	}
+	kubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"provider": {Server: "https://provider"}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"konnector": authInfo},
+		Contexts:       map[string]*clientcmdapi.Context{"default": {Cluster: "provider", AuthInfo: "konnector"}},
+		CurrentContext: "default",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kube-bind-abc",
+			Name:      "kubeconfig",
+		},
+		Data: map[string][]byte{"kubeconfig": kubeconfig},
+	}
+	if expiration != nil {
+		secret.Annotations = map[string]string{
+			kuberesources.TokenExpirationAnnotation: expiration.UTC().Format(time.RFC3339),
+		}
+	}
+	if issued != nil {
+		secret.Annotations[kuberesources.TokenIssuedAnnotation] = issued.UTC().Format(time.RFC3339)
+	}
+	return secret
+}
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	lifetime := 24 * time.Hour
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name             string
+		credentials      kuberesources.CredentialsType
+		secret           *corev1.Secret
+		renewalRequested bool
+		// rotated is the rotated kubeconfig Secret. Defaults to one with the configured lifetime.
+		rotated *corev1.Secret
+
+		wantRotated        bool
+		wantLegacyDeleted  bool
+		wantRequeue        time.Duration
+		wantExpiration     *time.Time
+		wantSecretNotFound bool
+	}{
+		{
+			name:               "no kubeconfig secret yet",
+			credentials:        kuberesources.ServiceAccountTokenCredentials,
+			wantSecretNotFound: true,
+		},
+		{
+			name:           "legacy secret without expiration",
+			credentials:    kuberesources.ServiceAccountTokenCredentials,
+			secret:         kubeconfigSecret(nil, nil, false),
+			wantRotated:    true,
+			wantRequeue:    lifetime * 2 / 3,
+			wantExpiration: at(lifetime),
+		},
+		{
+			name:           "not yet due",
+			credentials:    kuberesources.ServiceAccountTokenCredentials,
+			secret:         kubeconfigSecret(nil, at(lifetime*9/10), false),
+			wantRequeue:    lifetime*9/10 - lifetime/3,
+			wantExpiration: at(lifetime * 9 / 10),
+		},
+		{
+			name:           "due",
+			credentials:    kuberesources.ServiceAccountTokenCredentials,
+			secret:         kubeconfigSecret(nil, at(lifetime/4), false),
+			wantRotated:    true,
+			wantRequeue:    lifetime * 2 / 3,
+			wantExpiration: at(lifetime),
+		},
+		{
+			name:           "due exactly",
+			credentials:    kuberesources.ServiceAccountTokenCredentials,
+			secret:         kubeconfigSecret(nil, at(lifetime/3), false),
+			wantRotated:    true,
+			wantRequeue:    lifetime * 2 / 3,
+			wantExpiration: at(lifetime),
+		},
+		{
+			name:              "legacy token revoked after a third of the lifetime",
+			credentials:       kuberesources.ServiceAccountTokenCredentials,
+			secret:            kubeconfigSecret(nil, at(lifetime/2), false),
+			wantLegacyDeleted: true,
+			wantRequeue:       lifetime/2 - lifetime/3,
+			wantExpiration:    at(lifetime / 2),
+		},
+		{
+			name:           "token kubeconfig in certificate mode",
+			credentials:    kuberesources.ClientCertificateCredentials,
+			secret:         kubeconfigSecret(nil, at(lifetime*9/10), false),
+			wantRotated:    true,
+			wantRequeue:    lifetime / 3,
+			wantExpiration: at(lifetime),
+		},
+		{
+			name:             "certificate renewal requested",
+			credentials:      kuberesources.ClientCertificateCredentials,
+			secret:           kubeconfigSecret(nil, at(lifetime/3), true),
+			renewalRequested: true,
+			wantRotated:      true,
+			wantRequeue:      lifetime / 3,
+			wantExpiration:   at(lifetime),
+		},
+		{
+			name:              "certificate not yet due",
+			credentials:       kuberesources.ClientCertificateCredentials,
+			secret:            kubeconfigSecret(nil, at(lifetime/2), true),
+			wantLegacyDeleted: true,
+			wantRequeue:       lifetime/2 - lifetime/6,
+			wantExpiration:    at(lifetime / 2),
+		},
+		{
+			name:           "certificate close to expiry without renewal request",
+			credentials:    kuberesources.ClientCertificateCredentials,
+			secret:         kubeconfigSecret(nil, at(lifetime/10), true),
+			wantRotated:    true,
+			wantRequeue:    lifetime / 3,
+			wantExpiration: at(lifetime),
+		},
+		{
+			name:              "issued lifetime capped by the API server",
+			credentials:       kuberesources.ServiceAccountTokenCredentials,
+			secret:            kubeconfigSecret(at(-time.Hour), at(time.Hour), false),
+			wantLegacyDeleted: true,
+			wantRequeue:       time.Hour - 2*time.Hour/3,
+			wantExpiration:    at(time.Hour),
+		},
+		{
+			name:           "issued lifetime capped by the API server after rotation",
+			credentials:    kuberesources.ServiceAccountTokenCredentials,
+			secret:         kubeconfigSecret(at(-2*time.Hour), at(10*time.Minute), false),
+			rotated:        kubeconfigSecret(at(0), at(2*time.Hour), false),
+			wantRotated:    true,
+			wantRequeue:    2 * time.Hour * 2 / 3,
+			wantExpiration: at(2 * time.Hour),
+		},
+		{
+			name:              "certificate with capped lifetime not yet due",
+			credentials:       kuberesources.ClientCertificateCredentials,
+			secret:            kubeconfigSecret(at(-time.Hour), at(2*time.Hour), true),
+			wantLegacyDeleted: true,
+			wantRequeue:       2*time.Hour - 3*time.Hour/6,
+			wantExpiration:    at(2 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rotated, legacyDeleted bool
+			r := &reconciler{
+				credentials: tt.credentials,
+				lifetime:    lifetime,
+				now:         func() time.Time { return now },
+
+				getSecret: func(ctx context.Context, ns, name string) (*corev1.Secret, error) {
+					if tt.secret == nil {
+						return nil, errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+					}
+					require.Equal(t, tt.secret.Namespace, ns)
+					require.Equal(t, tt.secret.Name, name)
+					return tt.secret, nil
+				},
+				rotateKubeconfig: func(ctx context.Context, ns, name string) (*corev1.Secret, error) {
+					rotated = true
+					if tt.rotated != nil {
+						return tt.rotated, nil
+					}
+					return kubeconfigSecret(at(0), at(lifetime), tt.credentials == kuberesources.ClientCertificateCredentials), nil
+				},
+				deleteLegacySASecret: func(ctx context.Context, ns string) error {
+					require.Equal(t, "kube-bind-abc", ns)
+					legacyDeleted = true
+					return nil
+				},
+			}
+
+			clusterBinding := &v1alpha1.ClusterBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind-abc", Name: "cluster"},
+				Spec: v1alpha1.ClusterBindingSpec{
+					KubeconfigSecretRef: v1alpha1.LocalSecretKeyRef{Name: "kubeconfig", Key: "kubeconfig"},
+				},
+			}
+			if tt.renewalRequested {
+				clusterBinding.Status.CredentialsRenewalRequestTime = &metav1.Time{Time: now.Add(-time.Minute)}
+			}
+			requeue, err := r.reconcile(context.Background(), clusterBinding)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRotated, rotated, "rotated")
+			require.Equal(t, tt.wantLegacyDeleted, legacyDeleted, "legacy secret deleted")
+			require.Equal(t, tt.wantRequeue, requeue, "requeue")
+
+			if tt.wantSecretNotFound {
+				require.Nil(t, clusterBinding.Status.CredentialsExpiration)
+				return
+			}
+			require.Nil(t, clusterBinding.Status.CredentialsRenewalRequestTime)
+			require.NotNil(t, clusterBinding.Status.CredentialsExpiration)
+			require.True(t, tt.wantExpiration.Equal(clusterBinding.Status.CredentialsExpiration.Time), "expiration %s", clusterBinding.Status.CredentialsExpiration)
+		})
+	}
+}
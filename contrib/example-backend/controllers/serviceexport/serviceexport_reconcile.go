@@ -64,7 +64,7 @@ func (r *reconciler) ensureSchema(ctx context.Context, export *kubebindv1alpha1.
 		return false, r.deleteServiceExport(ctx, export.Namespace, export.Name)
 	}
 
-	expected, err := kubebindhelpers.CRDToServiceExport(crd)
+	expected, err := kubebindhelpers.CRDToServiceExport(crd, kubebindhelpers.RequestedVersions(export))
 	if err != nil {
 		conditions.MarkFalse(
 			export,
@@ -33,6 +33,7 @@ import (
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -55,6 +56,7 @@ func NewController(
 	isolation v1alpha1.Isolation,
 	serviceExportRequestInformer bindinformers.APIServiceExportRequestInformer,
 	serviceExportInformer bindinformers.APIServiceExportInformer,
+	clusterBindingInformer bindinformers.ClusterBindingInformer,
 	crdInformer apiextensionsinformers.CustomResourceDefinitionInformer,
 ) (*Controller, error) {
 	queue := workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{
@@ -99,6 +101,9 @@ func NewController(
 			getServiceExport: func(ns, name string) (*v1alpha1.APIServiceExport, error) {
 				return serviceExportInformer.Lister().APIServiceExports(ns).Get(name)
 			},
+			listClusterBindings: func() ([]*v1alpha1.ClusterBinding, error) {
+				return clusterBindingInformer.Lister().List(labels.Everything())
+			},
 			createServiceExport: func(ctx context.Context, resource *v1alpha1.APIServiceExport) (*v1alpha1.APIServiceExport, error) {
 				return bindClient.KubeBindV1alpha1().APIServiceExports(resource.Namespace).Create(ctx, resource, metav1.CreateOptions{})
 			},
@@ -18,6 +18,7 @@ package serviceexportrequest
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
@@ -38,6 +39,7 @@ type reconciler struct {
 
 	getCRD              func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	getServiceExport    func(ns, name string) (*v1alpha1.APIServiceExport, error)
+	listClusterBindings func() ([]*v1alpha1.ClusterBinding, error)
 	createServiceExport func(ctx context.Context, resource *v1alpha1.APIServiceExport) (*v1alpha1.APIServiceExport, error)
 
 	deleteServiceExportRequest func(ctx context.Context, namespace, name string) error
@@ -85,7 +87,21 @@ func (r *reconciler) ensureExports(ctx context.Context, req *v1alpha1.APIService
 				continue
 			}
 
-			exportSpec, err := helpers.CRDToServiceExport(crd)
+			if unserved := helpers.UnservedVersions(crd, res.Versions); len(unserved) > 0 {
+				conditions.MarkFalse(
+					req,
+					v1alpha1.APIServiceExportRequestConditionExportsReady,
+					"VersionNotServed",
+					conditionsapi.ConditionSeverityError,
+					"CustomResourceDefinition %s does not serve the requested versions %s",
+					name,
+					strings.Join(unserved, ", "),
+				)
+				failure = true
+				break
+			}
+
+			exportSpec, err := helpers.CRDToServiceExport(crd, res.Versions)
 			if err != nil {
 				conditions.MarkFalse(
 					req,
@@ -113,7 +129,42 @@ func (r *reconciler) ensureExports(ctx context.Context, req *v1alpha1.APIService
 					InformerScope:           r.informerScope,
 				},
 			}
-			if exportSpec.Scope == apiextensionsv1.ClusterScoped {
+			if len(res.Versions) > 0 {
+				export.Annotations[v1alpha1.RequestedVersionsAnnotationKey] = strings.Join(res.Versions, ",")
+			}
+			if helpers.IsNamespacedIsolationCRD(crd) {
+				export.Spec.ClusterScopedIsolation = v1alpha1.IsolationNamespaced
+			} else if exportSpec.Scope == apiextensionsv1.ClusterScoped {
+				if r.clusterScopedIsolation == v1alpha1.IsolationNamespaced {
+					conditions.MarkFalse(
+						req,
+						v1alpha1.APIServiceExportRequestConditionExportsReady,
+						"CRDScopeMismatch",
+						conditionsapi.ConditionSeverityError,
+						"CustomResourceDefinition %s must be namespaced and annotated with %s=%s for Namespaced isolation",
+						name,
+						v1alpha1.ConsumerScopeAnnotationKey,
+						apiextensionsv1.ClusterScoped,
+					)
+					failure = true
+					break
+				}
+				if r.clusterScopedIsolation == v1alpha1.IsolationNone {
+					if shared, err := r.isProviderShared(req.Namespace); err != nil {
+						return err
+					} else if shared {
+						conditions.MarkFalse(
+							req,
+							v1alpha1.APIServiceExportRequestConditionExportsReady,
+							"IsolationNoneNotPossible",
+							conditionsapi.ConditionSeverityError,
+							"CustomResourceDefinition %s is cluster-scoped and cannot be exported without isolation to more than one consumer cluster",
+							name,
+						)
+						failure = true
+						break
+					}
+				}
 				export.Spec.ClusterScopedIsolation = r.clusterScopedIsolation
 			}
 
@@ -144,3 +195,17 @@ func (r *reconciler) ensureExports(ctx context.Context, req *v1alpha1.APIService
 
 	return nil
 }
+
+// isProviderShared returns true if ClusterBindings of other consumers than the one in the given namespace exist.
+func (r *reconciler) isProviderShared(ns string) (bool, error) {
+	bindings, err := r.listClusterBindings()
+	if err != nil {
+		return false, err
+	}
+	for _, binding := range bindings {
+		if binding.Namespace != ns {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -33,6 +33,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -107,6 +108,10 @@ func NewController(
 		reconciler: reconciler{
 			scope: scope,
 
+			listServiceExports: func(ns string) ([]*v1alpha1.APIServiceExport, error) {
+				return serviceExportInformer.Lister().APIServiceExports(ns).List(labels.Everything())
+			},
+
 			getNamespace: namespaceInformer.Lister().Get,
 			createNamespace: func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error) {
 				return kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
@@ -124,6 +129,22 @@ func NewController(
 			updateRoleBinding: func(ctx context.Context, crb *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
 				return kubeClient.RbacV1().RoleBindings(crb.Namespace).Update(ctx, crb, metav1.UpdateOptions{})
 			},
+			deleteRoleBinding: func(ctx context.Context, ns, name string) error {
+				return kubeClient.RbacV1().RoleBindings(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+
+			getRole: func(ns, name string) (*rbacv1.Role, error) {
+				return roleInformer.Lister().Roles(ns).Get(name)
+			},
+			createRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Create(ctx, role, metav1.CreateOptions{})
+			},
+			updateRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Update(ctx, role, metav1.UpdateOptions{})
+			},
+			deleteRole: func(ctx context.Context, ns, name string) error {
+				return kubeClient.RbacV1().Roles(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
 		},
 
 		commit: committer.NewCommitter[*v1alpha1.APIServiceNamespace, *v1alpha1.APIServiceNamespaceSpec, *v1alpha1.APIServiceNamespaceStatus](
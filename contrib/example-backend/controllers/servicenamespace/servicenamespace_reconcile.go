@@ -33,6 +33,8 @@ import (
 type reconciler struct {
 	scope v1alpha1.Scope
 
+	listServiceExports func(ns string) ([]*v1alpha1.APIServiceExport, error)
+
 	getNamespace    func(name string) (*corev1.Namespace, error)
 	createNamespace func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error)
 	deleteNamespace func(ctx context.Context, name string) error
@@ -40,6 +42,12 @@ type reconciler struct {
 	getRoleBinding    func(ns, name string) (*rbacv1.RoleBinding, error)
 	createRoleBinding func(ctx context.Context, crb *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 	updateRoleBinding func(ctx context.Context, cr *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
+	deleteRoleBinding func(ctx context.Context, ns, name string) error
+
+	getRole    func(ns, name string) (*rbacv1.Role, error)
+	createRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+	updateRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+	deleteRole func(ctx context.Context, ns, name string) error
 }
 
 func (c *reconciler) reconcile(ctx context.Context, sns *v1alpha1.APIServiceNamespace) error {
@@ -68,6 +76,9 @@ func (c *reconciler) reconcile(ctx context.Context, sns *v1alpha1.APIServiceName
 			return fmt.Errorf("failed to ensure RBAC: %w", err)
 		}
 	}
+	if err := c.ensureRBACNamespacedRole(ctx, nsName, sns); err != nil {
+		return fmt.Errorf("failed to ensure RBAC: %w", err)
+	}
 
 	if sns.Status.Namespace != nsName {
 		sns.Status.Namespace = nsName
@@ -117,3 +128,84 @@ func (c *reconciler) ensureRBACRoleBinding(ctx context.Context, ns string, sns *
 
 	return nil
 }
+
+// ensureRBACNamespacedRole grants the konnector access to the Secrets and ConfigMaps its objects need
+// in the service namespace, in every scope. Without any such need, the Role and RoleBinding are deleted.
+func (c *reconciler) ensureRBACNamespacedRole(ctx context.Context, ns string, sns *v1alpha1.APIServiceNamespace) error {
+	objName := kuberesources.NamespacedRoleName
+
+	exports, err := c.listServiceExports(sns.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list APIServiceExports: %w", err)
+	}
+	expectedRules := kuberesources.NamespacedRules(exports)
+	if len(expectedRules) == 0 {
+		if err := c.deleteRoleBinding(ctx, ns, objName); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete role binding %s/%s: %w", ns, objName, err)
+		}
+		if err := c.deleteRole(ctx, ns, objName); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete role %s/%s: %w", ns, objName, err)
+		}
+		return nil
+	}
+
+	role, err := c.getRole(ns, objName)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get role %s/%s: %w", ns, objName, err)
+	}
+	if role == nil {
+		expected := &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      objName,
+				Namespace: ns,
+			},
+			Rules: expectedRules,
+		}
+		if _, err := c.createRole(ctx, expected); err != nil {
+			return fmt.Errorf("failed to create role %s/%s: %w", ns, objName, err)
+		}
+	} else if !reflect.DeepEqual(role.Rules, expectedRules) {
+		role = role.DeepCopy()
+		role.Rules = expectedRules
+		if _, err := c.updateRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to update role %s/%s: %w", ns, objName, err)
+		}
+	}
+
+	binding, err := c.getRoleBinding(ns, objName)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get role binding %s/%s: %w", ns, objName, err)
+	}
+	expected := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      objName,
+			Namespace: ns,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Namespace: sns.Namespace,
+				Name:      kuberesources.ServiceAccountName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "Role",
+			Name:     objName,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+	if binding == nil {
+		if _, err := c.createRoleBinding(ctx, expected); err != nil {
+			return fmt.Errorf("failed to create role binding %s/%s: %w", ns, objName, err)
+		}
+	} else if !reflect.DeepEqual(binding.Subjects, expected.Subjects) {
+		binding = binding.DeepCopy()
+		binding.Subjects = expected.Subjects
+		// roleRef is immutable
+		if _, err := c.updateRoleBinding(ctx, binding); err != nil {
+			return fmt.Errorf("failed to update role binding %s/%s: %w", ns, objName, err)
+		}
+	}
+
+	return nil
+}
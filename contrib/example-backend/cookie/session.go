@@ -40,6 +40,13 @@ type SessionState struct {
 	ClusterID   string `msgpack:"ci,omitempty"`
 }
 
+// AuthFlowState is kept in the browser during the OAuth2 authorization, binding
+// the callback to the browser that started the flow.
+type AuthFlowState struct {
+	Nonce        string `msgpack:"n,omitempty"`
+	CodeVerifier string `msgpack:"cv,omitempty"`
+}
+
 func (s *SessionState) Encode() ([]byte, error) {
 	return msgpack.Marshal(s)
 }
@@ -0,0 +1,253 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes"
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
+
+	"golang.org/x/time/rate"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+// handleBindToken binds the resources of a pre-issued bind token, passed as bearer token, without
+// any login. Every use is counted and recorded on the bind token Secret.
+func (h *handler) handleBindToken(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+	ctx := r.Context()
+
+	prepareNoCache(w)
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		http.Error(w, "missing bind token", http.StatusUnauthorized)
+		return
+	}
+	sessionID, clusterID := r.FormValue("s"), r.FormValue("c")
+	if sessionID == "" || clusterID == "" {
+		http.Error(w, "missing session id or cluster id", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	if !h.bindTokenLimiter.allow(r.RemoteAddr, now) {
+		logger.Info("rate limited bind token request", "remoteAddr", r.RemoteAddr)
+		w.Header().Set("Retry-After", strconv.Itoa(int(bindTokenRateInterval.Seconds())))
+		http.Error(w, "too many bind token requests, try again later", http.StatusTooManyRequests)
+		return
+	}
+
+	bindToken, err := h.kubeManager.LookupBindToken(h.bindTokenNamespace, token, now)
+	if err != nil {
+		h.bindTokenError(w, logger, err)
+		return
+	}
+	logger = logger.WithValues("bindToken", bindToken.Name, "consumer", bindToken.Consumer)
+
+	var requests []runtime.RawExtension
+	var names []string
+	for _, gr := range bindToken.Resources {
+		request, err := h.exportRequest(gr)
+		if err != nil {
+			logger.Error(err, "bind token resource cannot be bound")
+			http.Error(w, "bind token resources cannot be bound", http.StatusInternalServerError)
+			return
+		}
+		requestBytes, err := json.Marshal(request)
+		if err != nil {
+			logger.Error(err, "failed to marshal request")
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		requests = append(requests, runtime.RawExtension{Raw: requestBytes})
+		names = append(names, request.ObjectMeta.Name)
+	}
+
+	// the use is recorded before binding to not exceed the maximal uses with concurrent requests,
+	// and taken back if binding fails.
+	use := resources.BindTokenUse{
+		Time:       metav1.NewTime(now),
+		ClusterID:  clusterID,
+		RemoteAddr: r.RemoteAddr,
+	}
+	if bindToken, err = h.kubeManager.RecordBindTokenUse(ctx, h.bindTokenNamespace, bindToken.Name, use); err != nil {
+		h.bindTokenError(w, logger, err)
+		return
+	}
+
+	// the identity is prefixed to not collide with the OIDC subjects of interactive consumers.
+	identity := "bind-token:" + bindToken.Consumer + "#" + clusterID
+	kfg, err := h.kubeManager.HandleResources(ctx, identity, strings.Join(names, ","), "")
+	if err != nil {
+		if err := h.kubeManager.RevertBindTokenUse(ctx, h.bindTokenNamespace, bindToken.Name, use); err != nil {
+			logger.Error(err, "failed to revert bind token use")
+		}
+	}
+	if errors.Is(err, kubernetes.ErrBoundWithoutIsolation) {
+		logger.Info("refused binding", "reason", err.Error())
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		logger.Error(err, "failed to handle resources")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	response := v1alpha1.BindingResponse{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "BindingResponse",
+		},
+		Authentication: v1alpha1.BindingResponseAuthentication{
+			BindToken: &v1alpha1.BindingResponseAuthenticationBindToken{
+				SessionID: sessionID,
+				Consumer:  bindToken.Consumer,
+			},
+		},
+		Kubeconfig: kfg,
+		Requests:   requests,
+	}
+	payload, err := json.Marshal(&response)
+	if err != nil {
+		logger.Error(err, "failed to marshal binding response")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(payload) // nolint:errcheck
+}
+
+const (
+	// bindTokenRateInterval is the interval at which a client gains another bind token request.
+	bindTokenRateInterval = 6 * time.Second
+	// bindTokenRateBurst is the number of bind token requests a client may send at once.
+	bindTokenRateBurst = 10
+	// bindTokenLimiterIdle is the time after which the limiter of an idle client is dropped.
+	bindTokenLimiterIdle = 10 * time.Minute
+)
+
+// clientRateLimiters limits the bind token requests per client address, to slow down guessing
+// tokens. Clients behind the same proxy share a limit.
+type clientRateLimiters struct {
+	lock     sync.Mutex
+	limiters map[string]*clientRateLimiter
+}
+
+type clientRateLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newClientRateLimiters() *clientRateLimiters {
+	return &clientRateLimiters{
+		limiters: map[string]*clientRateLimiter{},
+	}
+}
+
+// allow returns whether the client with the given remote address may send a request now.
+func (l *clientRateLimiters) allow(remoteAddr string, now time.Time) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for addr, limiter := range l.limiters {
+		if now.Sub(limiter.lastSeen) > bindTokenLimiterIdle {
+			delete(l.limiters, addr)
+		}
+	}
+
+	limiter, found := l.limiters[host]
+	if !found {
+		limiter = &clientRateLimiter{limiter: rate.NewLimiter(rate.Every(bindTokenRateInterval), bindTokenRateBurst)}
+		l.limiters[host] = limiter
+	}
+	limiter.lastSeen = now
+	return limiter.limiter.AllowN(now, 1)
+}
+
+// exportRequest returns the APIServiceExportRequest for an exported CRD, with all served versions.
+func (h *handler) exportRequest(gr v1alpha1.GroupResource) (*v1alpha1.APIServiceExportRequestResponse, error) {
+	name := gr.Resource + "." + gr.Group
+	crd, err := h.apiextensionsLister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if crd.Labels[resources.ExportedCRDsLabel] != "true" {
+		return nil, fmt.Errorf("CRD %s is not exported", name)
+	}
+	if h.scope != v1alpha1.ClusterScope && crd.Spec.Scope != apiextensionsv1.NamespaceScoped {
+		return nil, fmt.Errorf("CRD %s is cluster-scoped", name)
+	}
+
+	var versions []string
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			versions = append(versions, v.Name)
+		}
+	}
+
+	return &v1alpha1.APIServiceExportRequestResponse{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "APIServiceExportRequest",
+		},
+		ObjectMeta: v1alpha1.NameObjectMeta{
+			Name: name,
+		},
+		Spec: v1alpha1.APIServiceExportRequestSpec{
+			Resources: []v1alpha1.APIServiceExportRequestResource{
+				{GroupResource: gr, Versions: versions},
+			},
+		},
+	}, nil
+}
+
+func (h *handler) bindTokenError(w http.ResponseWriter, logger klog.Logger, err error) {
+	switch {
+	case errors.Is(err, resources.ErrBindTokenInvalid):
+		logger.Info("rejected invalid bind token")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, resources.ErrBindTokenExpired), errors.Is(err, resources.ErrBindTokenExhausted):
+		logger.Info("rejected bind token", "reason", err.Error())
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case apierrors.IsConflict(err):
+		http.Error(w, "bind token is in use, try again", http.StatusConflict)
+	default:
+		logger.Error(err, "failed to use bind token")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
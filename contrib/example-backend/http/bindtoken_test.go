@@ -0,0 +1,41 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRateLimiters(t *testing.T) {
+	limiters := newClientRateLimiters()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for i := 0; i < bindTokenRateBurst; i++ {
+		require.True(t, limiters.allow("10.0.0.1:1234", now), "request %d", i)
+	}
+	require.False(t, limiters.allow("10.0.0.1:5678", now), "other port of the same client")
+	require.True(t, limiters.allow("10.0.0.2:1234", now), "other client")
+
+	require.True(t, limiters.allow("10.0.0.1:1234", now.Add(bindTokenRateInterval)))
+	require.False(t, limiters.allow("10.0.0.1:1234", now.Add(bindTokenRateInterval)))
+
+	limiters.allow("10.0.0.3:1234", now.Add(bindTokenLimiterIdle+time.Minute))
+	require.Len(t, limiters.limiters, 1, "idle clients are dropped")
+}
@@ -0,0 +1,298 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	htmltemplate "html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/template"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// deviceCodeTTL is how long the user has to enter the user code and to bind.
+	deviceCodeTTL = 10 * time.Minute
+	// devicePollInterval is the minimal interval between polls of the token endpoint.
+	devicePollInterval = 5 * time.Second
+
+	// deviceRedirectPrefix marks the redirect url of a bind flow started through the device
+	// verification page. Instead of redirecting, the BindingResponse is handed to the poller.
+	deviceRedirectPrefix = "urn:kube-bind:device:"
+
+	// userCodeAlphabet avoids vowels and look-alike characters.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+)
+
+var deviceTemplate = htmltemplate.Must(htmltemplate.New("device").Parse(mustRead(template.Files.ReadFile, "device.gohtml")))
+
+// deviceFlow is a pending OAuth2 device authorization grant.
+type deviceFlow struct {
+	deviceCode string
+	userCode   string
+	sessionID  string
+	clusterID  string
+
+	expiry   time.Time
+	lastPoll time.Time
+	response []byte
+}
+
+// deviceFlows keeps the pending device flows in memory. With multiple replicas of the
+// backend, the device endpoints need session affinity.
+type deviceFlows struct {
+	lock         sync.Mutex
+	byDeviceCode map[string]*deviceFlow
+	byUserCode   map[string]*deviceFlow
+}
+
+func newDeviceFlows() *deviceFlows {
+	return &deviceFlows{
+		byDeviceCode: map[string]*deviceFlow{},
+		byUserCode:   map[string]*deviceFlow{},
+	}
+}
+
+func (f *deviceFlows) start(sessionID, clusterID string, now time.Time) (*deviceFlow, error) {
+	deviceCode, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for code, flow := range f.byDeviceCode {
+		if now.After(flow.expiry) {
+			delete(f.byDeviceCode, code)
+			delete(f.byUserCode, flow.userCode)
+		}
+	}
+
+	var userCode string
+	for userCode == "" || f.byUserCode[userCode] != nil {
+		if userCode, err = newUserCode(); err != nil {
+			return nil, err
+		}
+	}
+
+	flow := &deviceFlow{
+		deviceCode: deviceCode,
+		userCode:   userCode,
+		sessionID:  sessionID,
+		clusterID:  clusterID,
+		expiry:     now.Add(deviceCodeTTL),
+	}
+	f.byDeviceCode[deviceCode] = flow
+	f.byUserCode[userCode] = flow
+	return flow, nil
+}
+
+// lookup returns the pending flow of the user code, if not expired.
+func (f *deviceFlows) lookup(userCode string, now time.Time) (*deviceFlow, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	flow, found := f.byUserCode[normalizeUserCode(userCode)]
+	if !found || now.After(flow.expiry) {
+		return nil, false
+	}
+	return flow, true
+}
+
+// complete hands the BindingResponse to the pending flow of the user code, if it belongs to the
+// given session.
+func (f *deviceFlows) complete(userCode, sessionID string, response []byte, now time.Time) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	flow, found := f.byUserCode[userCode]
+	if !found || now.After(flow.expiry) || flow.sessionID != sessionID {
+		return false
+	}
+	flow.response = response
+	return true
+}
+
+// poll returns the BindingResponse of the flow of the device code, or an RFC 8628 error code.
+func (f *deviceFlows) poll(deviceCode string, now time.Time) ([]byte, string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	flow, found := f.byDeviceCode[deviceCode]
+	if !found {
+		return nil, "invalid_grant"
+	}
+	if now.After(flow.expiry) {
+		delete(f.byDeviceCode, deviceCode)
+		delete(f.byUserCode, flow.userCode)
+		return nil, "expired_token"
+	}
+	if flow.response != nil {
+		delete(f.byDeviceCode, deviceCode)
+		delete(f.byUserCode, flow.userCode)
+		return flow.response, ""
+	}
+	if now.Sub(flow.lastPoll) < devicePollInterval {
+		flow.lastPoll = now
+		return nil, "slow_down"
+	}
+	flow.lastPoll = now
+	return nil, "authorization_pending"
+}
+
+func newUserCode() (string, error) {
+	var sb strings.Builder
+	for i := 0; i < 8; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// normalizeUserCode accepts user codes typed in lower case and with separators.
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return code
+}
+
+// formatUserCode formats the user code as XXXX-XXXX for display.
+func formatUserCode(code string) string {
+	return code[:4] + "-" + code[4:]
+}
+
+// backendURL returns the url of the given backend path, next to the authorize url.
+func (h *handler) backendURL(r *http.Request, path string) string {
+	if h.oidcAuthorizeURL == "" {
+		return "http://" + r.Host + "/" + path
+	}
+	base, err := url.Parse(h.oidcAuthorizeURL)
+	if err != nil {
+		return "http://" + r.Host + "/" + path
+	}
+	return base.ResolveReference(&url.URL{Path: path}).String()
+}
+
+// handleDeviceAuthorization starts a device flow for the session and cluster of the consumer.
+func (h *handler) handleDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	sessionID, clusterID := r.FormValue("s"), r.FormValue("c")
+	if sessionID == "" || clusterID == "" {
+		http.Error(w, "missing session id or cluster id", http.StatusBadRequest)
+		return
+	}
+
+	flow, err := h.devices.start(sessionID, clusterID, time.Now())
+	if err != nil {
+		logger.Error(err, "failed to start device flow")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	verificationURI := h.backendURL(r, "device")
+	bs, err := json.Marshal(&v1alpha1.OAuth2DeviceAuthorization{
+		DeviceCode:              flow.deviceCode,
+		UserCode:                formatUserCode(flow.userCode),
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + formatUserCode(flow.userCode),
+		ExpiresIn:               int64(deviceCodeTTL.Seconds()),
+		Interval:                int64(devicePollInterval.Seconds()),
+	})
+	if err != nil {
+		logger.Error(err, "failed to marshal device authorization")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+// handleDeviceVerification asks the user for the user code, and continues with the usual bind
+// flow for the session of the device flow.
+func (h *handler) handleDeviceVerification(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	userCode := r.URL.Query().Get("user_code")
+	if userCode == "" {
+		h.renderDevice(w, logger, "", false, http.StatusOK)
+		return
+	}
+	flow, found := h.devices.lookup(userCode, time.Now())
+	if !found {
+		h.renderDevice(w, logger, "The code is invalid or expired.", false, http.StatusBadRequest)
+		return
+	}
+
+	values := url.Values{}
+	values.Set("u", deviceRedirectPrefix+flow.userCode)
+	values.Set("s", flow.sessionID)
+	values.Set("c", flow.clusterID)
+	http.Redirect(w, r, h.backendURL(r, "authorize")+"?"+values.Encode(), http.StatusFound)
+}
+
+// handleDeviceToken returns the BindingResponse to the polling consumer once the user has bound.
+func (h *handler) handleDeviceToken(w http.ResponseWriter, r *http.Request) {
+	prepareNoCache(w)
+
+	response, errCode := h.devices.poll(r.FormValue("device_code"), time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	if errCode != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": errCode}) // nolint:errcheck
+		return
+	}
+	w.Write(response) // nolint:errcheck
+}
+
+func (h *handler) renderDevice(w http.ResponseWriter, logger klog.Logger, message string, done bool, status int) {
+	bs := bytes.Buffer{}
+	if err := deviceTemplate.Execute(&bs, struct {
+		Message string
+		Done    bool
+	}{
+		Message: message,
+		Done:    done,
+	}); err != nil {
+		logger.Error(err, "failed to execute template")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(status)
+	w.Write(bs.Bytes()) // nolint:errcheck
+}
@@ -18,6 +18,7 @@ package http
 
 import (
 	"bytes"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -38,6 +39,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/securecookie"
+	"golang.org/x/oauth2"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,6 +70,11 @@ type handler struct {
 	cookieEncryptionKey []byte
 	cookieSigningKey    []byte
 
+	devices *deviceFlows
+
+	bindTokenNamespace string
+	bindTokenLimiter   *clientRateLimiters
+
 	client              *http.Client
 	apiextensionsLister apiextensionslisters.CustomResourceDefinitionLister
 	kubeManager         *kubernetes.Manager
@@ -75,7 +82,7 @@ type handler struct {
 
 func NewHandler(
 	provider *OIDCServiceProvider,
-	oidcAuthorizeURL, backendCallbackURL, providerPrettyName, testingAutoSelect string,
+	oidcAuthorizeURL, backendCallbackURL, providerPrettyName, testingAutoSelect, bindTokenNamespace string,
 	cookieSigningKey, cookieEncryptionKey []byte,
 	scope v1alpha1.Scope,
 	mgr *kubernetes.Manager,
@@ -88,6 +95,9 @@ func NewHandler(
 		providerPrettyName:  providerPrettyName,
 		testingAutoSelect:   testingAutoSelect,
 		scope:               scope,
+		devices:             newDeviceFlows(),
+		bindTokenNamespace:  bindTokenNamespace,
+		bindTokenLimiter:    newClientRateLimiters(),
 		client:              http.DefaultClient,
 		kubeManager:         mgr,
 		apiextensionsLister: apiextensionsLister,
@@ -102,6 +112,12 @@ func (h *handler) AddRoutes(mux *mux.Router) {
 	mux.HandleFunc("/bind", h.handleBind).Methods("GET")
 	mux.HandleFunc("/authorize", h.handleAuthorize).Methods("GET")
 	mux.HandleFunc("/callback", h.handleCallback).Methods("GET")
+	mux.HandleFunc("/device/authorize", h.handleDeviceAuthorization).Methods("POST")
+	mux.HandleFunc("/device/token", h.handleDeviceToken).Methods("POST")
+	mux.HandleFunc("/device", h.handleDeviceVerification).Methods("GET")
+	if h.bindTokenNamespace != "" {
+		mux.HandleFunc("/bind-token", h.handleBindToken).Methods("POST")
+	}
 }
 
 func (h *handler) handleServiceExport(w http.ResponseWriter, r *http.Request) {
@@ -126,8 +142,23 @@ func (h *handler) handleServiceExport(w http.ResponseWriter, r *http.Request) {
 					AuthenticatedURL: oidcAuthorizeURL,
 				},
 			},
+			{
+				Method: "OAuth2DeviceGrant",
+				OAuth2DeviceGrant: &v1alpha1.OAuth2DeviceGrant{
+					DeviceAuthorizationURL: h.backendURL(r, "device/authorize"),
+					TokenURL:               h.backendURL(r, "device/token"),
+				},
+			},
 		},
 	}
+	if h.bindTokenNamespace != "" {
+		provider.AuthenticationMethods = append(provider.AuthenticationMethods, v1alpha1.AuthenticationMethod{
+			Method: "BindToken",
+			BindToken: &v1alpha1.BindToken{
+				BindURL: h.backendURL(r, "bind-token"),
+			},
+		})
+	}
 
 	bs, err := json.Marshal(provider)
 	if err != nil {
@@ -166,18 +197,44 @@ func (h *handler) handleAuthorize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dataCode, err := json.Marshal(code)
+	nonce, err := newNonce()
 	if err != nil {
-		logger.Info("failed to marshal auth code", "error", err)
+		logger.Info("failed to generate nonce", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	code.Nonce = nonce
+	code.Expiry = time.Now().Add(stateTTL).Unix()
+	state, err := signState(h.cookieSigningKey, code)
+	if err != nil {
+		logger.Info("failed to sign state", "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	encoded := base64.URLEncoding.EncodeToString(dataCode)
-	authURL := h.oidc.OIDCProviderConfig(scopes).AuthCodeURL(encoded)
+	// the PKCE verifier never leaves the browser and the backend
+	flow := cookie.AuthFlowState{
+		Nonce:        nonce,
+		CodeVerifier: oauth2.GenerateVerifier(),
+	}
+	cookieName := authFlowCookieName(code.SessionID)
+	s := securecookie.New(h.cookieSigningKey, h.cookieEncryptionKey)
+	encoded, err := s.Encode(cookieName, flow)
+	if err != nil {
+		logger.Info("failed to encode secure auth flow cookie", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, cookie.MakeCookie(r, cookieName, encoded, stateTTL))
+
+	authURL := h.oidc.OIDCProviderConfig(scopes).AuthCodeURL(state, oauth2.S256ChallengeOption(flow.CodeVerifier))
 	http.Redirect(w, r, authURL, http.StatusFound)
 }
 
+func authFlowCookieName(sessionID string) string {
+	return "kube-bind-auth-" + sessionID
+}
+
 func parseJWT(p string) ([]byte, error) {
 	parts := strings.Split(p, ".")
 	if len(parts) < 2 {
@@ -213,22 +270,35 @@ func (h *handler) handleCallback(w http.ResponseWriter, r *http.Request) {
 	if state == "" {
 		state = r.URL.Query().Get("state")
 	}
-	decoded, err := base64.StdEncoding.DecodeString(state)
+	authCode, err := verifyState(h.cookieSigningKey, state, time.Now())
 	if err != nil {
-		logger.Info("failed to decode state", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		logger.Info("invalid state", "error", err)
+		http.Error(w, "invalid state: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	authCode := &AuthCode{}
-	if err := json.Unmarshal(decoded, authCode); err != nil {
-		logger.Info("faile to unmarshal authCode", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+
+	flowCookieName := authFlowCookieName(authCode.SessionID)
+	ck, err := r.Cookie(flowCookieName)
+	if err != nil {
+		logger.Info("failed to get auth flow cookie", "error", err)
+		http.Error(w, "authorization was not started in this browser", http.StatusBadRequest)
 		return
 	}
+	flow := cookie.AuthFlowState{}
+	if err := securecookie.New(h.cookieSigningKey, h.cookieEncryptionKey).Decode(flowCookieName, ck.Value, &flow); err != nil {
+		logger.Info("failed to decode auth flow cookie", "error", err)
+		http.Error(w, "authorization was not started in this browser", http.StatusBadRequest)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(flow.Nonce), []byte(authCode.Nonce)) != 1 {
+		logger.Info("state nonce does not match auth flow cookie")
+		http.Error(w, "invalid state: nonce mismatch", http.StatusBadRequest)
+		return
+	}
+	// the flow is single-use
+	http.SetCookie(w, cookie.MakeCookie(r, flowCookieName, "", -time.Hour))
 
-	// TODO: sign state and verify that it is not faked by the oauth provider
-
-	token, err := h.oidc.OIDCProviderConfig(nil).Exchange(r.Context(), code)
+	token, err := h.oidc.OIDCProviderConfig(nil).Exchange(r.Context(), code, oauth2.VerifierOption(flow.CodeVerifier))
 	if err != nil {
 		logger.Info("failed to exchange token", "error", err)
 		http.Error(w, "internal error", http.StatusInternalServerError)
@@ -352,8 +422,13 @@ func (h *handler) handleBind(w http.ResponseWriter, r *http.Request) {
 
 	group := r.URL.Query().Get("group")
 	resource := r.URL.Query().Get("resource")
+	versions := r.URL.Query()["version"]
 	kfg, err := h.kubeManager.HandleResources(r.Context(), idToken.Subject+"#"+state.ClusterID, resource, group)
-	if err != nil {
+	if errors.Is(err, kubernetes.ErrBoundWithoutIsolation) {
+		logger.Info("refused binding", "reason", err.Error())
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
 		logger.Error(err, "failed to handle resources")
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -372,7 +447,7 @@ func (h *handler) handleBind(w http.ResponseWriter, r *http.Request) {
 		},
 		Spec: v1alpha1.APIServiceExportRequestSpec{
 			Resources: []v1alpha1.APIServiceExportRequestResource{
-				{GroupResource: v1alpha1.GroupResource{Group: group, Resource: resource}},
+				{GroupResource: v1alpha1.GroupResource{Group: group, Resource: resource}, Versions: versions},
 			},
 		},
 	}
@@ -405,6 +480,16 @@ func (h *handler) handleBind(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if userCode, found := strings.CutPrefix(state.RedirectURL, deviceRedirectPrefix); found {
+		if !h.devices.complete(userCode, state.SessionID, payload, time.Now()) {
+			h.renderDevice(w, logger, "The code is invalid or expired. Please run kubectl bind again.", true, http.StatusBadRequest)
+			return
+		}
+		logger.V(1).Info("handing binding response to device flow")
+		h.renderDevice(w, logger, "Successfully bound. You can return to kubectl bind.", true, http.StatusOK)
+		return
+	}
+
 	encoded := base64.URLEncoding.EncodeToString(payload)
 
 	parsedAuthURL, err := url.Parse(state.RedirectURL)
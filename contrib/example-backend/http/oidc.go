@@ -18,6 +18,10 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	oidc "github.com/coreos/go-oidc"
 	"golang.org/x/oauth2"
@@ -25,10 +29,14 @@ import (
 
 // AuthCode is sent and received by to/from the OIDC provider. It's the state
 // we can use to map the OIDC provider's response to the request from the client.
+// It is signed, and its nonce must match the AuthFlowState cookie of the browser
+// that started the flow.
 type AuthCode struct {
 	RedirectURL string `json:"redirectURL"`
 	SessionID   string `json:"sid"`
 	ClusterID   string `json:"cid"`
+	Nonce       string `json:"nonce"`
+	Expiry      int64  `json:"exp"`
 }
 
 type OIDCServiceProvider struct {
@@ -66,3 +74,23 @@ func (o *OIDCServiceProvider) OIDCProviderConfig(scopes []string) *oauth2.Config
 		Scopes:       scopes,
 	}
 }
+
+// CheckDiscovery verifies that the OIDC discovery document of the issuer is reachable.
+func (o *OIDCServiceProvider) CheckDiscovery(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(o.issuerURL, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach OIDC issuer %q: %w", o.issuerURL, err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OIDC discovery of issuer %q returned %s", o.issuerURL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// stateTTL is how long the user has to log in at the OIDC provider.
+const stateTTL = 10 * time.Minute
+
+// signState encodes the AuthCode as OAuth2 state, HMAC-signed with the given key.
+func signState(key []byte, code *AuthCode) (string, error) {
+	payload, err := json.Marshal(code)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(stateMAC(key, encoded)), nil
+}
+
+// verifyState decodes the AuthCode from OAuth2 state, verifying its signature and expiry.
+func verifyState(key []byte, state string, now time.Time) (*AuthCode, error) {
+	encoded, signature, found := strings.Cut(state, ".")
+	if !found {
+		return nil, errors.New("state is not signed")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("malformed state signature: %w", err)
+	}
+	if !hmac.Equal(mac, stateMAC(key, encoded)) {
+		return nil, errors.New("invalid state signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed state: %w", err)
+	}
+	code := &AuthCode{}
+	if err := json.Unmarshal(payload, code); err != nil {
+		return nil, fmt.Errorf("malformed state: %w", err)
+	}
+	if code.Nonce == "" {
+		return nil, errors.New("state has no nonce")
+	}
+	if now.Unix() > code.Expiry {
+		return nil, errors.New("state expired")
+	}
+	return code, nil
+}
+
+func stateMAC(key []byte, encoded string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("kube-bind-state:" + encoded)) // nolint:errcheck
+	return mac.Sum(nil)
+}
+
+// newNonce returns a random URL-safe string.
+func newNonce() (string, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bs), nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/cookie"
+
+	"github.com/gorilla/securecookie"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyState(t *testing.T) {
+	key := []byte("signing-key")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	code := &AuthCode{
+		RedirectURL: "http://localhost:1234/callback",
+		SessionID:   "session",
+		ClusterID:   "cluster",
+		Nonce:       "nonce",
+		Expiry:      now.Add(stateTTL).Unix(),
+	}
+	state, err := signState(key, code)
+	require.NoError(t, err)
+	encoded, signature, _ := strings.Cut(state, ".")
+
+	sign := func(code *AuthCode) string {
+		state, err := signState(key, code)
+		require.NoError(t, err)
+		return state
+	}
+
+	tests := []struct {
+		name    string
+		key     []byte
+		state   string
+		now     time.Time
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			state: state,
+		},
+		{
+			name:  "valid until expiry",
+			state: state,
+			now:   now.Add(stateTTL),
+		},
+		{
+			name:    "expired",
+			state:   state,
+			now:     now.Add(stateTTL + time.Second),
+			wantErr: "state expired",
+		},
+		{
+			name:    "missing signature",
+			state:   encoded,
+			wantErr: "state is not signed",
+		},
+		{
+			name:    "empty signature",
+			state:   encoded + ".",
+			wantErr: "invalid state signature",
+		},
+		{
+			name:    "malformed signature",
+			state:   encoded + ".!!",
+			wantErr: "malformed state signature",
+		},
+		{
+			name: "tampered payload",
+			state: base64.RawURLEncoding.EncodeToString([]byte(
+				`{"redirectURL":"https://attacker.example.com/callback","sid":"session","cid":"cluster","nonce":"nonce","exp":`+strconv.FormatInt(code.Expiry, 10)+`}`,
+			)) + "." + signature,
+			wantErr: "invalid state signature",
+		},
+		{
+			name:    "wrong key",
+			key:     []byte("other-key"),
+			state:   state,
+			wantErr: "invalid state signature",
+		},
+		{
+			name: "missing nonce",
+			state: sign(&AuthCode{
+				RedirectURL: code.RedirectURL,
+				SessionID:   code.SessionID,
+				ClusterID:   code.ClusterID,
+				Expiry:      code.Expiry,
+			}),
+			wantErr: "state has no nonce",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifyKey := key
+			if tt.key != nil {
+				verifyKey = tt.key
+			}
+			verifyNow := now
+			if !tt.now.IsZero() {
+				verifyNow = tt.now
+			}
+
+			got, err := verifyState(verifyKey, tt.state, verifyNow)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, code, got)
+		})
+	}
+}
+
+func TestCallbackAuthFlowCookie(t *testing.T) {
+	signingKey := []byte("signing-key-of-32-bytes-length!!")
+	encryptionKey := []byte("encryption-key-of-32-bytes-long!")
+	h := &handler{
+		cookieSigningKey:    signingKey,
+		cookieEncryptionKey: encryptionKey,
+	}
+
+	state, err := signState(signingKey, &AuthCode{
+		RedirectURL: "http://localhost:1234/callback",
+		SessionID:   "session",
+		ClusterID:   "cluster",
+		Nonce:       "nonce",
+		Expiry:      time.Now().Add(stateTTL).Unix(),
+	})
+	require.NoError(t, err)
+
+	flowCookie := func(nonce string) *http.Cookie {
+		name := authFlowCookieName("session")
+		value, err := securecookie.New(signingKey, encryptionKey).Encode(name, cookie.AuthFlowState{Nonce: nonce, CodeVerifier: "verifier"})
+		require.NoError(t, err)
+		return &http.Cookie{Name: name, Value: value}
+	}
+
+	tests := []struct {
+		name     string
+		state    string
+		cookie   *http.Cookie
+		wantBody string
+	}{
+		{
+			name:     "invalid state",
+			state:    state + "x",
+			cookie:   flowCookie("nonce"),
+			wantBody: "invalid state: invalid state signature",
+		},
+		{
+			name:     "no auth flow cookie",
+			state:    state,
+			wantBody: "authorization was not started in this browser",
+		},
+		{
+			name:     "undecodable auth flow cookie",
+			state:    state,
+			cookie:   &http.Cookie{Name: authFlowCookieName("session"), Value: "garbage"},
+			wantBody: "authorization was not started in this browser",
+		},
+		{
+			name:     "nonce mismatch",
+			state:    state,
+			cookie:   flowCookie("other-nonce"),
+			wantBody: "invalid state: nonce mismatch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/callback?"+url.Values{"code": {"code"}, "state": {tt.state}}.Encode(), nil)
+			if tt.cookie != nil {
+				r.AddCookie(tt.cookie)
+			}
+			w := httptest.NewRecorder()
+
+			h.handleCallback(w, r)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
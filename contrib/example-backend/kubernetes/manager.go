@@ -18,8 +18,11 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions/kubebind/v1alpha1"
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
@@ -27,16 +30,24 @@ import (
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	kubeclient "k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 )
 
+// ErrBoundWithoutIsolation is returned when binding a consumer to a service provider that exports
+// cluster-scoped resources without isolation to another consumer already.
+var ErrBoundWithoutIsolation = errors.New("service provider is bound exclusively by another consumer cluster")
+
 type Manager struct {
 	namespacePrefix    string
 	providerPrettyName string
@@ -45,6 +56,8 @@ type Manager struct {
 	externalAddress       string
 	externalCA            []byte
 	externalTLSServerName string
+	credentials           kuberesources.CredentialsType
+	credentialsLifetime   time.Duration
 
 	kubeClient kubeclient.Interface
 	bindClient bindclient.Interface
@@ -54,6 +67,8 @@ type Manager struct {
 
 	exportLister  bindlisters.APIServiceExportLister
 	exportIndexer cache.Indexer
+
+	bindTokenIndexer cache.Indexer // nil if bind tokens are disabled
 }
 
 func NewKubernetesManager(
@@ -62,8 +77,11 @@ func NewKubernetesManager(
 	externalAddress string,
 	externalCA []byte,
 	externalTLSServerName string,
+	credentials kuberesources.CredentialsType,
+	credentialsLifetime time.Duration,
 	namespaceInformer corev1informers.NamespaceInformer,
 	exportInformer bindinformers.APIServiceExportInformer,
+	bindTokenInformer corev1informers.SecretInformer,
 ) (*Manager, error) {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, "kube-bind-example-backend-kubernetes-manager")
@@ -85,6 +103,8 @@ func NewKubernetesManager(
 		externalAddress:       externalAddress,
 		externalCA:            externalCA,
 		externalTLSServerName: externalTLSServerName,
+		credentials:           credentials,
+		credentialsLifetime:   credentialsLifetime,
 
 		kubeClient: kubeClient,
 		bindClient: bindClient,
@@ -99,6 +119,12 @@ func NewKubernetesManager(
 	indexers.AddIfNotPresentOrDie(m.namespaceIndexer, cache.Indexers{
 		NamespacesByIdentity: IndexNamespacesByIdentity,
 	})
+	if bindTokenInformer != nil {
+		m.bindTokenIndexer = bindTokenInformer.Informer().GetIndexer()
+		indexers.AddIfNotPresentOrDie(m.bindTokenIndexer, cache.Indexers{
+			kuberesources.BindTokensByHash: kuberesources.IndexBindTokensByHash,
+		})
+	}
 
 	return m, nil
 }
@@ -119,7 +145,11 @@ func (m *Manager) HandleResources(ctx context.Context, identity, resource, group
 	var ns string
 	if len(nss) == 1 {
 		ns = nss[0].(*corev1.Namespace).Name
-	} else {
+	}
+	if err := m.ensureNotBoundWithoutIsolation(ns); err != nil {
+		return nil, err
+	}
+	if ns == "" {
 		nsObj, err := kuberesources.CreateNamespace(ctx, m.kubeClient, m.namespacePrefix, identity)
 		if err != nil {
 			return nil, err
@@ -133,9 +163,9 @@ func (m *Manager) HandleResources(ctx context.Context, identity, resource, group
 	// first look for ClusterBinding to get old secret name
 	kubeconfigSecretName := kuberesources.KubeconfigSecretName
 	cb, err := m.bindClient.KubeBindV1alpha1().ClusterBindings(ns).Get(ctx, kuberesources.ClusterBindingName, metav1.GetOptions{})
-	if err != nil && !errors.IsNotFound(err) {
+	if err != nil && !apierrors.IsNotFound(err) {
 		return nil, err
-	} else if errors.IsNotFound(err) {
+	} else if apierrors.IsNotFound(err) {
 		if err := kuberesources.CreateClusterBinding(ctx, m.bindClient, ns, "kubeconfig", m.providerPrettyName); err != nil {
 			return nil, err
 		}
@@ -144,20 +174,93 @@ func (m *Manager) HandleResources(ctx context.Context, identity, resource, group
 		kubeconfigSecretName = cb.Spec.KubeconfigSecretRef.Name // reuse old name
 	}
 
-	sa, err := kuberesources.CreateServiceAccount(ctx, m.kubeClient, ns, kuberesources.ServiceAccountName)
-	if err != nil {
+	if _, err := kuberesources.CreateServiceAccount(ctx, m.kubeClient, ns, kuberesources.ServiceAccountName); err != nil {
 		return nil, err
 	}
 
-	saSecret, err := kuberesources.CreateSASecret(ctx, m.kubeClient, ns, sa.Name)
+	kfgSecret, err := m.RotateKubeconfig(ctx, ns, kubeconfigSecretName)
 	if err != nil {
 		return nil, err
 	}
 
-	kfgSecret, err := kuberesources.GenerateKubeconfig(ctx, m.kubeClient, m.clusterConfig, m.externalAddress, m.externalCA, m.externalTLSServerName, saSecret.Name, ns, kubeconfigSecretName)
+	return kfgSecret.Data["kubeconfig"], nil
+}
+
+// ensureNotBoundWithoutIsolation fails if cluster-scoped resources are exported without isolation
+// to another consumer than the one of the given cluster namespace. With IsolationNone, consumers
+// would share the same cluster-scoped objects in the service provider cluster.
+func (m *Manager) ensureNotBoundWithoutIsolation(ns string) error {
+	exports, err := m.exportLister.List(labels.Everything())
 	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.Namespace == ns {
+			continue
+		}
+		if export.Spec.Scope == apiextensionsv1.ClusterScoped && export.Spec.ClusterScopedIsolation == v1alpha1.IsolationNone {
+			return fmt.Errorf("%w: APIServiceExport %s/%s exports cluster-scoped resources without isolation", ErrBoundWithoutIsolation, export.Namespace, export.Name)
+		}
+	}
+	return nil
+}
+
+// RotateKubeconfig writes a kubeconfig with freshly issued credentials into the kubeconfig Secret
+// of the given namespace. Earlier credentials stay valid until they expire, giving the konnector
+// time to pick up the new kubeconfig.
+func (m *Manager) RotateKubeconfig(ctx context.Context, ns, kubeconfigSecretName string) (*corev1.Secret, error) {
+	var authInfo *clientcmdapi.AuthInfo
+	var expiration time.Time
+	issued := time.Now()
+	switch m.credentials {
+	case kuberesources.ClientCertificateCredentials:
+		cert, key, err := kuberesources.RequestClientCertificate(ctx, m.kubeClient, ns, kuberesources.ServiceAccountName, m.credentialsLifetime)
+		if err != nil {
+			return nil, err
+		}
+		certs, err := certutil.ParseCertsPEM(cert)
+		if err != nil {
+			return nil, err
+		}
+		authInfo = &clientcmdapi.AuthInfo{ClientCertificateData: cert, ClientKeyData: key}
+		expiration = certs[0].NotAfter
+	default:
+		token, err := kuberesources.RequestServiceAccountToken(ctx, m.kubeClient, ns, kuberesources.ServiceAccountName, m.credentialsLifetime)
+		if err != nil {
+			return nil, err
+		}
+		authInfo = &clientcmdapi.AuthInfo{Token: token.Status.Token}
+		expiration = token.Status.ExpirationTimestamp.Time
+	}
+
+	return kuberesources.GenerateKubeconfig(ctx, m.kubeClient, m.clusterConfig, m.externalAddress, m.externalCA, m.externalTLSServerName, authInfo, issued, expiration, ns, kubeconfigSecretName)
+}
+
+// LookupBindToken returns the valid bind token in the given namespace holding the given token.
+func (m *Manager) LookupBindToken(ns, token string, now time.Time) (*kuberesources.BindToken, error) {
+	if m.bindTokenIndexer == nil {
+		return nil, kuberesources.ErrBindTokenInvalid
+	}
+	secret, err := kuberesources.FindBindToken(m.bindTokenIndexer, ns, token)
+	if err != nil {
+		return nil, err
+	}
+	bindToken, err := kuberesources.ParseBindToken(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := bindToken.Valid(now); err != nil {
 		return nil, err
 	}
+	return bindToken, nil
+}
 
-	return kfgSecret.Data["kubeconfig"], nil
+// RecordBindTokenUse counts a use of the named bind token in the given namespace.
+func (m *Manager) RecordBindTokenUse(ctx context.Context, ns, name string, use kuberesources.BindTokenUse) (*kuberesources.BindToken, error) {
+	return kuberesources.RecordBindTokenUse(ctx, m.kubeClient, ns, name, use)
+}
+
+// RevertBindTokenUse takes back a use of the named bind token in the given namespace.
+func (m *Manager) RevertBindTokenUse(ctx context.Context, ns, name string, use kuberesources.BindTokenUse) error {
+	return kuberesources.RevertBindTokenUse(ctx, m.kubeClient, ns, name, use)
 }
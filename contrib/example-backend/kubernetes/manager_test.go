@@ -0,0 +1,97 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"testing"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestEnsureNotBoundWithoutIsolation(t *testing.T) {
+	export := func(ns string, scope apiextensionsv1.ResourceScope, isolation v1alpha1.Isolation) *v1alpha1.APIServiceExport {
+		return &v1alpha1.APIServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "foos.example.com"},
+			Spec: v1alpha1.APIServiceExportSpec{
+				APIServiceExportCRDSpec: v1alpha1.APIServiceExportCRDSpec{Scope: scope},
+				ClusterScopedIsolation:  isolation,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		exports []*v1alpha1.APIServiceExport
+		ns      string
+		wantErr bool
+	}{
+		{
+			name: "first consumer",
+			ns:   "",
+		},
+		{
+			name:    "later consumer of provider bound without isolation",
+			exports: []*v1alpha1.APIServiceExport{export("cluster-a", apiextensionsv1.ClusterScoped, v1alpha1.IsolationNone)},
+			ns:      "",
+			wantErr: true,
+		},
+		{
+			name:    "existing other consumer of provider bound without isolation",
+			exports: []*v1alpha1.APIServiceExport{export("cluster-a", apiextensionsv1.ClusterScoped, v1alpha1.IsolationNone)},
+			ns:      "cluster-b",
+			wantErr: true,
+		},
+		{
+			name:    "same consumer binding again",
+			exports: []*v1alpha1.APIServiceExport{export("cluster-a", apiextensionsv1.ClusterScoped, v1alpha1.IsolationNone)},
+			ns:      "cluster-a",
+		},
+		{
+			name:    "prefixed isolation",
+			exports: []*v1alpha1.APIServiceExport{export("cluster-a", apiextensionsv1.ClusterScoped, v1alpha1.IsolationPrefixed)},
+			ns:      "",
+		},
+		{
+			name:    "namespaced resources",
+			exports: []*v1alpha1.APIServiceExport{export("cluster-a", apiextensionsv1.NamespaceScoped, "")},
+			ns:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, export := range tt.exports {
+				require.NoError(t, indexer.Add(export))
+			}
+			m := &Manager{exportLister: bindlisters.NewAPIServiceExportLister(indexer)}
+
+			err := m.ensureNotBoundWithoutIsolation(tt.ns)
+			if tt.wantErr {
+				require.True(t, errors.Is(err, ErrBoundWithoutIsolation), "expected ErrBoundWithoutIsolation, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
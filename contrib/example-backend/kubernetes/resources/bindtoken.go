@@ -0,0 +1,294 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// BindTokenType is the type of the Secrets holding bind tokens, created by the admin of the
+	// service provider. The data holds the token, the consumer, the comma separated
+	// <resource>.<group> list of resources, and optionally an expiration in RFC3339 and
+	// a maximal number of uses.
+	BindTokenType = "kube-bind.appscode.com/bind-token"
+
+	BindTokenTokenKey      = "token"
+	BindTokenConsumerKey   = "consumer"
+	BindTokenResourcesKey  = "resources"
+	BindTokenExpirationKey = "expiration"
+	BindTokenMaxUsesKey    = "maxUses"
+
+	// BindTokenUsesAnnotation on the bind token Secret counts the uses of the token.
+	BindTokenUsesAnnotation = "kube-bind.appscode.com/bind-token-uses"
+	// BindTokenAuditAnnotation on the bind token Secret holds the JSON list of the latest uses.
+	BindTokenAuditAnnotation = "kube-bind.appscode.com/bind-token-audit"
+
+	// bindTokenAuditLength is the number of uses kept in the audit annotation.
+	bindTokenAuditLength = 20
+)
+
+var (
+	ErrBindTokenInvalid   = errors.New("invalid bind token")
+	ErrBindTokenExpired   = errors.New("bind token expired")
+	ErrBindTokenExhausted = errors.New("bind token exhausted")
+)
+
+// BindToken is a parsed bind token Secret.
+type BindToken struct {
+	Name       string
+	Consumer   string
+	Resources  []v1alpha1.GroupResource
+	Expiration *time.Time
+	MaxUses    int
+	Uses       int
+}
+
+// BindTokenUse is an entry of the audit trail of a bind token.
+type BindTokenUse struct {
+	Time       metav1.Time `json:"time"`
+	ClusterID  string      `json:"clusterID"`
+	RemoteAddr string      `json:"remoteAddr,omitempty"`
+}
+
+// ParseBindToken parses a bind token Secret.
+func ParseBindToken(secret *corev1.Secret) (*BindToken, error) {
+	if secret.Type != BindTokenType {
+		return nil, fmt.Errorf("secret %s/%s is not of type %s", secret.Namespace, secret.Name, BindTokenType)
+	}
+
+	token := &BindToken{
+		Name:     secret.Name,
+		Consumer: string(secret.Data[BindTokenConsumerKey]),
+	}
+	if token.Consumer == "" {
+		return nil, fmt.Errorf("secret %s/%s has no %s", secret.Namespace, secret.Name, BindTokenConsumerKey)
+	}
+
+	for _, gr := range strings.Split(string(secret.Data[BindTokenResourcesKey]), ",") {
+		gr = strings.TrimSpace(gr)
+		if gr == "" {
+			continue
+		}
+		resource, group, _ := strings.Cut(gr, ".")
+		token.Resources = append(token.Resources, v1alpha1.GroupResource{Group: group, Resource: resource})
+	}
+	if len(token.Resources) == 0 {
+		return nil, fmt.Errorf("secret %s/%s has no %s", secret.Namespace, secret.Name, BindTokenResourcesKey)
+	}
+
+	if value := strings.TrimSpace(string(secret.Data[BindTokenExpirationKey])); value != "" {
+		expiration, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s has invalid %s: %w", secret.Namespace, secret.Name, BindTokenExpirationKey, err)
+		}
+		token.Expiration = &expiration
+	}
+	if value := strings.TrimSpace(string(secret.Data[BindTokenMaxUsesKey])); value != "" {
+		maxUses, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s has invalid %s: %w", secret.Namespace, secret.Name, BindTokenMaxUsesKey, err)
+		}
+		token.MaxUses = maxUses
+	}
+	if value, found := secret.Annotations[BindTokenUsesAnnotation]; found {
+		uses, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s/%s has invalid %s annotation: %w", secret.Namespace, secret.Name, BindTokenUsesAnnotation, err)
+		}
+		token.Uses = uses
+	}
+
+	return token, nil
+}
+
+// Valid returns an error if the bind token is expired or exhausted.
+func (t *BindToken) Valid(now time.Time) error {
+	if t.Expiration != nil && now.After(*t.Expiration) {
+		return ErrBindTokenExpired
+	}
+	if t.MaxUses > 0 && t.Uses >= t.MaxUses {
+		return ErrBindTokenExhausted
+	}
+	return nil
+}
+
+// BindTokensByHash is the name of the index of bind token Secrets by the SHA-256 hash of their token.
+const BindTokensByHash = "bindTokensByHash"
+
+// IndexBindTokensByHash indexes bind token Secrets by the hex encoded SHA-256 hash of their token,
+// such that looking up a token does not compare it against all tokens.
+func IndexBindTokensByHash(obj interface{}) ([]string, error) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Type != BindTokenType || len(secret.Data[BindTokenTokenKey]) == 0 {
+		return nil, nil
+	}
+	return []string{bindTokenHash(secret.Data[BindTokenTokenKey])}, nil
+}
+
+func bindTokenHash(token []byte) string {
+	sum := sha256.Sum256(token)
+	return hex.EncodeToString(sum[:])
+}
+
+// FindBindToken returns the bind token Secret in the given namespace holding the given token,
+// looked up in an indexer with the BindTokensByHash index.
+func FindBindToken(indexer cache.Indexer, ns, token string) (*corev1.Secret, error) {
+	if token == "" {
+		return nil, ErrBindTokenInvalid
+	}
+
+	objs, err := indexer.ByIndex(BindTokensByHash, bindTokenHash([]byte(token)))
+	if err != nil {
+		return nil, err
+	}
+
+	var found *corev1.Secret
+	for _, obj := range objs {
+		secret := obj.(*corev1.Secret)
+		if secret.Namespace != ns || subtle.ConstantTimeCompare(secret.Data[BindTokenTokenKey], []byte(token)) != 1 {
+			continue
+		}
+		if found != nil {
+			// ambiguous, the admin must fix this
+			return nil, fmt.Errorf("multiple bind token secrets %s and %s hold the same token", found.Name, secret.Name)
+		}
+		found = secret
+	}
+	if found == nil {
+		return nil, ErrBindTokenInvalid
+	}
+	return found, nil
+}
+
+// RecordBindTokenUse counts a use of the bind token Secret and appends it to the audit trail,
+// failing if the token has expired or is exhausted in the meantime.
+func RecordBindTokenUse(ctx context.Context, client kubernetes.Interface, ns, name string, use BindTokenUse) (*BindToken, error) {
+	logger := klog.FromContext(ctx)
+
+	var token *BindToken
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := client.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if token, err = ParseBindToken(secret); err != nil {
+			return err
+		}
+		if err := token.Valid(use.Time.Time); err != nil {
+			return err
+		}
+
+		var trail []BindTokenUse
+		if value, found := secret.Annotations[BindTokenAuditAnnotation]; found {
+			if err := json.Unmarshal([]byte(value), &trail); err != nil {
+				logger.Error(err, "failed to decode bind token audit trail, starting a new one", "name", name)
+				trail = nil
+			}
+		}
+		trail = append(trail, use)
+		if len(trail) > bindTokenAuditLength {
+			trail = trail[len(trail)-bindTokenAuditLength:]
+		}
+		bs, err := json.Marshal(trail)
+		if err != nil {
+			return err
+		}
+
+		token.Uses++
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[BindTokenUsesAnnotation] = strconv.Itoa(token.Uses)
+		secret.Annotations[BindTokenAuditAnnotation] = string(bs)
+		_, err = client.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Bind token used", "name", name, "consumer", token.Consumer, "clusterID", use.ClusterID, "remoteAddr", use.RemoteAddr, "uses", token.Uses)
+	return token, nil
+}
+
+// RevertBindTokenUse takes back a use recorded by RecordBindTokenUse, e.g. if binding failed
+// afterwards. The use is removed from the audit trail.
+func RevertBindTokenUse(ctx context.Context, client kubernetes.Interface, ns, name string, use BindTokenUse) error {
+	logger := klog.FromContext(ctx)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := client.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		token, err := ParseBindToken(secret)
+		if err != nil {
+			return err
+		}
+		if token.Uses == 0 {
+			return nil
+		}
+
+		var trail []BindTokenUse
+		if value, found := secret.Annotations[BindTokenAuditAnnotation]; found {
+			if err := json.Unmarshal([]byte(value), &trail); err != nil {
+				logger.Error(err, "failed to decode bind token audit trail, starting a new one", "name", name)
+				trail = nil
+			}
+		}
+		for i := len(trail) - 1; i >= 0; i-- {
+			if trail[i].Time.Equal(&use.Time) && trail[i].ClusterID == use.ClusterID && trail[i].RemoteAddr == use.RemoteAddr {
+				trail = append(trail[:i], trail[i+1:]...)
+				break
+			}
+		}
+		bs, err := json.Marshal(trail)
+		if err != nil {
+			return err
+		}
+
+		secret.Annotations[BindTokenUsesAnnotation] = strconv.Itoa(token.Uses - 1)
+		secret.Annotations[BindTokenAuditAnnotation] = string(bs)
+		_, err = client.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Bind token use reverted", "name", name, "clusterID", use.ClusterID, "remoteAddr", use.RemoteAddr)
+	return nil
+}
@@ -0,0 +1,315 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func bindTokenSecret(name string, data map[string]string, annotations map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "kube-bind",
+			Annotations: annotations,
+		},
+		Type: BindTokenType,
+		Data: map[string][]byte{},
+	}
+	for k, v := range data {
+		secret.Data[k] = []byte(v)
+	}
+	return secret
+}
+
+func TestParseBindToken(t *testing.T) {
+	expiration := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		secret  *corev1.Secret
+		want    *BindToken
+		wantErr string
+	}{
+		{
+			name:   "minimal",
+			secret: bindTokenSecret("ci", map[string]string{BindTokenConsumerKey: "ci", BindTokenResourcesKey: "mangodbs.mangodb.com"}, nil),
+			want: &BindToken{
+				Name:      "ci",
+				Consumer:  "ci",
+				Resources: []v1alpha1.GroupResource{{Group: "mangodb.com", Resource: "mangodbs"}},
+			},
+		},
+		{
+			name: "all fields",
+			secret: bindTokenSecret("ci", map[string]string{
+				BindTokenConsumerKey:   "ci",
+				BindTokenResourcesKey:  "mangodbs.mangodb.com, ,foos.example.com",
+				BindTokenExpirationKey: expiration.Format(time.RFC3339),
+				BindTokenMaxUsesKey:    "3",
+			}, map[string]string{BindTokenUsesAnnotation: "2"}),
+			want: &BindToken{
+				Name:     "ci",
+				Consumer: "ci",
+				Resources: []v1alpha1.GroupResource{
+					{Group: "mangodb.com", Resource: "mangodbs"},
+					{Group: "example.com", Resource: "foos"},
+				},
+				Expiration: &expiration,
+				MaxUses:    3,
+				Uses:       2,
+			},
+		},
+		{
+			name: "wrong type",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "kube-bind"},
+				Type:       corev1.SecretTypeOpaque,
+			},
+			wantErr: "is not of type",
+		},
+		{
+			name:    "missing consumer",
+			secret:  bindTokenSecret("ci", map[string]string{BindTokenResourcesKey: "mangodbs.mangodb.com"}, nil),
+			wantErr: "has no consumer",
+		},
+		{
+			name:    "missing resources",
+			secret:  bindTokenSecret("ci", map[string]string{BindTokenConsumerKey: "ci", BindTokenResourcesKey: " , "}, nil),
+			wantErr: "has no resources",
+		},
+		{
+			name:    "invalid expiration",
+			secret:  bindTokenSecret("ci", map[string]string{BindTokenConsumerKey: "ci", BindTokenResourcesKey: "mangodbs.mangodb.com", BindTokenExpirationKey: "tomorrow"}, nil),
+			wantErr: "invalid expiration",
+		},
+		{
+			name:    "invalid max uses",
+			secret:  bindTokenSecret("ci", map[string]string{BindTokenConsumerKey: "ci", BindTokenResourcesKey: "mangodbs.mangodb.com", BindTokenMaxUsesKey: "many"}, nil),
+			wantErr: "invalid maxUses",
+		},
+		{
+			name:    "invalid uses",
+			secret:  bindTokenSecret("ci", map[string]string{BindTokenConsumerKey: "ci", BindTokenResourcesKey: "mangodbs.mangodb.com"}, map[string]string{BindTokenUsesAnnotation: "x"}),
+			wantErr: "invalid " + BindTokenUsesAnnotation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBindToken(tt.secret)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBindTokenValid(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	before, after := now.Add(-time.Second), now.Add(time.Second)
+
+	tests := []struct {
+		name    string
+		token   BindToken
+		wantErr error
+	}{
+		{name: "unlimited", token: BindToken{Uses: 100}},
+		{name: "not expired", token: BindToken{Expiration: &after}},
+		{name: "expired", token: BindToken{Expiration: &before}, wantErr: ErrBindTokenExpired},
+		{name: "uses left", token: BindToken{MaxUses: 2, Uses: 1}},
+		{name: "exhausted", token: BindToken{MaxUses: 2, Uses: 2}, wantErr: ErrBindTokenExhausted},
+		{name: "expired and exhausted", token: BindToken{Expiration: &before, MaxUses: 1, Uses: 1}, wantErr: ErrBindTokenExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.token.Valid(now)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFindBindToken(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{BindTokensByHash: IndexBindTokensByHash})
+	require.NoError(t, indexer.Add(bindTokenSecret("ci", map[string]string{BindTokenTokenKey: "secret"}, nil)))
+	require.NoError(t, indexer.Add(bindTokenSecret("dup-a", map[string]string{BindTokenTokenKey: "dup"}, nil)))
+	require.NoError(t, indexer.Add(bindTokenSecret("dup-b", map[string]string{BindTokenTokenKey: "dup"}, nil)))
+	other := bindTokenSecret("other", map[string]string{BindTokenTokenKey: "other"}, nil)
+	other.Namespace = "default"
+	require.NoError(t, indexer.Add(other))
+
+	tests := []struct {
+		name     string
+		token    string
+		wantName string
+		wantErr  string
+	}{
+		{name: "found", token: "secret", wantName: "ci"},
+		{name: "empty", token: "", wantErr: ErrBindTokenInvalid.Error()},
+		{name: "unknown", token: "guess", wantErr: ErrBindTokenInvalid.Error()},
+		{name: "other namespace", token: "other", wantErr: ErrBindTokenInvalid.Error()},
+		{name: "ambiguous", token: "dup", wantErr: "hold the same token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindBindToken(indexer, "kube-bind", tt.token)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, got.Name)
+		})
+	}
+}
+
+func TestRecordBindTokenUse(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := map[string]string{BindTokenConsumerKey: "ci", BindTokenResourcesKey: "mangodbs.mangodb.com", BindTokenMaxUsesKey: "30"}
+
+	var fullTrail []BindTokenUse
+	for i := 0; i < bindTokenAuditLength; i++ {
+		fullTrail = append(fullTrail, BindTokenUse{Time: metav1.NewTime(now.Add(-time.Hour)), ClusterID: fmt.Sprintf("old-%d", i)})
+	}
+	fullTrailBytes, err := json.Marshal(fullTrail)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		wantUses    int
+		wantTrail   []string
+		wantErr     error
+		wantErrText string
+	}{
+		{
+			name:      "first use",
+			secret:    bindTokenSecret("ci", data, nil),
+			wantUses:  1,
+			wantTrail: []string{"cluster"},
+		},
+		{
+			name:      "invalid audit trail is restarted",
+			secret:    bindTokenSecret("ci", data, map[string]string{BindTokenUsesAnnotation: "4", BindTokenAuditAnnotation: "{"}),
+			wantUses:  5,
+			wantTrail: []string{"cluster"},
+		},
+		{
+			name:     "audit trail is trimmed",
+			secret:   bindTokenSecret("ci", data, map[string]string{BindTokenUsesAnnotation: "20", BindTokenAuditAnnotation: string(fullTrailBytes)}),
+			wantUses: 21,
+			wantTrail: func() []string {
+				var ids []string
+				for _, use := range fullTrail[1:] {
+					ids = append(ids, use.ClusterID)
+				}
+				return append(ids, "cluster")
+			}(),
+		},
+		{
+			name:    "exhausted",
+			secret:  bindTokenSecret("ci", data, map[string]string{BindTokenUsesAnnotation: "30"}),
+			wantErr: ErrBindTokenExhausted,
+		},
+		{
+			name: "expired",
+			secret: bindTokenSecret("ci", map[string]string{
+				BindTokenConsumerKey: "ci", BindTokenResourcesKey: "mangodbs.mangodb.com", BindTokenExpirationKey: now.Add(-time.Minute).Format(time.RFC3339),
+			}, nil),
+			wantErr: ErrBindTokenExpired,
+		},
+		{
+			name:        "not found",
+			secret:      bindTokenSecret("other", data, nil),
+			wantErrText: "not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tt.secret)
+			use := BindTokenUse{Time: metav1.NewTime(now), ClusterID: "cluster", RemoteAddr: "10.0.0.1:1234"}
+
+			token, err := RecordBindTokenUse(context.Background(), client, "kube-bind", "ci", use)
+			if tt.wantErr != nil || tt.wantErrText != "" {
+				if tt.wantErr != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+				} else {
+					require.ErrorContains(t, err, tt.wantErrText)
+				}
+				got, getErr := client.CoreV1().Secrets("kube-bind").Get(context.Background(), tt.secret.Name, metav1.GetOptions{})
+				require.NoError(t, getErr)
+				require.Equal(t, tt.secret.Annotations, got.Annotations, "secret must not change")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUses, token.Uses)
+
+			got, err := client.CoreV1().Secrets("kube-bind").Get(context.Background(), "ci", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprint(tt.wantUses), got.Annotations[BindTokenUsesAnnotation])
+			var trail []BindTokenUse
+			require.NoError(t, json.Unmarshal([]byte(got.Annotations[BindTokenAuditAnnotation]), &trail))
+			var ids []string
+			for _, use := range trail {
+				ids = append(ids, use.ClusterID)
+			}
+			require.Equal(t, tt.wantTrail, ids)
+			require.Equal(t, "10.0.0.1:1234", trail[len(trail)-1].RemoteAddr)
+		})
+	}
+}
+
+func TestRevertBindTokenUse(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := map[string]string{BindTokenConsumerKey: "ci", BindTokenResourcesKey: "mangodbs.mangodb.com", BindTokenMaxUsesKey: "1"}
+	client := fake.NewSimpleClientset(bindTokenSecret("ci", data, nil))
+
+	first := BindTokenUse{Time: metav1.NewTime(now), ClusterID: "first"}
+	_, err := RecordBindTokenUse(context.Background(), client, "kube-bind", "ci", first)
+	require.NoError(t, err)
+	_, err = RecordBindTokenUse(context.Background(), client, "kube-bind", "ci", BindTokenUse{Time: metav1.NewTime(now), ClusterID: "second"})
+	require.ErrorIs(t, err, ErrBindTokenExhausted)
+
+	require.NoError(t, RevertBindTokenUse(context.Background(), client, "kube-bind", "ci", first))
+	got, err := client.CoreV1().Secrets("kube-bind").Get(context.Background(), "ci", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "0", got.Annotations[BindTokenUsesAnnotation])
+	require.Equal(t, "[]", got.Annotations[BindTokenAuditAnnotation])
+
+	second := BindTokenUse{Time: metav1.NewTime(now.Add(time.Second)), ClusterID: "second"}
+	token, err := RecordBindTokenUse(context.Background(), client, "kube-bind", "ci", second)
+	require.NoError(t, err)
+	require.Equal(t, 1, token.Uses)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+// RequestClientCertificate issues a client certificate for the service account identity via the
+// CertificateSigningRequest API, expiring after the given duration. The certificate carries the
+// service account user name, such that the RBAC bindings of the service account apply. It returns
+// the PEM encoded certificate and key.
+func RequestClientCertificate(ctx context.Context, client kubernetes.Interface, ns, saName string, ttl time.Duration) ([]byte, []byte, error) {
+	logger := klog.FromContext(ctx)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: fmt.Sprintf("system:serviceaccount:%s:%s", ns, saName)},
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: ns + "-",
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}),
+			SignerName:        certificatesv1.KubeAPIServerClientSignerName,
+			ExpirationSeconds: ptr.To(int32(ttl.Seconds())),
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}
+	logger.V(1).Info("Creating certificate signing request", "namespace", ns, "ttl", ttl)
+	csr, err = client.CertificatesV1().CertificateSigningRequests().Create(ctx, csr, metav1.CreateOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:    certificatesv1.CertificateApproved,
+		Status:  corev1.ConditionTrue,
+		Reason:  "KubeBindApproved",
+		Message: "Approved by the kube-bind backend for the konnector of " + ns,
+	})
+	logger.V(1).Info("Approving certificate signing request", "name", csr.Name)
+	if _, err := client.CertificatesV1().CertificateSigningRequests().UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return nil, nil, err
+	}
+
+	var cert []byte
+	logger.V(2).Info("Waiting for certificate signing request to be signed", "name", csr.Name)
+	if err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, 30*time.Second, true, func(ctx context.Context) (done bool, err error) {
+		csr, err := client.CertificatesV1().CertificateSigningRequests().Get(ctx, csr.Name, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		} else if errors.IsNotFound(err) {
+			return false, nil
+		}
+		for _, c := range csr.Status.Conditions {
+			if c.Type == certificatesv1.CertificateFailed || c.Type == certificatesv1.CertificateDenied {
+				return false, fmt.Errorf("certificate signing request %s is %s: %s", csr.Name, c.Type, c.Message)
+			}
+		}
+		cert = csr.Status.Certificate
+		return len(cert) > 0, nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// HasClientCertificate returns whether the kubeconfig in the given Secret authenticates with a
+// client certificate.
+func HasClientCertificate(secret *corev1.Secret) bool {
+	cfg, err := clientcmd.Load(secret.Data["kubeconfig"])
+	if err != nil {
+		return false
+	}
+	kubeContext, found := cfg.Contexts[cfg.CurrentContext]
+	if !found {
+		return false
+	}
+	authInfo, found := cfg.AuthInfos[kubeContext.AuthInfo]
+	return found && len(authInfo.ClientCertificateData) > 0
+}
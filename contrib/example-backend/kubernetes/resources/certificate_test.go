@@ -0,0 +1,122 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestRequestClientCertificate(t *testing.T) {
+	tests := []struct {
+		name string
+		// sign sets the status of the approved CSR as the signer would.
+		sign    func(csr *certificatesv1.CertificateSigningRequest)
+		wantErr string
+	}{
+		{
+			name: "issued",
+			sign: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Status.Certificate = []byte("certificate")
+			},
+		},
+		{
+			name: "failed",
+			sign: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+					Type:    certificatesv1.CertificateFailed,
+					Status:  corev1.ConditionTrue,
+					Message: "signer unavailable",
+				})
+			},
+			wantErr: "is Failed: signer unavailable",
+		},
+		{
+			name: "denied",
+			sign: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+					Type:    certificatesv1.CertificateDenied,
+					Status:  corev1.ConditionTrue,
+					Message: "denied by policy",
+				})
+			},
+			wantErr: "is Denied: denied by policy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			// the fake clientset does not generate names
+			client.PrependReactor("create", "certificatesigningrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				csr := action.(clienttesting.CreateAction).GetObject().(*certificatesv1.CertificateSigningRequest)
+				csr.Name = csr.GenerateName + "csr"
+				return false, nil, nil
+			})
+			var approved *certificatesv1.CertificateSigningRequest
+			client.PrependReactor("update", "certificatesigningrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "approval" {
+					return false, nil, nil
+				}
+				approved = action.(clienttesting.UpdateAction).GetObject().(*certificatesv1.CertificateSigningRequest).DeepCopy()
+				signed := approved.DeepCopy()
+				tt.sign(signed)
+				return true, signed, client.Tracker().Update(certificatesv1.SchemeGroupVersion.WithResource("certificatesigningrequests"), signed, "")
+			})
+
+			cert, key, err := RequestClientCertificate(context.Background(), client, "kube-bind-abc", "kube-binder", time.Hour)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []byte("certificate"), cert)
+
+			keyBlock, _ := pem.Decode(key)
+			require.NotNil(t, keyBlock)
+			require.Equal(t, "EC PRIVATE KEY", keyBlock.Type)
+			privateKey, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+			require.NoError(t, err)
+
+			require.NotNil(t, approved, "CSR must be approved")
+			require.Equal(t, "kube-bind-abc-csr", approved.Name)
+			require.Equal(t, certificatesv1.KubeAPIServerClientSignerName, approved.Spec.SignerName)
+			require.Equal(t, int32(3600), *approved.Spec.ExpirationSeconds)
+			require.ElementsMatch(t, []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth}, approved.Spec.Usages)
+			require.Len(t, approved.Status.Conditions, 1)
+			require.Equal(t, certificatesv1.CertificateApproved, approved.Status.Conditions[0].Type)
+			require.Equal(t, corev1.ConditionTrue, approved.Status.Conditions[0].Status)
+
+			requestBlock, _ := pem.Decode(approved.Spec.Request)
+			require.NotNil(t, requestBlock)
+			request, err := x509.ParseCertificateRequest(requestBlock.Bytes)
+			require.NoError(t, err)
+			require.NoError(t, request.CheckSignature())
+			require.Equal(t, "system:serviceaccount:kube-bind-abc:kube-binder", request.Subject.CommonName)
+			require.True(t, privateKey.PublicKey.Equal(request.PublicKey), "CSR must be signed by the returned key")
+		})
+	}
+}
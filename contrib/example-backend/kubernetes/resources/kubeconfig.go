@@ -24,7 +24,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -33,13 +32,17 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// GenerateKubeconfig creates or updates the kubeconfig Secret with the given credentials, annotated
+// with their issue time and expiration.
 func GenerateKubeconfig(ctx context.Context,
 	client kubernetes.Interface,
 	clusterConfig *rest.Config,
 	externalAddress string,
 	externalCA []byte,
 	externalTLSServerName string,
-	saSecretName, ns, kubeconfigSecretName string,
+	authInfo *clientcmdapi.AuthInfo,
+	issued, expiration time.Time,
+	ns, kubeconfigSecretName string,
 ) (*corev1.Secret, error) {
 	logger := klog.FromContext(ctx)
 
@@ -50,20 +53,6 @@ func GenerateKubeconfig(ctx context.Context,
 		externalCA = clusterConfig.CAData
 	}
 
-	var saSecret *corev1.Secret
-	logger.V(2).Info("Waiting for service account secret to be updated with a token", "name", saSecretName)
-	if err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, 10*time.Second, true, func(ctx context.Context) (done bool, err error) {
-		saSecret, err = client.CoreV1().Secrets(ns).Get(ctx, saSecretName, v1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		} else if errors.IsNotFound(err) {
-			return false, nil
-		}
-		return saSecret.Data["token"] != nil && saSecret.Data["ca.crt"] != nil, nil
-	}); err != nil {
-		return nil, err
-	}
-
 	cfg := clientcmdapi.Config{
 		Clusters: map[string]*clientcmdapi.Cluster{
 			"default": {
@@ -80,9 +69,7 @@ func GenerateKubeconfig(ctx context.Context,
 			},
 		},
 		AuthInfos: map[string]*clientcmdapi.AuthInfo{
-			"default": {
-				Token: string(saSecret.Data["token"]),
-			},
+			"default": authInfo,
 		},
 		CurrentContext: "default",
 	}
@@ -96,6 +83,10 @@ func GenerateKubeconfig(ctx context.Context,
 		ObjectMeta: v1.ObjectMeta{
 			Name:      kubeconfigSecretName,
 			Namespace: ns,
+			Annotations: map[string]string{
+				TokenIssuedAnnotation:     issued.UTC().Format(time.RFC3339),
+				TokenExpirationAnnotation: expiration.UTC().Format(time.RFC3339),
+			},
 		},
 		Data: map[string][]byte{
 			"kubeconfig": kubeconfig,
@@ -116,6 +107,11 @@ func GenerateKubeconfig(ctx context.Context,
 			return err
 		}
 		existing.Data = kubeconfigSecret.Data
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations[TokenIssuedAnnotation] = kubeconfigSecret.Annotations[TokenIssuedAnnotation]
+		existing.Annotations[TokenExpirationAnnotation] = kubeconfigSecret.Annotations[TokenExpirationAnnotation]
 		logger.V(1).Info("Updating kubeconfig secret", "name", kubeconfigSecretName)
 		updated, err = client.CoreV1().Secrets(ns).Update(ctx, existing, v1.UpdateOptions{})
 		return err
@@ -19,13 +19,92 @@ package resources
 import (
 	"context"
 
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
+// NamespacedRoleName is the name of the Role and RoleBinding in each service namespace of a consumer,
+// granting its konnector access to the Secrets and ConfigMaps its objects need there. They are never
+// granted cluster-wide.
+const NamespacedRoleName = "kube-binder-namespaced"
+
+// ConversionProxyLabelKey marks the Roles and RoleBindings in the namespaces of conversion webhook
+// services granting the konnector of a consumer access to them through services/proxy. The value
+// is the cluster namespace of the consumer.
+const ConversionProxyLabelKey = "kube-bind.appscode.com/conversion-proxy"
+
+// NamespacedRules returns the rules of the namespaced Role for the APIServiceExports of a consumer.
+// The names of related and status resources are taken from the objects referencing them, hence the
+// rules cannot be restricted by resourceNames.
+func NamespacedRules(exports []*v1alpha1.APIServiceExport) []rbacv1.PolicyRule {
+	relatedKinds := sets.New[v1alpha1.RelatedResourceKind]()
+	statusKinds := sets.New[v1alpha1.RelatedResourceKind]()
+	for _, export := range exports {
+		for _, related := range export.Spec.RelatedResources {
+			relatedKinds.Insert(related.Kind)
+		}
+		for _, res := range helpers.StatusResources(export) {
+			statusKinds.Insert(res.Kind)
+		}
+	}
+
+	var rules []rbacv1.PolicyRule
+	if len(exports) > 0 {
+		// Events about exported objects are mirrored to the consumer cluster
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"get", "list", "watch"},
+		})
+	}
+	if relatedKinds.Len() > 0 {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: relatedResources(relatedKinds),
+			Verbs:     []string{"get", "list", "create", "update", "patch", "delete"},
+		})
+	}
+	if statusKinds.Len() > 0 {
+		// resources referenced by the status are only read, by name
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: relatedResources(statusKinds),
+			Verbs:     []string{"get"},
+		})
+	}
+	return rules
+}
+
+// WatchesAllNamespaces returns true if the konnector watches the exported objects, and hence their
+// events, in all namespaces of the service provider cluster instead of the service namespaces.
+func WatchesAllNamespaces(export *v1alpha1.APIServiceExport) bool {
+	if export.Spec.Scope == apiextensionsv1.ClusterScoped && export.Spec.ClusterScopedIsolation == v1alpha1.IsolationNamespaced && export.Spec.InformerScope != v1alpha1.ClusterScope {
+		// cluster-scoped objects live as namespaced objects in the cluster namespace
+		return false
+	}
+	return export.Spec.Scope == apiextensionsv1.ClusterScoped || export.Spec.InformerScope == v1alpha1.ClusterScope
+}
+
+func relatedResources(kinds sets.Set[v1alpha1.RelatedResourceKind]) []string {
+	var resources []string
+	if kinds.Has(v1alpha1.RelatedResourceKindConfigMap) {
+		resources = append(resources, "configmaps")
+	}
+	if kinds.Has(v1alpha1.RelatedResourceKindSecret) {
+		resources = append(resources, "secrets")
+	}
+	return resources
+}
+
 func CreateServiceAccount(ctx context.Context, client kubeclient.Interface, ns, name string) (*corev1.ServiceAccount, error) {
 	logger := klog.FromContext(ctx)
 
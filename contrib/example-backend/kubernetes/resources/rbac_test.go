@@ -0,0 +1,100 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestNamespacedRules(t *testing.T) {
+	events := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"get", "list", "watch"}}
+
+	tests := []struct {
+		name    string
+		exports []*v1alpha1.APIServiceExport
+		want    []rbacv1.PolicyRule
+	}{
+		{
+			name: "no exports",
+		},
+		{
+			name: "default status secret",
+			exports: []*v1alpha1.APIServiceExport{
+				{},
+			},
+			want: []rbacv1.PolicyRule{
+				events,
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+			},
+		},
+		{
+			name: "related and status resources",
+			exports: []*v1alpha1.APIServiceExport{
+				{Spec: v1alpha1.APIServiceExportSpec{
+					RelatedResources: []v1alpha1.APIServiceExportRelatedResource{
+						{Kind: v1alpha1.RelatedResourceKindSecret, NamePath: "spec.authSecret.name"},
+					},
+					StatusResources: []v1alpha1.APIServiceExportStatusResource{
+						{Kind: v1alpha1.RelatedResourceKindConfigMap, NamePath: "status.configRef.name"},
+					},
+				}},
+				{Spec: v1alpha1.APIServiceExportSpec{
+					RelatedResources: []v1alpha1.APIServiceExportRelatedResource{
+						{Kind: v1alpha1.RelatedResourceKindConfigMap, NamePath: "spec.config.name"},
+					},
+					StatusResources: []v1alpha1.APIServiceExportStatusResource{
+						{Kind: v1alpha1.RelatedResourceKindConfigMap, NamePath: "status.configRef.name"},
+					},
+				}},
+			},
+			want: []rbacv1.PolicyRule{
+				events,
+				{APIGroups: []string{""}, Resources: []string{"configmaps", "secrets"}, Verbs: []string{"get", "list", "create", "update", "patch", "delete"}},
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NamespacedRules(tt.exports))
+		})
+	}
+}
+
+func TestWatchesAllNamespaces(t *testing.T) {
+	export := func(scope apiextensionsv1.ResourceScope, informerScope v1alpha1.Scope, isolation v1alpha1.Isolation) *v1alpha1.APIServiceExport {
+		return &v1alpha1.APIServiceExport{
+			Spec: v1alpha1.APIServiceExportSpec{
+				APIServiceExportCRDSpec: v1alpha1.APIServiceExportCRDSpec{Scope: scope},
+				InformerScope:           informerScope,
+				ClusterScopedIsolation:  isolation,
+			},
+		}
+	}
+
+	require.False(t, WatchesAllNamespaces(export(apiextensionsv1.NamespaceScoped, v1alpha1.NamespacedScope, "")))
+	require.True(t, WatchesAllNamespaces(export(apiextensionsv1.NamespaceScoped, v1alpha1.ClusterScope, "")))
+	require.True(t, WatchesAllNamespaces(export(apiextensionsv1.ClusterScoped, v1alpha1.ClusterScope, v1alpha1.IsolationPrefixed)))
+	require.True(t, WatchesAllNamespaces(export(apiextensionsv1.ClusterScoped, v1alpha1.ClusterScope, v1alpha1.IsolationNamespaced)))
+	require.False(t, WatchesAllNamespaces(export(apiextensionsv1.ClusterScoped, v1alpha1.NamespacedScope, v1alpha1.IsolationNamespaced)))
+}
@@ -16,6 +16,9 @@ limitations under the License.
 
 package resources
 
+// CredentialsType is the kind of credentials in the kubeconfigs handed out to konnectors.
+type CredentialsType string
+
 const (
 	ServiceAccountTokenType       = "kubernetes.io/service-account-token"
 	ServiceAccountTokenAnnotation = "kubernetes.io/service-account.name"
@@ -23,6 +26,19 @@ const (
 	KubeconfigSecretName          = "kubeconfig"
 	ClusterBindingName            = "cluster"
 
+	// TokenExpirationAnnotation on the kubeconfig Secret holds the expiration of its token or client
+	// certificate in RFC3339.
+	TokenExpirationAnnotation = "kube-bind.appscode.com/token-expiration"
+	// TokenIssuedAnnotation on the kubeconfig Secret holds when its token or client certificate has
+	// been issued in RFC3339. The API server might issue credentials with a shorter lifetime than
+	// requested.
+	TokenIssuedAnnotation = "kube-bind.appscode.com/token-issued"
+
+	// ServiceAccountTokenCredentials are bound service account tokens issued via TokenRequest.
+	ServiceAccountTokenCredentials CredentialsType = "token"
+	// ClientCertificateCredentials are client certificates issued via CertificateSigningRequest.
+	ClientCertificateCredentials CredentialsType = "certificate"
+
 	// TODO(MQ): maybe think of a better label name.
 	ExportedCRDsLabel = "kube-bind.appscode.com/exported"
 )
@@ -0,0 +1,93 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+// RequestServiceAccountToken issues a bound token of the service account via the TokenRequest
+// API, expiring after the given duration.
+func RequestServiceAccountToken(ctx context.Context, client kubernetes.Interface, ns, saName string, expiration time.Duration) (*authenticationv1.TokenRequest, error) {
+	logger := klog.FromContext(ctx)
+
+	logger.V(1).Info("Requesting service account token", "name", saName, "expiration", expiration)
+	return client.CoreV1().ServiceAccounts(ns).CreateToken(ctx, saName, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To(int64(expiration.Seconds())),
+		},
+	}, metav1.CreateOptions{})
+}
+
+// DeleteLegacySASecret deletes the long-lived token Secret of the service account created by
+// older backends, such that its token is not valid anymore.
+func DeleteLegacySASecret(ctx context.Context, client kubernetes.Interface, ns, saName string) error {
+	logger := klog.FromContext(ctx)
+
+	secret, err := client.CoreV1().Secrets(ns).Get(ctx, saName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if secret.Type != ServiceAccountTokenType || secret.Annotations[ServiceAccountTokenAnnotation] != saName {
+		return nil
+	}
+
+	logger.V(1).Info("Deleting legacy service account token secret", "name", secret.Name)
+	if err := client.CoreV1().Secrets(ns).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// TokenExpiration returns the expiration of the token in the given kubeconfig Secret, or false
+// if it holds a legacy token without expiration.
+func TokenExpiration(secret *corev1.Secret) (time.Time, bool) {
+	value, found := secret.Annotations[TokenExpirationAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+	expiration, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiration, true
+}
+
+// TokenIssued returns when the token in the given kubeconfig Secret has been issued, or false if
+// it has been written by an older backend.
+func TokenIssued(secret *corev1.Secret) (time.Time, bool) {
+	value, found := secret.Annotations[TokenIssuedAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+	issued, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return issued, true
+}
@@ -21,8 +21,10 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
 
 	"github.com/spf13/pflag"
 	"k8s.io/component-base/logs"
@@ -48,6 +50,14 @@ type ExtraOptions struct {
 	ExternalCAFile         string
 	ExternalCA             []byte
 	TLSExternalServerName  string
+	HeartbeatInterval      time.Duration
+	TokenExpiration        time.Duration
+	Credentials            string
+	CertificateTTL         time.Duration
+	BindTokenNamespace     string
+
+	HealthBindAddress string
+	HealthPort        int
 
 	TestingAutoSelect string
 }
@@ -81,6 +91,11 @@ func NewOptions() *Options {
 			PrettyName:             "Example Backend",
 			ConsumerScope:          string(v1alpha1.NamespacedScope),
 			ClusterScopedIsolation: string(v1alpha1.IsolationPrefixed),
+			TokenExpiration:        time.Hour,
+			Credentials:            "token",
+			CertificateTTL:         24 * time.Hour,
+			HealthBindAddress:      "0.0.0.0",
+			HealthPort:             8081,
 		},
 	}
 }
@@ -95,10 +110,18 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&options.NamespacePrefix, "namespace-prefix", options.NamespacePrefix, "The prefix to use for cluster namespaces")
 	fs.StringVar(&options.PrettyName, "pretty-name", options.PrettyName, "Pretty name for the backend")
 	fs.StringVar(&options.ConsumerScope, "consumer-scope", options.ConsumerScope, "How consumers access the service provider cluster. In Kubernetes, \"namespaced\" allows namespace isolation. In kcp, \"cluster\" allows workspace isolation, and with that allows cluster-scoped resources to bind and it is generally more performant.")
-	fs.StringVar(&options.ClusterScopedIsolation, "cluster-scoped-isolation", options.ClusterScopedIsolation, "How cluster scoped service objects are isolated between multiple consumers on the provider side. Among the choices, \"prefixed\" prepends the name of the cluster namespace to an object's name; \"namespaced\" maps a consumer side object into a namespaced object inside the corresponding cluster namespace; \"none\" is used for the case of a dedicated provider where isolation is not necessary.")
+	fs.StringVar(&options.ClusterScopedIsolation, "cluster-scoped-isolation", options.ClusterScopedIsolation, "How cluster scoped service objects are isolated between multiple consumers on the provider side. Among the choices, \"prefixed\" prepends the name of the cluster namespace to an object's name; \"namespaced\" maps a consumer side object into a namespaced object inside the corresponding cluster namespace, for which the service provider installs the CRD as namespaced and annotated with "+v1alpha1.ConsumerScopeAnnotationKey+"=Cluster; \"none\" is used for the case of a dedicated provider where isolation is not necessary.")
 	fs.StringVar(&options.ExternalAddress, "external-address", options.ExternalAddress, "The external address for the service provider cluster, including https:// and port. If not specified, service account's hosts are used.")
 	fs.StringVar(&options.ExternalCAFile, "external-ca-file", options.ExternalCAFile, "The external CA file for the service provider cluster. If not specified, service account's CA is used.")
 	fs.StringVar(&options.TLSExternalServerName, "external-server-name", options.TLSExternalServerName, "The external (TLS) server name used by consumers to talk to the service provider cluster. This can be useful to select the right certificate via SNI.")
+	fs.DurationVar(&options.TokenExpiration, "token-expiration", options.TokenExpiration, "Lifetime of the service account tokens in the kubeconfigs handed out to konnectors. Kubeconfigs are rotated when a third of the lifetime is left.")
+	fs.StringVar(&options.Credentials, "credentials", options.Credentials, "The credentials in the kubeconfigs handed out to konnectors. \"token\" issues service account tokens rotated by the backend, \"certificate\" issues client certificates via CertificateSigningRequests renewed on request of the konnector.")
+	fs.DurationVar(&options.CertificateTTL, "certificate-ttl", options.CertificateTTL, "Lifetime of the client certificates in the kubeconfigs handed out to konnectors, with --credentials=certificate. Konnectors request renewal when a third of the lifetime is left, the backend renews on its own when a sixth is left.")
+	fs.StringVar(&options.BindTokenNamespace, "bind-token-namespace", options.BindTokenNamespace, "The namespace of the bind token Secrets of type "+resources.BindTokenType+", which let consumers bind non-interactively with kubectl bind --token, e.g. in GitOps or CI. If empty, bind tokens are disabled.")
+	fs.DurationVar(&options.HeartbeatInterval, "heartbeat-interval", options.HeartbeatInterval, "The heartbeat interval suggested to konnectors in the ClusterBindings. If 0, konnectors use their own.")
+
+	fs.StringVar(&options.HealthBindAddress, "health-bind-address", options.HealthBindAddress, "IP address to serve /healthz and /readyz on.")
+	fs.IntVar(&options.HealthPort, "health-port", options.HealthPort, "Port to serve /healthz and /readyz on. If 0, health checks are not served.")
 
 	fs.StringVar(&options.TestingAutoSelect, "testing-auto-select", options.TestingAutoSelect, "<resource>.<group> that is automatically selected on th bind screen for testing")
 	fs.MarkHidden("testing-auto-select") // nolint: errcheck
@@ -180,6 +203,21 @@ func (options *CompletedOptions) Validate() error {
 			return fmt.Errorf("invalid external hostname: %v", err)
 		}
 	}
+	if options.TokenExpiration < 10*time.Minute {
+		return fmt.Errorf("token expiration must be at least 10m")
+	}
+	if options.Credentials != "token" && options.Credentials != "certificate" {
+		return fmt.Errorf("credentials must be either %q or %q", "token", "certificate")
+	}
+	if options.CertificateTTL < 10*time.Minute {
+		return fmt.Errorf("certificate TTL must be at least 10m")
+	}
+	if options.HeartbeatInterval < 0 {
+		return fmt.Errorf("heartbeat interval cannot be negative")
+	}
+	if options.HealthPort < 0 || options.HealthPort > 65535 {
+		return fmt.Errorf("health port must be between 0 and 65535")
+	}
 
 	return nil
 }
@@ -21,18 +21,24 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net"
+	"net/http"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/clusterbinding"
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/kubeconfigrotation"
 	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/serviceexport"
 	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/serviceexportrequest"
 	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/servicenamespace"
 	"go.bytebuilders.dev/kube-bind/contrib/example-backend/deploy"
 	examplehttp "go.bytebuilders.dev/kube-bind/contrib/example-backend/http"
 	examplekube "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes"
+	kuberesources "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
+	"go.bytebuilders.dev/kube-bind/pkg/health"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/client-go/dynamic"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/klog/v2"
 )
 
@@ -48,6 +54,7 @@ type Server struct {
 
 type Controllers struct {
 	ClusterBinding       *clusterbinding.Controller
+	KubeconfigRotation   *kubeconfigrotation.Controller
 	ServiceNamespace     *servicenamespace.Controller
 	ServiceExport        *serviceexport.Controller
 	ServiceExportRequest *serviceexportrequest.Controller
@@ -78,6 +85,14 @@ func NewServer(config *Config) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error setting up OIDC: %w", err)
 	}
+	credentials, credentialsLifetime := kuberesources.ServiceAccountTokenCredentials, config.Options.TokenExpiration
+	if config.Options.Credentials == string(kuberesources.ClientCertificateCredentials) {
+		credentials, credentialsLifetime = kuberesources.ClientCertificateCredentials, config.Options.CertificateTTL
+	}
+	var bindTokenInformer corev1informers.SecretInformer
+	if config.BindTokenInformers != nil {
+		bindTokenInformer = config.BindTokenInformers.Core().V1().Secrets()
+	}
 	s.Kubernetes, err = examplekube.NewKubernetesManager(
 		config.Options.NamespacePrefix,
 		config.Options.PrettyName,
@@ -85,8 +100,11 @@ func NewServer(config *Config) (*Server, error) {
 		config.Options.ExternalAddress,
 		config.Options.ExternalCA,
 		config.Options.TLSExternalServerName,
+		credentials,
+		credentialsLifetime,
 		config.KubeInformers.Core().V1().Namespaces(),
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		bindTokenInformer,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error setting up Kubernetes Manager: %w", err)
@@ -112,6 +130,7 @@ func NewServer(config *Config) (*Server, error) {
 		callback,
 		config.Options.PrettyName,
 		config.Options.TestingAutoSelect,
+		config.Options.BindTokenNamespace,
 		signingKey,
 		encryptionKey,
 		v1alpha1.Scope(config.Options.ConsumerScope),
@@ -127,16 +146,28 @@ func NewServer(config *Config) (*Server, error) {
 	s.ClusterBinding, err = clusterbinding.NewController(
 		config.ClientConfig,
 		v1alpha1.Scope(config.Options.ConsumerScope),
+		config.Options.HeartbeatInterval,
 		config.BindInformers.KubeBind().V1alpha1().ClusterBindings(),
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
 		config.KubeInformers.Rbac().V1().ClusterRoles(),
 		config.KubeInformers.Rbac().V1().ClusterRoleBindings(),
+		config.KubeInformers.Rbac().V1().Roles(),
 		config.KubeInformers.Rbac().V1().RoleBindings(),
 		config.KubeInformers.Core().V1().Namespaces(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error setting up ClusterBinding Controller: %v", err)
 	}
+	s.KubeconfigRotation, err = kubeconfigrotation.NewController(
+		config.ClientConfig,
+		credentials,
+		credentialsLifetime,
+		s.Kubernetes.RotateKubeconfig,
+		config.BindInformers.KubeBind().V1alpha1().ClusterBindings(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up KubeconfigRotation Controller: %w", err)
+	}
 	s.ServiceNamespace, err = servicenamespace.NewController(
 		config.ClientConfig,
 		v1alpha1.Scope(config.Options.ConsumerScope),
@@ -164,6 +195,7 @@ func NewServer(config *Config) (*Server, error) {
 		v1alpha1.Isolation(config.Options.ClusterScopedIsolation),
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExportRequests(),
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		config.BindInformers.KubeBind().V1alpha1().ClusterBindings(),
 		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
 	)
 	if err != nil {
@@ -184,6 +216,11 @@ func (s *Server) OptionallyStartInformers(ctx context.Context) {
 	kubeSynced := s.Config.KubeInformers.WaitForCacheSync(ctx.Done())
 	kubeBindSynced := s.Config.BindInformers.WaitForCacheSync(ctx.Done())
 	apiextensionsSynced := s.Config.ApiextensionsInformers.WaitForCacheSync(ctx.Done())
+	if s.Config.BindTokenInformers != nil {
+		s.Config.BindTokenInformers.Start(ctx.Done())
+		bindTokenSynced := s.Config.BindTokenInformers.WaitForCacheSync(ctx.Done())
+		logger.Info("bind token informers are synced", "bindTokenSynced", fmt.Sprintf("%v", bindTokenSynced))
+	}
 
 	logger.Info("local informers are synced",
 		"kubeSynced", fmt.Sprintf("%v", kubeSynced),
@@ -192,6 +229,26 @@ func (s *Server) OptionallyStartInformers(ctx context.Context) {
 	)
 }
 
+// StartHealthServer serves /healthz and /readyz in the background. The backend is ready when the
+// OIDC issuer is discoverable and the informers of the controllers have synced.
+func (s *Server) StartHealthServer(ctx context.Context) {
+	if s.Config.Options.HealthPort == 0 {
+		return
+	}
+	checks := []healthz.HealthChecker{
+		healthz.NamedCheck("oidc", func(r *http.Request) error {
+			return s.OIDC.CheckDiscovery(r.Context())
+		}),
+		health.InformersSynced("kube-informers", s.Config.KubeInformers),
+		health.InformersSynced("bind-informers", s.Config.BindInformers),
+		health.InformersSynced("apiextensions-informers", s.Config.ApiextensionsInformers),
+	}
+	if s.Config.BindTokenInformers != nil {
+		checks = append(checks, health.InformersSynced("bind-token-informers", s.Config.BindTokenInformers))
+	}
+	go health.Serve(ctx, s.Config.Options.HealthBindAddress, s.Config.Options.HealthPort, checks...)
+}
+
 func (s *Server) Addr() net.Addr {
 	return s.WebServer.Addr()
 }
@@ -210,6 +267,7 @@ func (s *Server) Run(ctx context.Context) error {
 	go s.Controllers.ServiceExport.Start(ctx, 1)
 	go s.Controllers.ServiceNamespace.Start(ctx, 1)
 	go s.Controllers.ClusterBinding.Start(ctx, 1)
+	go s.Controllers.KubeconfigRotation.Start(ctx, 1)
 	go s.Controllers.ServiceExportRequest.Start(ctx, 1)
 
 	go func() {
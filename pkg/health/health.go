@@ -0,0 +1,87 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/klog/v2"
+)
+
+// Handler returns a handler serving /healthz for liveness and /readyz for readiness. Both
+// include a ping check, /readyz additionally the given checks.
+func Handler(readyChecks ...healthz.HealthChecker) http.Handler {
+	mux := http.NewServeMux()
+	healthz.InstallHandler(mux, healthz.PingHealthz)
+	healthz.InstallReadyzHandler(mux, append([]healthz.HealthChecker{healthz.PingHealthz}, readyChecks...)...)
+	return mux
+}
+
+// Serve serves /healthz and /readyz on the given address and port until ctx is done.
+func Serve(ctx context.Context, address string, port int, readyChecks ...healthz.HealthChecker) {
+	logger := klog.FromContext(ctx)
+
+	server := &http.Server{
+		Addr:              net.JoinHostPort(address, strconv.Itoa(port)),
+		Handler:           Handler(readyChecks...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+
+	logger.Info("serving health checks", "address", server.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error(err, "failed to serve health checks")
+	}
+}
+
+type cacheSyncWaiter interface {
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+}
+
+// InformersSynced returns a check which passes when all started informers of the factory have synced.
+func InformersSynced(name string, factory cacheSyncWaiter) healthz.HealthChecker {
+	return healthz.NamedCheck(name, func(_ *http.Request) error {
+		// a closed channel makes WaitForCacheSync report the current state without waiting.
+		stopCh := make(chan struct{})
+		close(stopCh)
+
+		var notSynced []string
+		for informerType, synced := range factory.WaitForCacheSync(stopCh) {
+			if !synced {
+				notSynced = append(notSynced, informerType.String())
+			}
+		}
+		if len(notSynced) > 0 {
+			sort.Strings(notSynced)
+			return fmt.Errorf("informers not synced: %s", strings.Join(notSynced, ", "))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/server/healthz"
+)
+
+type fakeFactory map[reflect.Type]bool
+
+func (f fakeFactory) WaitForCacheSync(_ <-chan struct{}) map[reflect.Type]bool {
+	return f
+}
+
+func TestHandler(t *testing.T) {
+	ready := false
+	server := httptest.NewServer(Handler(
+		healthz.NamedCheck("ready", func(_ *http.Request) error {
+			if !ready {
+				return errors.New("not ready")
+			}
+			return nil
+		}),
+	))
+	defer server.Close()
+
+	get := func(path string) int {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close() // nolint:errcheck
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, get("/healthz"))
+	require.Equal(t, http.StatusInternalServerError, get("/readyz"))
+
+	ready = true
+	require.Equal(t, http.StatusOK, get("/readyz"))
+}
+
+func TestInformersSynced(t *testing.T) {
+	check := InformersSynced("informers", fakeFactory{
+		reflect.TypeOf(""): true,
+		reflect.TypeOf(0):  false,
+	})
+	require.EqualError(t, check.Check(nil), "informers not synced: int")
+
+	check = InformersSynced("informers", fakeFactory{reflect.TypeOf(""): true})
+	require.NoError(t, check.Check(nil))
+}
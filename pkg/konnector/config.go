@@ -22,6 +22,8 @@ import (
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/options"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/secretinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
@@ -32,6 +34,8 @@ import (
 )
 
 type Config struct {
+	Options *options.CompletedOptions
+
 	ClientConfig        *rest.Config
 	BindClient          *bindclient.Clientset
 	KubeClient          *kubernetesclient.Clientset
@@ -40,10 +44,18 @@ type Config struct {
 	KubeInformers          kubeinformers.SharedInformerFactory
 	BindInformers          bindinformers.SharedInformerFactory
 	ApiextensionsInformers apiextensionsinformers.SharedInformerFactory
+
+	// SecretInformer watches only the kubeconfig secrets referenced by APIServiceBindings.
+	SecretInformer *secretinformer.Informer
+
+	// Sharder assigns APIServiceBindings to the konnector replicas. It is nil if not sharded.
+	Sharder *sharding.Sharder
 }
 
 func NewConfig(options *options.CompletedOptions) (*Config, error) {
-	config := &Config{}
+	config := &Config{
+		Options: options,
+	}
 
 	// create clients
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
@@ -70,6 +82,14 @@ func NewConfig(options *options.CompletedOptions) (*Config, error) {
 	config.KubeInformers = kubeinformers.NewSharedInformerFactory(config.KubeClient, time.Minute*30)
 	config.BindInformers = bindinformers.NewSharedInformerFactory(config.BindClient, time.Minute*30)
 	config.ApiextensionsInformers = apiextensionsinformers.NewSharedInformerFactory(config.ApiextensionsClient, time.Minute*30)
+	config.SecretInformer, err = secretinformer.New(config.KubeClient, config.BindInformers.KubeBind().V1alpha1().APIServiceBindings(), time.Minute*30)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Sharding {
+		config.Sharder = sharding.New(config.KubeClient.CoordinationV1(), options.LeaseLockNamespace, options.LeaseLockName, options.LeaseLockIdentity)
+	}
 
 	return config, nil
 }
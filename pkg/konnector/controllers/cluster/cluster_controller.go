@@ -19,11 +19,12 @@ package cluster
 import (
 	"context"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
-	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/clusterbinding"
@@ -31,14 +32,17 @@ import (
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/servicebinding"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/providerpool"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/secretinformer"
 
 	crdlisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
-	kubernetesinformers "k8s.io/client-go/informers"
-	kubernetesclient "k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -49,8 +53,6 @@ import (
 
 const (
 	controllerName = "kube-bind-konnector-cluster"
-
-	heartbeatInterval = 5 * time.Minute // TODO: make configurable
 )
 
 // NewController returns a new controller handling one cluster connection.
@@ -58,46 +60,83 @@ func NewController(
 	reconcileServiceBinding func(binding *kubebindv1alpha1.APIServiceBinding) bool,
 	consumerConfig *rest.Config,
 	providerInfos []*konnectormodels.ProviderInfo,
+	providerPool *providerpool.Pool,
 	namespaceInformer dynamic.Informer[corelisters.NamespaceLister],
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[crdlisters.CustomResourceDefinitionLister],
+	consumerSecretInformer *secretinformer.Informer,
+	conversionWebhook *conversion.Webhook,
+	heartbeatInterval time.Duration,
 ) (*controller, error) {
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
 
 	for _, provider := range providerInfos {
-		provider.Config = rest.CopyConfig(provider.Config)
-		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
-
-		// create shared informer factories
-		var err error
-		if provider.BindClient, err = bindclient.NewForConfig(provider.Config); err != nil {
-			return nil, err
-		}
-		if provider.KubeClient, err = kubernetesclient.NewForConfig(provider.Config); err != nil {
+		config := rest.CopyConfig(provider.Config)
+		config = rest.AddUserAgent(config, controllerName)
+		config.Wrap(metrics.InstrumentUpstream(provider.ClusterID))
+
+		// share clients and informers with other bindings to the same provider namespace
+		conn, err := providerPool.Acquire(providerpool.Key{ClusterUID: provider.ClusterID, Namespace: provider.Namespace}, provider.Kubeconfig, config)
+		if err != nil {
+			releaseConnections(providerPool, providerInfos)
 			return nil, err
 		}
-
-		provider.BindInformer = bindinformers.NewSharedInformerFactoryWithOptions(provider.BindClient, time.Minute*30, bindinformers.WithNamespace(provider.Namespace))
-		provider.KubeInformer = kubernetesinformers.NewSharedInformerFactoryWithOptions(provider.KubeClient, time.Minute*30, kubernetesinformers.WithNamespace(provider.Namespace))
+		provider.Connection = conn
+		provider.Config = conn.Config
+		provider.Client = conn.Client
+		provider.BindClient = conn.BindClient
+		provider.KubeClient = conn.KubeClient
+		provider.BindInformer = conn.BindInformer
+		provider.KubeInformer = conn.KubeInformer
+		provider.DynamicServiceNamespaceInformer = conn.ServiceNamespaceInformer
 	}
 
-	consumerBindClient, err := bindclient.NewForConfig(consumerConfig)
+	c, err := newController(reconcileServiceBinding, consumerConfig, providerInfos, namespaceInformer, serviceBindingInformer, crdInformer, consumerSecretInformer, conversionWebhook, heartbeatInterval)
 	if err != nil {
+		for _, provider := range providerInfos {
+			provider.RemoveEventHandlers()
+		}
+		releaseConnections(providerPool, providerInfos)
 		return nil, err
 	}
-	consumerKubeClient, err := kubernetesclient.NewForConfig(consumerConfig)
+	c.providerPool = providerPool
+
+	return c, nil
+}
+
+// releaseConnections releases the pooled connections of the given providers.
+func releaseConnections(providerPool *providerpool.Pool, providerInfos []*konnectormodels.ProviderInfo) {
+	for _, provider := range providerInfos {
+		if provider.Connection != nil {
+			providerPool.Release(provider.Connection)
+			provider.Connection = nil
+		}
+	}
+}
+
+func newController(
+	reconcileServiceBinding func(binding *kubebindv1alpha1.APIServiceBinding) bool,
+	consumerConfig *rest.Config,
+	providerInfos []*konnectormodels.ProviderInfo,
+	namespaceInformer dynamic.Informer[corelisters.NamespaceLister],
+	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
+	crdInformer dynamic.Informer[crdlisters.CustomResourceDefinitionLister],
+	consumerSecretInformer *secretinformer.Informer,
+	conversionWebhook *conversion.Webhook,
+	heartbeatInterval time.Duration,
+) (*controller, error) {
+	consumerBindClient, err := bindclient.NewForConfig(consumerConfig)
 	if err != nil {
 		return nil, err
 	}
-	consumerSecretInformers := kubernetesinformers.NewSharedInformerFactoryWithOptions(consumerKubeClient, time.Minute*30)
 
 	// create controllers
 	clusterbindingCtrl, err := clusterbinding.NewController(
 		heartbeatInterval,
 		consumerConfig,
 		serviceBindingInformer,
-		consumerSecretInformers.Core().V1().Secrets(),
+		consumerSecretInformer,
 		providerInfos,
 	)
 	if err != nil {
@@ -116,6 +155,7 @@ func NewController(
 		serviceBindingInformer,
 		crdInformer,
 		providerInfos,
+		conversionWebhook,
 	)
 	if err != nil {
 		return nil, err
@@ -125,23 +165,17 @@ func NewController(
 		serviceBindingInformer,
 		crdInformer,
 		providerInfos,
+		conversionWebhook,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	var factories []SharedInformerFactory
-	for _, provider := range providerInfos {
-		factories = append(factories, provider.BindInformer)
-		factories = append(factories, provider.KubeInformer)
-	}
-	factories = append(factories, consumerSecretInformers)
-
 	return &controller{
-		providerInfos: providerInfos,
-		bindClient:    consumerBindClient,
-
-		factories: factories,
+		providerInfos:     providerInfos,
+		syncedRecorded:    sets.New[string](),
+		bindClient:        consumerBindClient,
+		heartbeatInterval: heartbeatInterval,
 
 		serviceBindingLister:  serviceBindingInformer.Lister(),
 		serviceBindingIndexer: serviceBindingInformer.Informer().GetIndexer(),
@@ -157,26 +191,34 @@ type GenericController interface {
 	Start(ctx context.Context, numThreads int)
 }
 
-type SharedInformerFactory interface {
-	Start(stopCh <-chan struct{})
-	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
-}
-
 // controller holding all controller that are per provider cluster.
 type controller struct {
 	providerInfos []*konnectormodels.ProviderInfo
 
 	bindClient bindclient.Interface
 
+	heartbeatInterval time.Duration
+
 	serviceBindingLister  bindlisters.APIServiceBindingLister
 	serviceBindingIndexer cache.Indexer
 
-	factories []SharedInformerFactory
+	providerPool *providerpool.Pool
 
 	clusterbindingCtrl         GenericController
 	namespacedeletionCtrl      GenericController
 	servicebindingCtrl         GenericController
 	serviceresourcebindingCtrl GenericController
+
+	synced atomic.Bool
+
+	// syncedRecorded are the bindings with an informers_synced metric, forgotten on stop.
+	syncedLock     sync.Mutex
+	syncedRecorded sets.Set[string]
+}
+
+// Synced returns whether the informers of the provider clusters have synced.
+func (c *controller) Synced() bool {
+	return c.synced.Load()
 }
 
 // Start starts the controller, which stops when ctx.Done() is closed.
@@ -184,32 +226,42 @@ func (c *controller) Start(ctx context.Context) {
 	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
 	ctx = klog.NewContext(ctx, logger)
 
-	logger.V(2).Info("starting factories")
-	for _, factory := range c.factories {
-		factory.Start(ctx.Done())
+	defer func() {
+		for _, provider := range c.providerInfos {
+			provider.RemoveEventHandlers()
+		}
+		releaseConnections(c.providerPool, c.providerInfos)
+		c.forgetInformersSynced()
+	}()
+
+	logger.V(2).Info("starting provider connections")
+	for _, provider := range c.providerInfos {
+		provider.Connection.Start()
 	}
 
-	if err := wait.PollUntilContextCancel(ctx, heartbeatInterval, true, func(ctx context.Context) (bool, error) {
-		waitCtx, cancel := context.WithDeadline(ctx, time.Now().Add(heartbeatInterval/2))
+	if err := wait.PollUntilContextCancel(ctx, c.heartbeatInterval, true, func(ctx context.Context) (bool, error) {
+		waitCtx, cancel := context.WithDeadline(ctx, time.Now().Add(c.heartbeatInterval/2))
 		defer cancel()
 
 		logger.V(2).Info("waiting for cache sync")
-		for _, factory := range c.factories {
-			synced := factory.WaitForCacheSync(waitCtx.Done())
+		for _, provider := range c.providerInfos {
+			synced := provider.Connection.WaitForCacheSync(waitCtx.Done())
 			logger.V(2).Info("cache sync", "synced", synced)
 		}
 		select {
 		case <-ctx.Done():
 			// timeout
-			logger.Info("informers did not sync in time", "timeout", heartbeatInterval/2)
+			logger.Info("informers did not sync in time", "timeout", c.heartbeatInterval/2)
+			c.synced.Store(false)
 			c.updateServiceBindings(ctx, func(binding *kubebindv1alpha1.APIServiceBinding) {
+				c.setInformersSynced(binding, false)
 				conditions.MarkFalse(
 					binding,
 					kubebindv1alpha1.APIServiceBindingConditionInformersSynced,
 					"InformerSyncTimeout",
 					conditionsapi.ConditionSeverityError,
 					"Informers did not sync within %s",
-					heartbeatInterval/2,
+					c.heartbeatInterval/2,
 				)
 			})
 
@@ -222,8 +274,10 @@ func (c *controller) Start(ctx context.Context) {
 		return
 	}
 
+	c.synced.Store(true)
 	logger.V(2).Info("setting InformersSynced condition to true on service binding")
 	c.updateServiceBindings(ctx, func(binding *kubebindv1alpha1.APIServiceBinding) {
+		c.setInformersSynced(binding, true)
 		conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionInformersSynced)
 	})
 
@@ -235,6 +289,30 @@ func (c *controller) Start(ctx context.Context) {
 	<-ctx.Done()
 }
 
+// metricLabels returns the metric labels of the given binding to the providers of this controller.
+func (c *controller) metricLabels(binding string) metrics.Labels {
+	return metrics.Labels{
+		Binding:   binding,
+		ClusterID: metrics.ClusterIDLabel(konnectormodels.ClusterIDs(c.providerInfos)...),
+	}
+}
+
+func (c *controller) setInformersSynced(binding *kubebindv1alpha1.APIServiceBinding, synced bool) {
+	c.syncedLock.Lock()
+	defer c.syncedLock.Unlock()
+	metrics.SetInformersSynced(c.metricLabels(binding.Name), synced)
+	c.syncedRecorded.Insert(binding.Name)
+}
+
+func (c *controller) forgetInformersSynced() {
+	c.syncedLock.Lock()
+	defer c.syncedLock.Unlock()
+	for binding := range c.syncedRecorded {
+		metrics.ForgetInformersSynced(c.metricLabels(binding))
+	}
+	c.syncedRecorded.Clear()
+}
+
 func (c *controller) updateServiceBindings(ctx context.Context, update func(*kubebindv1alpha1.APIServiceBinding)) {
 	logger := klog.FromContext(ctx)
 
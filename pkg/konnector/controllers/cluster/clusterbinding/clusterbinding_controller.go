@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
@@ -28,17 +29,18 @@ import (
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/secretinformer"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
-	coreinformers "k8s.io/client-go/informers/core/v1"
 	kubernetesclient "k8s.io/client-go/kubernetes"
-	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -56,27 +58,14 @@ func NewController(
 	heartbeatInterval time.Duration,
 	consumerConfig *rest.Config,
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
-	consumerSecretInformer coreinformers.SecretInformer,
+	consumerSecretInformer *secretinformer.Informer,
 	providerInfos []*konnectormodels.ProviderInfo,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	metricLabels := metrics.Labels{ClusterID: metrics.ClusterIDLabel(konnectormodels.ClusterIDs(providerInfos)...)}
+	queue := metrics.NewRateLimitingQueue(controllerName, metricLabels)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
-	for _, provider := range providerInfos {
-		provider.Config = rest.CopyConfig(provider.Config)
-		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
-		var err error
-		provider.BindClient, err = bindclient.NewForConfig(provider.Config)
-		if err != nil {
-			return nil, err
-		}
-		provider.KubeClient, err = kubernetesclient.NewForConfig(provider.Config)
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
 
@@ -90,12 +79,15 @@ func NewController(
 	}
 
 	c := &controller{
-		queue: queue,
+		queue:        queue,
+		metricLabels: metricLabels,
+
+		heartbeatsRecorded: sets.New[[2]string](),
 
 		consumerBindClient:     consumerBindClient,
 		consumerKubeClient:     consumerKubeClient,
 		serviceBindingInformer: serviceBindingInformer,
-		consumerSecretLister:   consumerSecretInformer.Lister(),
+		consumerSecretInformer: consumerSecretInformer,
 
 		reconciler: reconciler{
 			heartbeatInterval: heartbeatInterval,
@@ -110,6 +102,17 @@ func NewController(
 			getServiceBinding: func(ctx context.Context) (*v1alpha1.APIServiceBindingList, error) {
 				return consumerBindClient.KubeBindV1alpha1().APIServiceBindings().List(ctx, metav1.ListOptions{})
 			},
+			listServiceBindings: func(consumerSecretRefKey string) ([]*v1alpha1.APIServiceBinding, error) {
+				objs, err := serviceBindingInformer.Informer().GetIndexer().ByIndex(indexers.ByServiceBindingKubeconfigSecret, consumerSecretRefKey)
+				if err != nil {
+					return nil, err
+				}
+				sbindings := make([]*v1alpha1.APIServiceBinding, 0, len(objs))
+				for _, obj := range objs {
+					sbindings = append(sbindings, obj.(*v1alpha1.APIServiceBinding))
+				}
+				return sbindings, nil
+			},
 			getProviderInfo: func(clusterID string) (*konnectormodels.ProviderInfo, error) {
 				for _, provider := range providerInfos {
 					if provider.ClusterID == clusterID {
@@ -154,7 +157,7 @@ func NewController(
 	}
 
 	for _, provider := range providerInfos {
-		_, err = provider.BindInformer.KubeBind().V1alpha1().ClusterBindings().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		err = provider.AddEventHandler(provider.BindInformer.KubeBind().V1alpha1().ClusterBindings().Informer(), cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueClusterBinding(logger, obj)
 			},
@@ -169,7 +172,7 @@ func NewController(
 			return nil, err
 		}
 
-		_, err = provider.KubeInformer.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		err = provider.AddEventHandler(provider.KubeInformer.Core().V1().Secrets().Informer(), cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueProviderSecret(logger, obj, c.providerInfos)
 			},
@@ -184,7 +187,7 @@ func NewController(
 			return nil, err
 		}
 
-		_, err = provider.BindInformer.KubeBind().V1alpha1().APIServiceExports().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		err = provider.AddEventHandler(provider.BindInformer.KubeBind().V1alpha1().APIServiceExports().Informer(), cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueServiceExport(logger, obj)
 			},
@@ -210,20 +213,6 @@ func NewController(
 			return nil, err
 		}
 
-		_, err = consumerSecretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {
-				c.enqueueConsumerSecret(logger, obj, provider.Namespace, provider.ConsumerSecretRefKey)
-			},
-			UpdateFunc: func(_, newObj interface{}) {
-				c.enqueueConsumerSecret(logger, newObj, provider.Namespace, provider.ConsumerSecretRefKey)
-			},
-			DeleteFunc: func(obj interface{}) {
-				c.enqueueConsumerSecret(logger, obj, provider.Namespace, provider.ConsumerSecretRefKey)
-			},
-		})
-		if err != nil {
-			return nil, err
-		}
 	}
 
 	return c, nil
@@ -236,14 +225,19 @@ type (
 
 // controller reconciles ClusterBindings on the service provider cluster, including heartbeating.
 type controller struct {
-	queue workqueue.RateLimitingInterface
+	queue        workqueue.RateLimitingInterface
+	metricLabels metrics.Labels
+
+	// heartbeatsRecorded are the bindings and cluster IDs with a heartbeat metric, forgotten on stop.
+	heartbeatsLock     sync.Mutex
+	heartbeatsRecorded sets.Set[[2]string]
 
 	consumerBindClient bindclient.Interface
 	consumerKubeClient kubernetesclient.Interface
 
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister]
 
-	consumerSecretLister corelisters.SecretLister
+	consumerSecretInformer *secretinformer.Informer
 
 	reconciler
 
@@ -316,6 +310,29 @@ func (c *controller) enqueueProviderSecret(logger klog.Logger, obj interface{},
 	c.queue.Add(key)
 }
 
+// enqueueServiceBinding enqueues the ClusterBindings of the providers of the binding, whose heartbeat
+// interval might have changed.
+func (c *controller) enqueueServiceBinding(logger klog.Logger, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*v1alpha1.APIServiceBinding)
+	if !ok {
+		return
+	}
+
+	for _, p := range binding.Spec.Providers {
+		secretKey := p.Kubeconfig.Namespace + "/" + p.Kubeconfig.Name
+		for _, provider := range c.providerInfos {
+			if provider.ConsumerSecretRefKey == secretKey {
+				key := provider.Namespace + "/cluster"
+				logger.V(2).Info("queueing ClusterBinding", "key", key, "reason", "APIServiceBinding", "APIServiceBindingKey", binding.Name)
+				c.queue.Add(key)
+			}
+		}
+	}
+}
+
 func (c *controller) enqueueServiceExport(logger klog.Logger, obj interface{}) {
 	seKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -336,6 +353,8 @@ func (c *controller) enqueueServiceExport(logger klog.Logger, obj interface{}) {
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
+	defer metrics.Forget(controllerName, c.metricLabels)
+	defer c.forgetHeartbeats()
 	defer c.queue.ShutDown()
 
 	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
@@ -343,17 +362,50 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 	logger.Info("Starting controller")
 	defer logger.Info("Shutting down controller")
 
+	for _, provider := range c.providerInfos {
+		c.consumerSecretInformer.AddDynamicEventHandler(ctx, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueConsumerSecret(logger, obj, provider.Namespace, provider.ConsumerSecretRefKey)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				c.enqueueConsumerSecret(logger, newObj, provider.Namespace, provider.ConsumerSecretRefKey)
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.enqueueConsumerSecret(logger, obj, provider.Namespace, provider.ConsumerSecretRefKey)
+			},
+		})
+	}
+
+	c.serviceBindingInformer.Informer().AddDynamicEventHandler(ctx, controllerName, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueServiceBinding(logger, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldBinding, ok := oldObj.(*v1alpha1.APIServiceBinding)
+			if !ok {
+				return
+			}
+			newBinding, ok := newObj.(*v1alpha1.APIServiceBinding)
+			if !ok {
+				return
+			}
+			if reflect.DeepEqual(oldBinding.Spec.HeartbeatInterval, newBinding.Spec.HeartbeatInterval) {
+				return
+			}
+			c.enqueueServiceBinding(logger, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueServiceBinding(logger, obj)
+		},
+	})
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
 
-	// start the heartbeat
+	// start the heartbeat. Every reconciliation schedules the next one.
 	for _, provider := range c.providerInfos {
-		// nolint:errcheck
-		wait.PollUntilContextCancel(ctx, c.heartbeatInterval/2, false, func(ctx context.Context) (bool, error) {
-			c.queue.Add(provider.Namespace + "/cluster")
-			return false, nil
-		})
+		c.queue.Add(provider.Namespace + "/cluster")
 	}
 
 	<-ctx.Done()
@@ -382,7 +434,9 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	err := c.process(ctx, key)
+	metrics.ObserveSync(controllerName, c.metricLabels, err)
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
@@ -402,12 +456,13 @@ func (c *controller) process(ctx context.Context, key string) error {
 	}
 
 	var clusterBindingLister bindlisters.ClusterBindingLister
-	var consumerSecretRefKey string
+	var consumerSecretRefKey, clusterID string
 
 	for _, provider := range c.providerInfos {
 		if provider.Namespace == ns {
 			clusterBindingLister = provider.BindInformer.KubeBind().V1alpha1().ClusterBindings().Lister()
 			consumerSecretRefKey = provider.ConsumerSecretRefKey
+			clusterID = provider.ClusterID
 			break
 		}
 	}
@@ -433,6 +488,11 @@ func (c *controller) process(ctx context.Context, key string) error {
 		errs = append(errs, err)
 	}
 
+	// schedule the next heartbeat
+	if interval := obj.Status.HeartbeatInterval.Duration; interval > 0 {
+		c.queue.AddAfter(key, interval/2)
+	}
+
 	// Regardless of whether reconcile returned an error or not, always try to patch status if needed. Return the
 	// reconciliation error at the end.
 
@@ -455,6 +515,7 @@ func (c *controller) process(ctx context.Context, key string) error {
 	} else {
 		// try to update service bindings
 		c.updateServiceBindings(ctx, func(binding *v1alpha1.APIServiceBinding) {
+			c.heartbeatSucceeded(binding.Name, clusterID)
 			conditions.MarkTrue(binding, v1alpha1.APIServiceBindingConditionHeartbeating)
 		}, consumerSecretRefKey)
 	}
@@ -462,6 +523,22 @@ func (c *controller) process(ctx context.Context, key string) error {
 	return utilerrors.NewAggregate(errs)
 }
 
+func (c *controller) heartbeatSucceeded(binding, clusterID string) {
+	c.heartbeatsLock.Lock()
+	defer c.heartbeatsLock.Unlock()
+	metrics.HeartbeatSucceeded(binding, clusterID)
+	c.heartbeatsRecorded.Insert([2]string{binding, clusterID})
+}
+
+func (c *controller) forgetHeartbeats() {
+	c.heartbeatsLock.Lock()
+	defer c.heartbeatsLock.Unlock()
+	for key := range c.heartbeatsRecorded {
+		metrics.ForgetHeartbeat(key[0], key[1])
+	}
+	c.heartbeatsRecorded.Clear()
+}
+
 func (c *controller) updateServiceBindings(ctx context.Context, update func(*v1alpha1.APIServiceBinding), consumerSecretRefKey string) {
 	logger := klog.FromContext(ctx)
 
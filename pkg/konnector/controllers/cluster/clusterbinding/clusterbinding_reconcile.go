@@ -18,7 +18,9 @@ package clusterbinding
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
+	"reflect"
 	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
@@ -30,6 +32,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
 	componentbaseversion "k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 	conditionsapi "kmodules.xyz/client-go/api/v1"
@@ -41,6 +45,7 @@ type reconciler struct {
 
 	updateServiceBinding func(ctx context.Context, sbinding *kubebindv1alpha1.APIServiceBinding) error
 	getServiceBinding    func(ctx context.Context) (*kubebindv1alpha1.APIServiceBindingList, error)
+	listServiceBindings  func(consumerSecretRefKey string) ([]*kubebindv1alpha1.APIServiceBinding, error)
 	getProviderSecret    func(porvider *konnectormodels.ProviderInfo) (*corev1.Secret, error)
 	getConsumerSecret    func(provider *konnectormodels.ProviderInfo) (*corev1.Secret, error)
 	updateConsumerSecret func(ctx context.Context, secret *corev1.Secret) (*corev1.Secret, error)
@@ -61,7 +66,7 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.Cl
 		errs = append(errs, err)
 	}
 
-	if err := r.ensureHeartbeat(ctx, binding); err != nil {
+	if err := r.ensureHeartbeat(ctx, binding, provider); err != nil {
 		errs = append(errs, err)
 	}
 
@@ -69,6 +74,10 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.Cl
 		errs = append(errs, err)
 	}
 
+	if err := r.ensureCredentialsRenewal(ctx, binding, provider); err != nil {
+		errs = append(errs, err)
+	}
+
 	if err := r.ensureRightScopedServiceBinding(ctx, binding); err != nil {
 		errs = append(errs, err)
 	}
@@ -104,15 +113,43 @@ func (r *reconciler) ensureRightScopedServiceBinding(ctx context.Context, bindin
 	return nil
 }
 
-func (r *reconciler) ensureHeartbeat(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding) error {
-	binding.Status.HeartbeatInterval.Duration = r.heartbeatInterval
-	if now := time.Now(); binding.Status.LastHeartbeatTime.IsZero() || now.After(binding.Status.LastHeartbeatTime.Add(r.heartbeatInterval/2)) {
+func (r *reconciler) ensureHeartbeat(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding, provider *konnectormodels.ProviderInfo) error {
+	interval, err := r.effectiveHeartbeatInterval(binding, provider)
+	if err != nil {
+		return err
+	}
+
+	binding.Status.HeartbeatInterval.Duration = interval
+	if now := time.Now(); binding.Status.LastHeartbeatTime.IsZero() || now.After(binding.Status.LastHeartbeatTime.Add(interval/2)) {
 		binding.Status.LastHeartbeatTime.Time = now
 	}
 
 	return nil
 }
 
+// effectiveHeartbeatInterval returns the interval suggested by the service provider in the ClusterBinding,
+// or else the shortest override of the APIServiceBindings using this provider, or else the konnector default.
+func (r *reconciler) effectiveHeartbeatInterval(binding *kubebindv1alpha1.ClusterBinding, provider *konnectormodels.ProviderInfo) (time.Duration, error) {
+	if binding.Spec.HeartbeatInterval != nil && binding.Spec.HeartbeatInterval.Duration > 0 {
+		return binding.Spec.HeartbeatInterval.Duration, nil
+	}
+
+	sbindings, err := r.listServiceBindings(provider.ConsumerSecretRefKey)
+	if err != nil {
+		return 0, err
+	}
+	var interval time.Duration
+	for _, sbinding := range sbindings {
+		if override := sbinding.Spec.HeartbeatInterval; override != nil && override.Duration > 0 && (interval == 0 || override.Duration < interval) {
+			interval = override.Duration
+		}
+	}
+	if interval == 0 {
+		interval = r.heartbeatInterval
+	}
+	return interval, nil
+}
+
 func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding, provider *konnectormodels.ProviderInfo) error {
 	logger := klog.FromContext(ctx)
 
@@ -157,8 +194,8 @@ func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebind
 		}
 		consumerSecret := corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      ns,
-				Namespace: name,
+				Name:      name,
+				Namespace: ns,
 			},
 			Data: providerSecret.Data,
 			Type: providerSecret.Type,
@@ -167,7 +204,8 @@ func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebind
 		if _, err := r.createConsumerSecret(ctx, &consumerSecret); err != nil {
 			return err
 		}
-	} else {
+	} else if !reflect.DeepEqual(consumerSecret.Data, providerSecret.Data) || consumerSecret.Type != providerSecret.Type {
+		consumerSecret = consumerSecret.DeepCopy()
 		consumerSecret.Data = providerSecret.Data
 		consumerSecret.Type = providerSecret.Type
 
@@ -187,6 +225,54 @@ func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebind
 	return nil
 }
 
+// ensureCredentialsRenewal asks the service provider for a new client certificate when a third of
+// the lifetime of the current one is left. Token credentials are rotated by the service provider
+// on its own.
+func (r *reconciler) ensureCredentialsRenewal(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding, provider *konnectormodels.ProviderInfo) error {
+	cert, err := clientCertificate(provider.Kubeconfig)
+	if err != nil {
+		return err
+	} else if cert == nil {
+		return nil
+	}
+
+	if binding.Status.CredentialsRenewalRequestTime != nil {
+		return nil // still pending
+	}
+	if expiration := binding.Status.CredentialsExpiration; expiration != nil && expiration.After(cert.NotAfter) {
+		return nil // renewed, but the new kubeconfig is not used yet
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	if now := time.Now(); now.After(cert.NotAfter.Add(-lifetime / 3)) {
+		klog.FromContext(ctx).Info("requesting renewal of the client certificate", "expiration", cert.NotAfter)
+		binding.Status.CredentialsRenewalRequestTime = &metav1.Time{Time: now}
+	}
+
+	return nil
+}
+
+// clientCertificate returns the client certificate of the current context of the kubeconfig, or
+// nil if it authenticates differently.
+func clientCertificate(kubeconfig string) (*x509.Certificate, error) {
+	cfg, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, found := cfg.Contexts[cfg.CurrentContext]
+	if !found {
+		return nil, nil
+	}
+	authInfo, found := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !found || len(authInfo.ClientCertificateData) == 0 {
+		return nil, nil
+	}
+	certs, err := certutil.ParseCertsPEM(authInfo.ClientCertificateData)
+	if err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
 func (r *reconciler) ensureKonnectorVersion(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding) error {
 	binding.Status.KonnectorVersion = version.BinaryVersion(componentbaseversion.Get().GitVersion)
 
@@ -0,0 +1,158 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterbinding
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestEnsureHeartbeat(t *testing.T) {
+	serviceBinding := func(interval time.Duration) *kubebindv1alpha1.APIServiceBinding {
+		sbinding := &kubebindv1alpha1.APIServiceBinding{}
+		if interval > 0 {
+			sbinding.Spec.HeartbeatInterval = &metav1.Duration{Duration: interval}
+		}
+		return sbinding
+	}
+
+	tests := []struct {
+		name              string
+		suggested         time.Duration
+		serviceBindings   []*kubebindv1alpha1.APIServiceBinding
+		expectedInterval  time.Duration
+		expectedHeartbeat bool
+	}{
+		{
+			name:             "default",
+			serviceBindings:  []*kubebindv1alpha1.APIServiceBinding{serviceBinding(0)},
+			expectedInterval: 5 * time.Minute,
+		},
+		{
+			name:              "shortest binding override",
+			serviceBindings:   []*kubebindv1alpha1.APIServiceBinding{serviceBinding(0), serviceBinding(2 * time.Minute), serviceBinding(time.Minute)},
+			expectedInterval:  time.Minute,
+			expectedHeartbeat: true,
+		},
+		{
+			name:              "provider suggestion wins",
+			suggested:         30 * time.Second,
+			serviceBindings:   []*kubebindv1alpha1.APIServiceBinding{serviceBinding(time.Minute)},
+			expectedInterval:  30 * time.Second,
+			expectedHeartbeat: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reconciler{
+				heartbeatInterval: 5 * time.Minute,
+				listServiceBindings: func(consumerSecretRefKey string) ([]*kubebindv1alpha1.APIServiceBinding, error) {
+					require.Equal(t, "kube-bind/kubeconfig", consumerSecretRefKey)
+					return tt.serviceBindings, nil
+				},
+			}
+
+			lastHeartbeat := metav1.NewTime(time.Now().Add(-time.Minute))
+			binding := &kubebindv1alpha1.ClusterBinding{
+				Status: kubebindv1alpha1.ClusterBindingStatus{LastHeartbeatTime: lastHeartbeat},
+			}
+			if tt.suggested > 0 {
+				binding.Spec.HeartbeatInterval = &metav1.Duration{Duration: tt.suggested}
+			}
+
+			err := r.ensureHeartbeat(context.Background(), binding, &konnectormodels.ProviderInfo{ConsumerSecretRefKey: "kube-bind/kubeconfig"})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedInterval, binding.Status.HeartbeatInterval.Duration)
+			require.Equal(t, tt.expectedHeartbeat, binding.Status.LastHeartbeatTime.After(lastHeartbeat.Time))
+		})
+	}
+}
+
+func TestEnsureCredentialsRenewal(t *testing.T) {
+	kubeconfig := func(notBefore, notAfter time.Time) string {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "system:serviceaccount:kube-bind-abc:kube-binder"},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+		}, &x509.Certificate{SerialNumber: big.NewInt(1)}, &key.PublicKey, key)
+		require.NoError(t, err)
+		bs, err := clientcmd.Write(clientcmdapi.Config{
+			Contexts:       map[string]*clientcmdapi.Context{"default": {AuthInfo: "default"}},
+			AuthInfos:      map[string]*clientcmdapi.AuthInfo{"default": {ClientCertificateData: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}},
+			CurrentContext: "default",
+		})
+		require.NoError(t, err)
+		return string(bs)
+	}
+
+	now := time.Now().Truncate(time.Second) // as in certificates
+	tests := []struct {
+		name       string
+		kubeconfig string
+		expiration *metav1.Time
+		requested  bool
+	}{
+		{
+			name:       "token",
+			kubeconfig: "apiVersion: v1\nkind: Config\n",
+		},
+		{
+			name:       "fresh certificate",
+			kubeconfig: kubeconfig(now.Add(-time.Hour), now.Add(23*time.Hour)),
+		},
+		{
+			name:       "due certificate",
+			kubeconfig: kubeconfig(now.Add(-20*time.Hour), now.Add(4*time.Hour)),
+			expiration: &metav1.Time{Time: now.Add(4 * time.Hour)},
+			requested:  true,
+		},
+		{
+			name:       "due certificate already renewed",
+			kubeconfig: kubeconfig(now.Add(-20*time.Hour), now.Add(4*time.Hour)),
+			expiration: &metav1.Time{Time: now.Add(24 * time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reconciler{}
+			binding := &kubebindv1alpha1.ClusterBinding{
+				Status: kubebindv1alpha1.ClusterBindingStatus{CredentialsExpiration: tt.expiration},
+			}
+			require.NoError(t, r.ensureCredentialsRenewal(context.Background(), binding, &konnectormodels.ProviderInfo{Kubeconfig: tt.kubeconfig}))
+			require.Equal(t, tt.requested, binding.Status.CredentialsRenewalRequestTime != nil)
+		})
+	}
+}
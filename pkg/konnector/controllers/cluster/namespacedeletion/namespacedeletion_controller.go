@@ -22,7 +22,6 @@ import (
 	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
-	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
@@ -31,9 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	kubernetesclient "k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
-	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -51,20 +48,6 @@ func NewController(
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
-	for _, provider := range providerInfos {
-		provider.Config = rest.CopyConfig(provider.Config)
-		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
-
-		var err error
-		provider.BindClient, err = bindclient.NewForConfig(provider.Config)
-		if err != nil {
-			return nil, err
-		}
-		provider.KubeClient, err = kubernetesclient.NewForConfig(provider.Config)
-		if err != nil {
-			return nil, err
-		}
-	}
 
 	c := &controller{
 		queue: queue,
@@ -91,7 +74,7 @@ func NewController(
 	}
 
 	for _, provider := range providerInfos {
-		_, err := provider.BindInformer.KubeBind().V1alpha1().APIServiceNamespaces().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		err := provider.AddEventHandler(provider.BindInformer.KubeBind().V1alpha1().APIServiceNamespaces().Informer(), cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueServiceNamespace(logger, obj)
 			},
@@ -27,6 +27,8 @@ import (
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -54,21 +56,13 @@ func NewController(
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[apiextensionslisters.CustomResourceDefinitionLister],
 	providerInfos []*konnectormodels.ProviderInfo,
+	conversionWebhook *conversion.Webhook,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	metricLabels := metrics.Labels{ClusterID: metrics.ClusterIDLabel(konnectormodels.ClusterIDs(providerInfos)...)}
+	queue := metrics.NewRateLimitingQueue(controllerName, metricLabels)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
-	for _, provider := range providerInfos {
-		provider.Config = rest.CopyConfig(provider.Config)
-		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
-		var err error
-		provider.BindClient, err = bindclient.NewForConfig(provider.Config)
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
 
@@ -82,7 +76,8 @@ func NewController(
 	}
 
 	c := &controller{
-		queue: queue,
+		queue:        queue,
+		metricLabels: metricLabels,
 
 		serviceBindingInformer: serviceBindingInformer,
 
@@ -90,7 +85,8 @@ func NewController(
 		providerInfors: providerInfos,
 
 		reconciler: reconciler{
-			providerInfos: providerInfos,
+			providerInfos:     providerInfos,
+			conversionWebhook: conversionWebhook,
 
 			reconcileServiceBinding: reconcileServiceBinding,
 
@@ -133,7 +129,7 @@ func NewController(
 			indexers.ServiceExportByCustomResourceDefinition: indexers.IndexServiceExportByCustomResourceDefinition,
 		})
 
-		_, err = provider.BindInformer.KubeBind().V1alpha1().APIServiceExports().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		err = provider.AddEventHandler(provider.BindInformer.KubeBind().V1alpha1().APIServiceExports().Informer(), cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueServiceExport(logger, obj, provider)
 			},
@@ -159,7 +155,8 @@ type (
 
 // controller reconciles ServiceBindings with there ServiceExports counterparts.
 type controller struct {
-	queue workqueue.RateLimitingInterface
+	queue        workqueue.RateLimitingInterface
+	metricLabels metrics.Labels
 
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister]
 
@@ -231,6 +228,7 @@ func (c *controller) enqueueCRD(logger klog.Logger, obj interface{}) {
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
+	defer metrics.Forget(controllerName, c.metricLabels)
 	defer c.queue.ShutDown()
 
 	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
@@ -292,7 +290,9 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	err := c.process(ctx, key)
+	metrics.ObserveSync(controllerName, c.metricLabels, err)
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
@@ -21,6 +21,7 @@ import (
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -33,7 +34,8 @@ import (
 )
 
 type reconciler struct {
-	providerInfos []*konnectormodels.ProviderInfo
+	providerInfos     []*konnectormodels.ProviderInfo
+	conversionWebhook *conversion.Webhook
 
 	reconcileServiceBinding func(binding *v1alpha1.APIServiceBinding) bool
 	getServiceExport        func(provider *konnectormodels.ProviderInfo, ns string) (*v1alpha1.APIServiceExport, error)
@@ -64,6 +66,8 @@ func (r *reconciler) reconcile(ctx context.Context, binding *v1alpha1.APIService
 		errs = append(errs, err)
 	}
 
+	r.ensurePausedCondition(binding)
+
 	//if err := r.ensureClusterName(ctx, binding); err != nil {
 	//	errs = append(errs, err)
 	//}
@@ -139,6 +143,7 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *v1alpha1.APIServic
 			)
 			return nil // nothing we can do here
 		}
+		r.ensureCRDConversion(crd, export)
 
 		// put binding owner reference on the CRD.
 		newReference := metav1.OwnerReference{
@@ -206,6 +211,49 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *v1alpha1.APIServic
 	return utilerrors.NewAggregate(errs)
 }
 
+// ensurePausedCondition reports whether syncing of the objects of the binding is paused.
+func (r *reconciler) ensurePausedCondition(binding *v1alpha1.APIServiceBinding) {
+	if !binding.Spec.Paused {
+		conditions.Delete(binding, v1alpha1.APIServiceBindingConditionPaused)
+		return
+	}
+
+	conditions.Set(binding, &conditionsapi.Condition{
+		Type:     v1alpha1.APIServiceBindingConditionPaused,
+		Status:   metav1.ConditionTrue,
+		Severity: conditionsapi.ConditionSeverityInfo,
+		Reason:   "Paused",
+		Message:  "Syncing is paused by spec.paused.",
+	})
+}
+
+// ensureCRDConversion points the CRD to the conversion webhook of the konnector if the service
+// provider CRD converts through a webhook. Without the konnector serving conversion webhooks,
+// only the storage version is served.
+func (r *reconciler) ensureCRDConversion(crd *apiextensionsv1.CustomResourceDefinition, export *v1alpha1.APIServiceExport) {
+	if export.Spec.Conversion == nil || len(crd.Spec.Versions) <= 1 {
+		return
+	}
+
+	if clientConfig := r.conversionWebhook.ClientConfig(crd.Name); clientConfig != nil {
+		crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+			Strategy: apiextensionsv1.WebhookConverter,
+			Webhook: &apiextensionsv1.WebhookConversion{
+				ClientConfig:             clientConfig,
+				ConversionReviewVersions: export.Spec.Conversion.ConversionReviewVersions,
+			},
+		}
+		return
+	}
+
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{v}
+			return
+		}
+	}
+}
+
 //func (r *reconciler) ensureClusterName(ctx context.Context, binding *v1alpha1.APIServiceBinding) error {
 //	binding.Status.Providers = []v1alpha1.Provider{}
 //	for _, provider := range r.providerInfos {
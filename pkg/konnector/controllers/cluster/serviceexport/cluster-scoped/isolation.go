@@ -0,0 +1,78 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterscoped
+
+import (
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// UpstreamNamespacedName returns the namespace and name of the upstream copy of a
+// cluster-scoped downstream object with the given name.
+func UpstreamNamespacedName(name, clusterNs string, isolation kubebindv1alpha1.Isolation) (string, string) {
+	switch isolation {
+	case kubebindv1alpha1.IsolationNamespaced:
+		return clusterNs, name
+	case kubebindv1alpha1.IsolationNone:
+		return "", name
+	default:
+		return "", Prepend(name, clusterNs)
+	}
+}
+
+// DownstreamName returns the name of the cluster-scoped downstream object corresponding to
+// the given upstream object. It returns false if the upstream object does not belong to the
+// cluster namespace.
+func DownstreamName(ns, name, clusterNs string, isolation kubebindv1alpha1.Isolation) (string, bool) {
+	switch isolation {
+	case kubebindv1alpha1.IsolationNamespaced:
+		return name, ns == clusterNs
+	case kubebindv1alpha1.IsolationNone:
+		return name, ns == ""
+	default:
+		if ns != "" || Behead(name, clusterNs) == name {
+			return "", false
+		}
+		return Behead(name, clusterNs), true
+	}
+}
+
+// ToUpstream mutates a cluster-scoped downstream object in place into its upstream copy.
+func ToUpstream(obj *unstructured.Unstructured, clusterNs, clusterNsUID string, isolation kubebindv1alpha1.Isolation) error {
+	switch isolation {
+	case kubebindv1alpha1.IsolationNamespaced:
+		return TranslateToNamespaced(obj, clusterNs)
+	case kubebindv1alpha1.IsolationNone:
+		return nil
+	default:
+		return TranslateFromDownstream(obj, clusterNs, clusterNsUID)
+	}
+}
+
+// ToDownstream mutates an upstream copy of a cluster-scoped object in place into its downstream
+// counterpart.
+func ToDownstream(obj *unstructured.Unstructured, isolation kubebindv1alpha1.Isolation) error {
+	switch isolation {
+	case kubebindv1alpha1.IsolationNamespaced:
+		return TranslateFromNamespaced(obj)
+	case kubebindv1alpha1.IsolationNone:
+		return nil
+	default:
+		return TranslateFromUpstream(obj)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterscoped
+
+import (
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestIsolationRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		isolation    kubebindv1alpha1.Isolation
+		expectedNs   string
+		expectedName string
+	}{
+		{
+			name:         "prefixed",
+			isolation:    kubebindv1alpha1.IsolationPrefixed,
+			expectedNs:   "",
+			expectedName: "kube-bind-zlp9m-example-foo",
+		},
+		{
+			name:         "namespaced",
+			isolation:    kubebindv1alpha1.IsolationNamespaced,
+			expectedNs:   "kube-bind-zlp9m",
+			expectedName: "example-foo",
+		},
+		{
+			name:         "none",
+			isolation:    kubebindv1alpha1.IsolationNone,
+			expectedNs:   "",
+			expectedName: "example-foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, name := UpstreamNamespacedName("example-foo", "kube-bind-zlp9m", tt.isolation)
+			require.Equal(t, tt.expectedNs, ns)
+			require.Equal(t, tt.expectedName, name)
+
+			downstreamName, ok := DownstreamName(ns, name, "kube-bind-zlp9m", tt.isolation)
+			require.True(t, ok)
+			require.Equal(t, "example-foo", downstreamName)
+
+			obj := unstructured.Unstructured{}
+			obj.SetName("example-foo")
+			require.NoError(t, ToUpstream(&obj, "kube-bind-zlp9m", "real-identity", tt.isolation))
+			require.Equal(t, tt.expectedNs, obj.GetNamespace())
+			require.Equal(t, tt.expectedName, obj.GetName())
+			if tt.isolation == kubebindv1alpha1.IsolationNone {
+				require.Empty(t, obj.GetAnnotations())
+				require.Empty(t, obj.GetOwnerReferences())
+			}
+
+			require.NoError(t, ToDownstream(&obj, tt.isolation))
+			require.Equal(t, "", obj.GetNamespace())
+			require.Equal(t, "example-foo", obj.GetName())
+			require.NotContains(t, obj.GetAnnotations(), ClusterNsAnnotationKey)
+		})
+	}
+}
+
+func TestDownstreamNameMismatch(t *testing.T) {
+	_, ok := DownstreamName("", "other-example-foo", "kube-bind-zlp9m", kubebindv1alpha1.IsolationPrefixed)
+	require.False(t, ok)
+	_, ok = DownstreamName("kube-bind-s85lc", "example-foo", "kube-bind-zlp9m", kubebindv1alpha1.IsolationNamespaced)
+	require.False(t, ok)
+	_, ok = DownstreamName("kube-bind-zlp9m", "example-foo", "kube-bind-zlp9m", kubebindv1alpha1.IsolationNone)
+	require.False(t, ok)
+}
@@ -152,6 +152,40 @@ func TranslateFromUpstream(obj *unstructured.Unstructured) error {
 	return nil
 }
 
+// TranslateToNamespaced mutates a cluster-scoped object in place by moving it into the cluster namespace
+// and annotating it with the cluster namespace. The name is kept.
+func TranslateToNamespaced(obj *unstructured.Unstructured, clusterNs string) error {
+	ans := obj.GetAnnotations()
+	if existing, found := ans[ClusterNsAnnotationKey]; found && existing != clusterNs {
+		return errors.New("mismatch between existing cluster namespace and given cluster namespace")
+	}
+	if ans == nil {
+		ans = map[string]string{}
+	}
+	ans[ClusterNsAnnotationKey] = clusterNs
+	obj.SetAnnotations(ans)
+	obj.SetNamespace(clusterNs)
+	return nil
+}
+
+// TranslateFromNamespaced mutates a namespaced upstream copy of a cluster-scoped object in place
+// by clearing the cluster namespace annotation and the namespace.
+func TranslateFromNamespaced(obj *unstructured.Unstructured) error {
+	clusterNs, err := ExtractClusterNs(obj)
+	if err != nil {
+		return err
+	}
+	if obj.GetNamespace() != clusterNs {
+		return errors.New("mismatch between object namespace and cluster namespace")
+	}
+
+	ans := obj.GetAnnotations()
+	delete(ans, ClusterNsAnnotationKey)
+	obj.SetAnnotations(ans)
+	obj.SetNamespace("")
+	return nil
+}
+
 func findOwnerReferenceToClusterNs(ors []metav1.OwnerReference, clusterNs string) (int, bool) {
 	if ors == nil {
 		return -1, false
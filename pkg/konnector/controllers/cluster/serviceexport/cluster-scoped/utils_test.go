@@ -188,6 +188,48 @@ func TestClearClusterNs(t *testing.T) {
 	}
 }
 
+func TestTranslateNamespaced(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       unstructured.Unstructured
+		clusterNs string
+		wantErr   bool
+	}{
+		{
+			name:      "noExistingClusterNs",
+			obj:       unstructured.Unstructured{},
+			clusterNs: "kube-bind-zlp9m",
+			wantErr:   false,
+		},
+		{
+			name:      "otherExistingClusterNs",
+			obj:       newObjectWithClusterNs("kube-bind-zlp9m"),
+			clusterNs: "kube-bind-s85lc",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.obj.SetName("example-foo")
+			if err := TranslateToNamespaced(&tt.obj, tt.clusterNs); (err != nil) != tt.wantErr {
+				t.Error("TranslateToNamespaced() error", "error", err, "wantErr", tt.wantErr)
+				return
+			} else if err != nil {
+				t.Log("expected error", err)
+				return
+			}
+			require.Equal(t, tt.clusterNs, tt.obj.GetNamespace())
+			require.Equal(t, "example-foo", tt.obj.GetName())
+			require.Equal(t, tt.clusterNs, tt.obj.GetAnnotations()[ClusterNsAnnotationKey])
+
+			require.NoError(t, TranslateFromNamespaced(&tt.obj))
+			require.Equal(t, "", tt.obj.GetNamespace())
+			require.Equal(t, "example-foo", tt.obj.GetName())
+			require.NotContains(t, tt.obj.GetAnnotations(), ClusterNsAnnotationKey)
+		})
+	}
+}
+
 func newObjectWithClusterNs(name string) unstructured.Unstructured {
 	obj := unstructured.Unstructured{}
 	ans := map[string]string{
@@ -0,0 +1,264 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	controllerName = "kube-bind-konnector-cluster-events"
+
+	// mirroredCacheSize is the number of mirrored Events remembered for deduplication.
+	mirroredCacheSize = 4096
+)
+
+// EventsGVR is the resource of the upstream Events.
+var EventsGVR = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+
+// InvolvedKindTweakListOptions returns list options selecting Events about objects of the given kind.
+func InvolvedKindTweakListOptions(kind string) func(*metav1.ListOptions) {
+	return func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("involvedObject.kind", kind).String()
+	}
+}
+
+// NewController returns a new controller mirroring Events about upstream objects onto the
+// downstream objects.
+//
+// Mirrored Events are recorded through an Event broadcaster, which rate limits and aggregates
+// them per downstream object.
+func NewController(
+	gvr schema.GroupVersionResource,
+	kind string,
+	isolation kubebindv1alpha1.Isolation,
+	consumerConfig *rest.Config,
+	consumerDynamicInformer informers.GenericInformer,
+	providerInfos []*konnectormodels.ProviderInfo,
+	providerEventInformers map[string]multinsinformer.GetterInformer,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
+	consumerKubeClient, err := kubernetes.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerName})
+
+	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr)
+	c := &controller{
+		queue: queue,
+
+		consumerKubeClient: consumerKubeClient,
+		broadcaster:        broadcaster,
+
+		providerInfos:          providerInfos,
+		providerEventInformers: providerEventInformers,
+
+		reconciler: reconciler{
+			gvr:                    gvr,
+			kind:                   kind,
+			clusterScopedIsolation: isolation,
+
+			startTime: time.Now(),
+			mirrored:  cache.NewLRUExpireCache(mirroredCacheSize),
+
+			getServiceNamespace: func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error) {
+				sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, upstreamNamespace)
+				if err != nil {
+					return nil, err
+				}
+				if len(sns) == 0 {
+					return nil, errors.NewNotFound(kubebindv1alpha1.SchemeGroupVersion.WithResource("APIServiceNamespace").GroupResource(), upstreamNamespace)
+				}
+				return sns[0].(*kubebindv1alpha1.APIServiceNamespace), nil
+			},
+			getConsumerObject: func(ns, name string) (*unstructured.Unstructured, error) {
+				if ns != "" {
+					return dynamicConsumerLister.Namespace(ns).Get(name)
+				}
+				return dynamicConsumerLister.Get(name)
+			},
+			recordEvent: func(obj *unstructured.Unstructured, eventType, reason, message string) {
+				recorder.Event(obj, eventType, reason, message)
+			},
+		},
+	}
+
+	for _, provider := range providerInfos {
+		inf, found := providerEventInformers[provider.ClusterID]
+		if !found {
+			return nil, fmt.Errorf("no Event informer for provider cluster %s", provider.ClusterID)
+		}
+		inf.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueEvent(logger, provider, obj)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				c.enqueueEvent(logger, provider, newObj)
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// controller mirrors Events about upstream objects onto the downstream objects.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	consumerKubeClient kubernetes.Interface
+	broadcaster        record.EventBroadcaster
+
+	providerInfos          []*konnectormodels.ProviderInfo
+	providerEventInformers map[string]multinsinformer.GetterInformer
+
+	reconciler
+}
+
+func (c *controller) enqueueEvent(logger klog.Logger, provider *konnectormodels.ProviderInfo, obj interface{}) {
+	key, err := toolscache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger.V(3).Info("queueing Event", "key", key)
+	c.queue.Add(provider.ClusterID + "/" + key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	c.broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.consumerKubeClient.CoreV1().Events("")})
+	defer c.broadcaster.Shutdown()
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(3).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 {
+		runtime.HandleError(fmt.Errorf("unexpected key format: %q", key))
+		return nil // we cannot do anything
+	}
+	clusterID, ns, name := parts[0], parts[1], parts[2]
+
+	provider, err := konnectormodels.GetProviderInfoWithClusterID(c.providerInfos, clusterID)
+	if err != nil {
+		return err
+	}
+
+	obj, err := c.providerEventInformers[clusterID].Get(ns, name)
+	if errors.IsNotFound(err) {
+		return nil // nothing to mirror anymore
+	} else if err != nil {
+		return err
+	}
+
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected Event type %T", obj))
+		return nil // nothing we can do here
+	}
+	var event corev1.Event
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &event); err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
+
+	return c.reconcile(ctx, provider, &event)
+}
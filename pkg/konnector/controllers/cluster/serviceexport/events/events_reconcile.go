@@ -0,0 +1,134 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/klog/v2"
+)
+
+// mirroredTTL is how long mirrored Events are remembered. It matches the default Event TTL of
+// kube-apiserver. Older Events are not mirrored.
+const mirroredTTL = time.Hour
+
+type reconciler struct {
+	gvr                    schema.GroupVersionResource
+	kind                   string
+	clusterScopedIsolation kubebindv1alpha1.Isolation
+
+	// startTime is when the controller was created. Events observed before are not mirrored to
+	// avoid duplicates on restarts.
+	startTime time.Time
+	// mirrored maps the UIDs of mirrored upstream Events to their count when they were mirrored.
+	mirrored *cache.LRUExpireCache
+
+	getServiceNamespace func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error)
+	getConsumerObject   func(ns, name string) (*unstructured.Unstructured, error)
+
+	recordEvent func(obj *unstructured.Unstructured, eventType, reason, message string)
+}
+
+// reconcile mirrors an upstream Event onto the downstream object it is about.
+func (r *reconciler) reconcile(ctx context.Context, provider *konnectormodels.ProviderInfo, event *corev1.Event) error {
+	logger := klog.FromContext(ctx)
+
+	involved := event.InvolvedObject
+	if involved.Kind != r.kind {
+		return nil
+	}
+	if gv, err := schema.ParseGroupVersion(involved.APIVersion); err != nil || gv.Group != r.gvr.Group {
+		return nil
+	}
+
+	count := eventCount(event)
+	if last, found := r.mirrored.Get(event.UID); found && last.(int32) >= count {
+		return nil // already mirrored
+	}
+	if observed := lastObserved(event); observed.Before(r.startTime) || time.Since(observed) > mirroredTTL {
+		return nil // observed before the controller started, or expired
+	}
+
+	var ns, name string
+	if downstreamName, ok := clusterscoped.DownstreamName(involved.Namespace, involved.Name, provider.Namespace, r.clusterScopedIsolation); ok {
+		// upstream copy of a cluster-scoped downstream object
+		name = downstreamName
+	} else if involved.Namespace != "" {
+		sn, err := r.getServiceNamespace(provider, involved.Namespace)
+		if errors.IsNotFound(err) {
+			return nil // not a service namespace
+		} else if err != nil {
+			return err
+		}
+		if sn.Namespace != provider.Namespace {
+			return nil // not for us
+		}
+		ns, name = sn.Name, involved.Name
+	} else {
+		return nil // not a kube-bind managed object
+	}
+
+	downstream, err := r.getConsumerObject(ns, name)
+	if errors.IsNotFound(err) {
+		logger.V(3).Info("skipping Event because downstream object is missing", "downstreamNamespace", ns, "downstreamName", name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if downstream.GetAnnotations()[konnectormodels.AnnotationProviderClusterID] != provider.ClusterID {
+		return nil // not for us
+	}
+
+	logger.V(2).Info("Mirroring Event", "reason", event.Reason, "downstreamNamespace", downstream.GetNamespace(), "downstreamName", downstream.GetName())
+	r.recordEvent(downstream, event.Type, event.Reason, event.Message)
+	r.mirrored.Add(event.UID, count, mirroredTTL)
+
+	return nil
+}
+
+// eventCount returns how often the Event has been observed.
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > event.Count {
+		return event.Series.Count
+	}
+	return event.Count
+}
+
+// lastObserved returns when the Event has been observed last.
+func lastObserved(event *corev1.Event) time.Time {
+	observed := event.LastTimestamp.Time
+	if event.Series != nil && event.Series.LastObservedTime.Time.After(observed) {
+		observed = event.Series.LastObservedTime.Time
+	}
+	if event.EventTime.Time.After(observed) {
+		observed = event.EventTime.Time
+	}
+	if observed.IsZero() {
+		observed = event.CreationTimestamp.Time
+	}
+	return observed
+}
@@ -0,0 +1,121 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+func TestReconcile(t *testing.T) {
+	provider := &konnectormodels.ProviderInfo{Namespace: "kube-bind-zlp9m", ClusterID: "cluster-a"}
+
+	downstream := &unstructured.Unstructured{}
+	downstream.SetAPIVersion("mongodb.example.com/v1")
+	downstream.SetKind("MongoDB")
+	downstream.SetNamespace("default")
+	downstream.SetName("mongo")
+	downstream.SetAnnotations(map[string]string{konnectormodels.AnnotationProviderClusterID: "cluster-a"})
+
+	type recorded struct {
+		namespace, name, reason string
+	}
+	var events []recorded
+	r := &reconciler{
+		gvr:                    schema.GroupVersionResource{Group: "mongodb.example.com", Version: "v1", Resource: "mongodbs"},
+		kind:                   "MongoDB",
+		clusterScopedIsolation: kubebindv1alpha1.IsolationPrefixed,
+		startTime:              time.Now().Add(-time.Minute),
+		mirrored:               cache.NewLRUExpireCache(10),
+		getServiceNamespace: func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error) {
+			if upstreamNamespace != "kube-bind-zlp9m-default" {
+				return nil, errors.NewNotFound(kubebindv1alpha1.SchemeGroupVersion.WithResource("apiservicenamespaces").GroupResource(), upstreamNamespace)
+			}
+			return &kubebindv1alpha1.APIServiceNamespace{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind-zlp9m", Name: "default"},
+			}, nil
+		},
+		getConsumerObject: func(ns, name string) (*unstructured.Unstructured, error) {
+			if ns != downstream.GetNamespace() || name != downstream.GetName() {
+				return nil, errors.NewNotFound(schema.GroupResource{Group: "mongodb.example.com", Resource: "mongodbs"}, name)
+			}
+			return downstream, nil
+		},
+		recordEvent: func(obj *unstructured.Unstructured, eventType, reason, message string) {
+			events = append(events, recorded{obj.GetNamespace(), obj.GetName(), reason})
+		},
+	}
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind-zlp9m-default", Name: "mongo.1", UID: "event-uid"},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "mongodb.example.com/v1",
+			Kind:       "MongoDB",
+			Namespace:  "kube-bind-zlp9m-default",
+			Name:       "mongo",
+		},
+		Type:          corev1.EventTypeWarning,
+		Reason:        "ProvisioningFailed",
+		Count:         1,
+		LastTimestamp: metav1.Now(),
+	}
+	require.NoError(t, r.reconcile(context.Background(), provider, event))
+	require.Equal(t, []recorded{{"default", "mongo", "ProvisioningFailed"}}, events)
+
+	// unchanged Events are mirrored once
+	require.NoError(t, r.reconcile(context.Background(), provider, event))
+	require.Len(t, events, 1)
+
+	// repeated Events are mirrored again
+	event.Count = 2
+	require.NoError(t, r.reconcile(context.Background(), provider, event))
+	require.Len(t, events, 2)
+
+	// Events observed before the start are skipped
+	old := event.DeepCopy()
+	old.UID = "old-uid"
+	old.LastTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	require.NoError(t, r.reconcile(context.Background(), provider, old))
+	require.Len(t, events, 2)
+
+	// Events about other namespaces are skipped
+	foreign := event.DeepCopy()
+	foreign.UID = "foreign-uid"
+	foreign.InvolvedObject.Namespace = "kube-system"
+	require.NoError(t, r.reconcile(context.Background(), provider, foreign))
+	require.Len(t, events, 2)
+
+	// Events about other kinds are skipped
+	other := event.DeepCopy()
+	other.UID = "other-uid"
+	other.InvolvedObject.Kind = "Pod"
+	other.InvolvedObject.APIVersion = "v1"
+	require.NoError(t, r.reconcile(context.Background(), provider, other))
+	require.Len(t, events, 2)
+}
@@ -36,7 +36,6 @@ import (
 	dynamicclient "k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
-	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 )
@@ -48,7 +47,8 @@ const (
 type GetterInformer interface {
 	Get(ns, name string) (runtime.Object, error)
 	List(ns string) ([]runtime.Object, error)
-	AddEventHandler(handler cache.ResourceEventHandler)
+	// AddEventHandler adds a handler and returns a func removing it again.
+	AddEventHandler(handler cache.ResourceEventHandler) (remove func())
 
 	Start(ctx context.Context)
 	WaitForCacheSync(stopCh <-chan struct{}) map[schema.GroupVersionResource]bool
@@ -65,38 +65,44 @@ type DynamicMultiNamespaceInformer struct {
 	providerDynamicClient dynamicclient.Interface
 
 	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister]
+	tweakListOptions         dynamicinformer.TweakListOptionsFunc
 
 	lock               sync.RWMutex
 	namespaceInformers map[string]informers.GenericInformer
 	namespaceCancel    map[string]func()
-	handlers           []cache.ResourceEventHandler
+	handlers           map[int]cache.ResourceEventHandler
+	handlerID          int
 }
 
 func NewDynamicMultiNamespaceInformer(
 	gvr schema.GroupVersionResource,
 	providerNamespace string,
-	providerConfig *rest.Config,
+	providerDynamicClient dynamicclient.Interface,
 	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister],
-) (*DynamicMultiNamespaceInformer, error) {
-	providerConfig = rest.CopyConfig(providerConfig)
-	providerConfig = rest.AddUserAgent(providerConfig, controllerName)
-
-	providerDynamicClient, err := dynamicclient.NewForConfig(providerConfig)
-	if err != nil {
-		return nil, err
-	}
+) *DynamicMultiNamespaceInformer {
+	return NewFilteredDynamicMultiNamespaceInformer(gvr, providerNamespace, providerDynamicClient, serviceNamespaceInformer, nil)
+}
 
-	inf := DynamicMultiNamespaceInformer{
+// NewFilteredDynamicMultiNamespaceInformer is like NewDynamicMultiNamespaceInformer, but applies
+// tweakListOptions to the list and watch requests of the per-namespace informers.
+func NewFilteredDynamicMultiNamespaceInformer(
+	gvr schema.GroupVersionResource,
+	providerNamespace string,
+	providerDynamicClient dynamicclient.Interface,
+	serviceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister],
+	tweakListOptions dynamicinformer.TweakListOptionsFunc,
+) *DynamicMultiNamespaceInformer {
+	return &DynamicMultiNamespaceInformer{
 		gvr:                      gvr,
 		providerNamespace:        providerNamespace,
 		providerDynamicClient:    providerDynamicClient,
 		serviceNamespaceInformer: serviceNamespaceInformer,
+		tweakListOptions:         tweakListOptions,
 
 		namespaceInformers: map[string]informers.GenericInformer{},
 		namespaceCancel:    map[string]func(){},
+		handlers:           map[int]cache.ResourceEventHandler{},
 	}
-
-	return &inf, nil
 }
 
 func (inf *DynamicMultiNamespaceInformer) Start(ctx context.Context) {
@@ -184,33 +190,70 @@ func (inf *DynamicMultiNamespaceInformer) enqueueServiceNamespace(obj interface{
 
 	logger.V(1).Info("starting dynamic informer", "namespace", sns.Status.Namespace)
 	ctx, cancel := context.WithCancel(context.Background())
-	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(inf.providerDynamicClient, time.Minute*30, sns.Status.Namespace, nil)
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(inf.providerDynamicClient, time.Minute*30, sns.Status.Namespace, inf.tweakListOptions)
 	gvrInf := factory.ForResource(inf.gvr)
 	gvrInf.Lister() // to wire the GVR up in the informer factory
 	inf.namespaceCancel[name] = cancel
 	inf.namespaceInformers[name] = gvrInf
 
-	for _, h := range inf.handlers {
-		_, err = gvrInf.Informer().AddEventHandler(h)
-		if err != nil {
-			panic(err)
-		}
+	// one handler per namespace informer fanning out to the current handlers, such that these can be removed.
+	_, err = gvrInf.Informer().AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			for _, h := range inf.currentHandlers() {
+				h.OnAdd(obj, isInInitialList)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			for _, h := range inf.currentHandlers() {
+				h.OnUpdate(oldObj, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			for _, h := range inf.currentHandlers() {
+				h.OnDelete(obj)
+			}
+		},
+	})
+	if err != nil {
+		panic(err)
 	}
 
 	factory.Start(ctx.Done())
 }
 
-func (inf *DynamicMultiNamespaceInformer) AddEventHandler(handler cache.ResourceEventHandler) {
-	inf.lock.Lock()
-	defer inf.lock.Unlock()
+func (inf *DynamicMultiNamespaceInformer) currentHandlers() []cache.ResourceEventHandler {
+	inf.lock.RLock()
+	defer inf.lock.RUnlock()
+
+	handlers := make([]cache.ResourceEventHandler, 0, len(inf.handlers))
+	for _, h := range inf.handlers {
+		handlers = append(handlers, h)
+	}
+	return handlers
+}
 
+// AddEventHandler adds a handler for the objects of all namespaces. It receives add events for
+// the objects known already.
+func (inf *DynamicMultiNamespaceInformer) AddEventHandler(handler cache.ResourceEventHandler) func() {
+	inf.lock.Lock()
+	id := inf.handlerID
+	inf.handlers[id] = handler
+	inf.handlerID++
+	var objs []interface{}
 	for _, i := range inf.namespaceInformers {
-		_, err := i.Informer().AddEventHandler(handler)
-		if err != nil {
-			panic(err)
-		}
+		objs = append(objs, i.Informer().GetStore().List()...)
+	}
+	inf.lock.Unlock()
+
+	for _, obj := range objs {
+		handler.OnAdd(obj, true)
+	}
+
+	return func() {
+		inf.lock.Lock()
+		defer inf.lock.Unlock()
+		delete(inf.handlers, id)
 	}
-	inf.handlers = append(inf.handlers, handler)
 }
 
 func (inf *DynamicMultiNamespaceInformer) Get(ns, name string) (runtime.Object, error) {
@@ -303,11 +346,15 @@ func (w GetterInformerWrapper) List(ns string) ([]runtime.Object, error) {
 	return w.Delegate.ForResource(w.GVR).Lister().ByNamespace(ns).List(labels.Everything())
 }
 
-func (w GetterInformerWrapper) AddEventHandler(handler cache.ResourceEventHandler) {
-	_, err := w.Delegate.ForResource(w.GVR).Informer().AddEventHandler(handler)
+func (w GetterInformerWrapper) AddEventHandler(handler cache.ResourceEventHandler) func() {
+	informer := w.Delegate.ForResource(w.GVR).Informer()
+	registration, err := informer.AddEventHandler(handler)
 	if err != nil {
 		panic(err)
 	}
+	return func() {
+		informer.RemoveEventHandler(registration) // nolint:errcheck
+	}
 }
 
 func (w GetterInformerWrapper) Start(ctx context.Context) {
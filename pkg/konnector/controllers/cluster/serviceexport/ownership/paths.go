@@ -0,0 +1,96 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"reflect"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ProviderOwnedPaths returns the spec paths owned by the service provider.
+func ProviderOwnedPaths(ownership *kubebindv1alpha1.APIServiceExportFieldOwnership) []string {
+	if ownership == nil {
+		return nil
+	}
+	return ownership.ProviderOwnedPaths
+}
+
+// ConsumerOwnedPaths returns the status paths owned by the consumer.
+func ConsumerOwnedPaths(ownership *kubebindv1alpha1.APIServiceExportFieldOwnership) []string {
+	if ownership == nil {
+		return nil
+	}
+	return ownership.ConsumerOwnedPaths
+}
+
+// CopyPaths sets the given paths of to to the values of from. Paths missing in from are
+// removed from to. It returns true if to has been changed.
+func CopyPaths(from, to *unstructured.Unstructured, paths []string) (bool, error) {
+	changed := false
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		value, found, err := unstructured.NestedFieldCopy(from.Object, fields...)
+		if err != nil {
+			return false, err
+		}
+		existing, existingFound, err := unstructured.NestedFieldNoCopy(to.Object, fields...)
+		if err != nil {
+			return false, err
+		}
+		if found == existingFound && reflect.DeepEqual(value, existing) {
+			continue
+		}
+
+		changed = true
+		if !found {
+			unstructured.RemoveNestedField(to.Object, fields...)
+			continue
+		}
+		if err := unstructured.SetNestedField(to.Object, value, fields...); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// WithoutPaths returns a copy of value, the object field at root, with the given paths removed.
+func WithoutPaths(value interface{}, root string, paths []string) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	obj := map[string]interface{}{root: m}
+	copied := false
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		if fields[0] != root {
+			continue
+		}
+		if !copied {
+			obj = map[string]interface{}{root: runtime.DeepCopyJSONValue(m)}
+			copied = true
+		}
+		unstructured.RemoveNestedField(obj, fields...)
+	}
+	return obj[root]
+}
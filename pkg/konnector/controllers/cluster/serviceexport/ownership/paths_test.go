@@ -0,0 +1,65 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCopyPaths(t *testing.T) {
+	upstream := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"size":    "small",
+			"storage": map[string]interface{}{"class": "fast"},
+		},
+	}}
+	downstream := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"size":    "small",
+			"version": "1.0",
+		},
+	}}
+
+	paths := []string{"spec.storage.class", "spec.version"}
+	changed, err := CopyPaths(upstream, downstream, paths)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, map[string]interface{}{
+		"size":    "small",
+		"storage": map[string]interface{}{"class": "fast"},
+	}, downstream.Object["spec"])
+
+	changed, err = CopyPaths(upstream, downstream, paths)
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestWithoutPaths(t *testing.T) {
+	spec := map[string]interface{}{
+		"size":    "small",
+		"storage": map[string]interface{}{"class": "fast"},
+	}
+
+	require.Equal(t, map[string]interface{}{
+		"size":    "small",
+		"storage": map[string]interface{}{},
+	}, WithoutPaths(spec, "spec", []string{"spec.storage.class", "status.phase"}))
+	require.Equal(t, "fast", spec["storage"].(map[string]interface{})["class"], "input must not be mutated")
+}
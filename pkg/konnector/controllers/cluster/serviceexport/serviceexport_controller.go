@@ -22,12 +22,13 @@ import (
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
-	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	konnectorrbac "go.bytebuilders.dev/kube-bind/pkg/konnector/rbac"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
@@ -35,6 +36,8 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -52,6 +55,7 @@ func NewController(
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[apiextensionslisters.CustomResourceDefinitionLister],
 	providerInfos []*konnectormodels.ProviderInfo,
+	conversionWebhook *conversion.Webhook,
 ) (*controller, error) {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
@@ -60,19 +64,15 @@ func NewController(
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
 
-	for _, provider := range providerInfos {
-		provider.Config = rest.CopyConfig(provider.Config)
-		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
-
-		// create shared informer factory
-		var err error
-		if provider.BindClient, err = bindclient.NewForConfig(provider.Config); err != nil {
-			return nil, err
-		}
+	consumerClient, err := dynamicclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
 	}
-	for _, provider := range providerInfos {
-		provider.DynamicServiceNamespaceInformer = dynamic.NewDynamicInformer[bindlisters.APIServiceNamespaceLister](provider.BindInformer.KubeBind().V1alpha1().APIServiceNamespaces())
+	consumerKubeClient, err := kubernetes.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
 	}
+
 	c := &controller{
 		queue: queue,
 
@@ -82,10 +82,13 @@ func NewController(
 
 		reconciler: reconciler{
 			consumerConfig: consumerConfig,
+			consumerClient: consumerClient,
+			roleBinder:     konnectorrbac.NewRoleBinder(consumerKubeClient),
 
 			syncContext: map[syncInfo]syncContext{},
 
-			providerInfos: providerInfos,
+			providerInfos:     providerInfos,
+			conversionWebhook: conversionWebhook,
 
 			getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 				return crdInformer.Lister().Get(name)
@@ -112,7 +115,7 @@ func NewController(
 			indexers.ServiceNamespaceByNamespace: indexers.IndexServiceNamespaceByNamespace,
 		})
 
-		_, err := provider.BindInformer.KubeBind().V1alpha1().APIServiceExports().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		err := provider.AddEventHandler(provider.BindInformer.KubeBind().V1alpha1().APIServiceExports().Informer(), cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueServiceExport(logger, obj)
 			},
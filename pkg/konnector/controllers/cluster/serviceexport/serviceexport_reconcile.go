@@ -22,10 +22,16 @@ import (
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	kubebindhelpers "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/events"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/spec"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/status"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/providerpool"
+	konnectorrbac "go.bytebuilders.dev/kube-bind/pkg/konnector/rbac"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -48,10 +54,13 @@ type syncInfo struct {
 
 type reconciler struct {
 	consumerConfig *rest.Config
+	consumerClient dynamicclient.Interface
+	roleBinder     *konnectorrbac.RoleBinder // grants access to related and status resources per namespace
 	lock           sync.Mutex
 	syncContext    map[syncInfo]syncContext // by ClusterID and CRD name
 
-	providerInfos []*konnectormodels.ProviderInfo
+	providerInfos     []*konnectormodels.ProviderInfo
+	conversionWebhook *conversion.Webhook
 
 	getCRD            func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	getServiceBinding func(name string) (*v1alpha1.APIServiceBinding, error)
@@ -59,6 +68,7 @@ type reconciler struct {
 
 type syncContext struct {
 	generation int64
+	paused     bool
 	cancel     func()
 }
 
@@ -68,11 +78,15 @@ func (r *reconciler) reconcile(ctx context.Context, sync *syncInfo, export *v1al
 	if err := r.ensureControllers(ctx, sync, export); err != nil {
 		errs = append(errs, err)
 	}
+	r.ensureConversionTarget(sync, export)
 
 	if export != nil {
 		if err := r.ensureServiceBindingConditionCopied(ctx, export); err != nil {
 			errs = append(errs, err)
 		}
+		if err := r.ensureStorageVersionCondition(export); err != nil {
+			errs = append(errs, err)
+		}
 		if err := r.ensureCRDConditionsCopied(export); err != nil {
 			errs = append(errs, err)
 		}
@@ -81,6 +95,22 @@ func (r *reconciler) reconcile(ctx context.Context, sync *syncInfo, export *v1al
 	return utilerrors.NewAggregate(errs)
 }
 
+// ensureConversionTarget makes the conversion webhook of the konnector forward ConversionReviews
+// of the bound CRD to the service provider conversion webhook.
+func (r *reconciler) ensureConversionTarget(sync *syncInfo, export *v1alpha1.APIServiceExport) {
+	if export == nil || export.Spec.Conversion == nil {
+		r.conversionWebhook.Unregister(sync.exporterName, sync.clusterID)
+		return
+	}
+
+	provider, err := konnectormodels.GetProviderInfoWithClusterID(r.providerInfos, sync.clusterID)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	r.conversionWebhook.Register(export.Name, sync.clusterID, provider.KubeClient, export.Spec.Conversion)
+}
+
 func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, export *v1alpha1.APIServiceExport) error {
 	logger := klog.FromContext(ctx)
 
@@ -149,14 +179,19 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 		exporterName: export.Name,
 	}]
 	if found {
-		if c.generation == export.Generation {
+		if c.generation == export.Generation && c.paused == binding.Spec.Paused {
 			r.lock.Unlock()
 			return nil // all as expected
 		}
 
 		// technically, we could be less aggressive here if nothing big changed in the resource, e.g. just schemas. But ¯\_(ツ)_/¯
 
-		logger.V(1).Info("Stopping APIServiceExport sync", "reason", "GenerationChanged", "generation", export.Generation)
+		if c.paused != binding.Spec.Paused {
+			// restarting reconciles every object, which reports the Paused condition, or resyncs all objects on resume.
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "PausedChanged", "paused", binding.Spec.Paused)
+		} else {
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "GenerationChanged", "generation", export.Generation)
+		}
 		c.cancel()
 		delete(r.syncContext, syncInfo{
 			clusterID:    sync.clusterID,
@@ -165,93 +200,128 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 	}
 	r.lock.Unlock()
 
-	// start a new syncer
+	// start a new syncer, using the storage version of the provider on both sides
 
-	var syncVersion string
-	for _, v := range export.Spec.Versions {
-		if v.Served {
-			syncVersion = v.Name
-			break
-		}
-	}
+	syncVersion := exportStorageVersion(export)
 	gvr := runtimeschema.GroupVersionResource{Group: export.Spec.Group, Version: syncVersion, Resource: export.Spec.Names.Plural}
 
-	dynamicConsumerClient := dynamicclient.NewForConfigOrDie(r.consumerConfig)
-	consumerInf := dynamicinformer.NewDynamicSharedInformerFactory(dynamicConsumerClient, time.Minute*30)
+	consumerInf := dynamicinformer.NewDynamicSharedInformerFactory(r.consumerClient, time.Minute*30)
 
+	// provider informers are shared with the other bindings to the same provider namespace
+	var releases []func()
+	releaseInformers := func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+	scope := providerpool.ServiceNamespaces
+	if crd.Spec.Scope == apiextensionsv1.ClusterScoped && export.Spec.ClusterScopedIsolation == v1alpha1.IsolationNamespaced && export.Spec.InformerScope != v1alpha1.ClusterScope {
+		// cluster-scoped objects live as namespaced objects in the provider namespace
+		scope = providerpool.ProviderNamespace
+	} else if crd.Spec.Scope == apiextensionsv1.ClusterScoped || export.Spec.InformerScope == v1alpha1.ClusterScope {
+		scope = providerpool.AllNamespaces
+	}
+	eventInformers := map[string]multinsinformer.GetterInformer{}
+	eventsTweak := events.InvolvedKindTweakListOptions(export.Spec.Names.Kind)
 	for _, provider := range r.providerInfos {
-		dynamicProviderClient := dynamicclient.NewForConfigOrDie(provider.Config)
-
-		if pns, err := dynamicProviderClient.Resource(runtimeschema.GroupVersionResource{
-			Group:    "",
-			Version:  "v1",
-			Resource: "namespaces",
-		}).Get(ctx, provider.Namespace, metav1.GetOptions{}); err != nil {
+		if pns, err := provider.KubeClient.CoreV1().Namespaces().Get(ctx, provider.Namespace, metav1.GetOptions{}); err != nil {
+			releaseInformers()
 			return err
 		} else {
 			provider.NamespaceUID = string(pns.GetUID())
 		}
 
-		if crd.Spec.Scope == apiextensionsv1.ClusterScoped || export.Spec.InformerScope == v1alpha1.ClusterScope {
-			factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicProviderClient, time.Minute*30)
-			factory.ForResource(gvr).Lister() // wire the GVR up in the informer factory
-			provider.ProviderDynamicInformer = multinsinformer.GetterInformerWrapper{
-				GVR:      gvr,
-				Delegate: factory,
-			}
-		} else {
-			provider.ProviderDynamicInformer, err = multinsinformer.NewDynamicMultiNamespaceInformer(
-				gvr,
-				provider.Namespace,
-				provider.Config,
-				provider.DynamicServiceNamespaceInformer,
-			)
-			if err != nil {
-				return err
-			}
-		}
+		var release func()
+		provider.ProviderDynamicInformer, release = provider.Connection.DynamicInformer(providerpool.DynamicInformerKey{GVR: gvr, Scope: scope}, nil)
+		releases = append(releases, release)
+		eventInformers[provider.ClusterID], release = provider.Connection.DynamicInformer(providerpool.DynamicInformerKey{GVR: events.EventsGVR, Scope: scope, TweakName: "involvedObject.kind=" + export.Spec.Names.Kind}, eventsTweak)
+		releases = append(releases, release)
 	}
 
 	specCtrl, err := spec.NewController(
 		gvr,
+		export.Spec.ClusterScopedIsolation,
+		export.Spec.FieldOwnership,
+		export.Spec.RelatedResources,
+		export.Spec.ConflictPolicy,
+		export.Spec.ProviderDeletionPolicy,
+		r.bindingDeletionPolicy(export.Name),
+		r.bindingPaused(export.Name),
 		r.consumerConfig,
 		consumerInf.ForResource(gvr),
+		r.roleBinder.Ensure,
 		r.providerInfos,
 	)
 	if err != nil {
+		releaseInformers()
 		runtime.HandleError(err)
 		return nil // nothing we can do here
 	}
 	statusCtrl, err := status.NewController(
 		gvr,
+		export.Spec.ClusterScopedIsolation,
+		export.Spec.FieldOwnership,
+		kubebindhelpers.StatusResources(export),
+		r.bindingDeletionPolicy(export.Name),
+		r.bindingPaused(export.Name),
 		r.consumerConfig,
 		consumerInf.ForResource(gvr),
+		r.roleBinder.Ensure,
 		r.providerInfos,
 	)
 	if err != nil {
+		releaseInformers()
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
+
+	eventsCtrl, err := events.NewController(
+		gvr,
+		export.Spec.Names.Kind,
+		export.Spec.ClusterScopedIsolation,
+		r.consumerConfig,
+		consumerInf.ForResource(gvr),
+		r.providerInfos,
+		eventInformers,
+	)
+	if err != nil {
+		releaseInformers()
 		runtime.HandleError(err)
 		return nil // nothing we can do here
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
+	informersSynced := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		releaseInformers()
+
+		<-informersSynced
+		for _, provider := range r.providerInfos {
+			metrics.ForgetInformersSynced(metrics.NewLabels(export.Name, gvr, provider.ClusterID))
+		}
+	}()
 
 	consumerInf.Start(ctx.Done())
-	for _, provider := range r.providerInfos {
-		provider.ProviderDynamicInformer.Start(ctx)
-	}
 
 	go func() {
 		// to not block the main thread
+		defer close(informersSynced)
+
 		consumerSynced := consumerInf.WaitForCacheSync(ctx.Done())
 		logger.V(2).Info("Synced informers", "consumer", consumerSynced)
 
 		for _, provider := range r.providerInfos {
 			providerSynced := provider.ProviderDynamicInformer.WaitForCacheSync(ctx.Done())
 			logger.V(2).Info("Synced informers", "provider", providerSynced)
+			eventsSynced := eventInformers[provider.ClusterID].WaitForCacheSync(ctx.Done())
+			logger.V(2).Info("Synced informers", "providerEvents", eventsSynced)
+			metrics.SetInformersSynced(metrics.NewLabels(export.Name, gvr, provider.ClusterID), allSynced(consumerSynced) && allSynced(providerSynced) && allSynced(eventsSynced))
 		}
 
 		go specCtrl.Start(ctx, 1)
 		go statusCtrl.Start(ctx, 1)
+		go eventsCtrl.Start(ctx, 1)
 	}()
 
 	r.lock.Lock()
@@ -267,12 +337,35 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 		exporterName: export.Name,
 	}] = syncContext{
 		generation: export.Generation,
+		paused:     binding.Spec.Paused,
 		cancel:     cancel,
 	}
 
 	return utilerrors.NewAggregate(errs)
 }
 
+// bindingDeletionPolicy returns a func returning the deletion policy of the APIServiceBinding of the given name.
+func (r *reconciler) bindingDeletionPolicy(name string) func() (v1alpha1.DeletionPolicy, error) {
+	return func() (v1alpha1.DeletionPolicy, error) {
+		binding, err := r.getServiceBinding(name)
+		if err != nil {
+			return "", err
+		}
+		return binding.Spec.DeletionPolicy, nil
+	}
+}
+
+// bindingPaused returns a func telling whether syncing is paused by the APIServiceBinding of the given name.
+func (r *reconciler) bindingPaused(name string) func() (bool, error) {
+	return func() (bool, error) {
+		binding, err := r.getServiceBinding(name)
+		if err != nil {
+			return false, err
+		}
+		return binding.Spec.Paused, nil
+	}
+}
+
 func (r *reconciler) ensureServiceBindingConditionCopied(ctx context.Context, export *v1alpha1.APIServiceExport) error {
 	binding, err := r.getServiceBinding(export.Name)
 	if err != nil && !errors.IsNotFound(err) {
@@ -361,3 +454,64 @@ func (r *reconciler) ensureCRDConditionsCopied(export *v1alpha1.APIServiceExport
 
 	return nil
 }
+
+func (r *reconciler) ensureStorageVersionCondition(export *v1alpha1.APIServiceExport) error {
+	crd, err := r.getCRD(export.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		return nil // nothing to compare.
+	}
+
+	providerVersion := exportStorageVersion(export)
+	consumerVersion := crdStorageVersion(crd)
+	if providerVersion != consumerVersion {
+		conditions.MarkFalse(
+			export,
+			v1alpha1.APIServiceExportConditionStorageVersionInSync,
+			"StorageVersionMismatch",
+			conditionsapi.ConditionSeverityWarning,
+			"Storage version %q in the consumer cluster differs from storage version %q in the service provider cluster. Objects are synced as %q.",
+			consumerVersion, providerVersion, providerVersion,
+		)
+		return nil
+	}
+
+	conditions.MarkTrue(export, v1alpha1.APIServiceExportConditionStorageVersionInSync)
+
+	return nil
+}
+
+// allSynced returns whether all informers of a factory have synced.
+func allSynced(synced map[runtimeschema.GroupVersionResource]bool) bool {
+	for _, ok := range synced {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// exportStorageVersion returns the storage version of the APIServiceExport, or the first
+// served version if no storage version is marked.
+func exportStorageVersion(export *v1alpha1.APIServiceExport) string {
+	var served string
+	for _, v := range export.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+		if v.Served && served == "" {
+			served = v.Name
+		}
+	}
+	return served
+}
+
+func crdStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
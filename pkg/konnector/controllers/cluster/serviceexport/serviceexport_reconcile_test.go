@@ -74,6 +74,52 @@ func TestEnsureCRDConditionsCopied(t *testing.T) {
 	}
 }
 
+func TestEnsureStorageVersionCondition(t *testing.T) {
+	tests := []struct {
+		name            string
+		consumerVersion string
+		exportVersions  []kubebindv1alpha1.APIServiceExportVersion
+		expectedStatus  metav1.ConditionStatus
+	}{
+		{
+			name:            "same storage version",
+			consumerVersion: "v1",
+			exportVersions: []kubebindv1alpha1.APIServiceExportVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+			expectedStatus: metav1.ConditionTrue,
+		},
+		{
+			name:            "different storage version",
+			consumerVersion: "v1alpha1",
+			exportVersions: []kubebindv1alpha1.APIServiceExportVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+			expectedStatus: metav1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := newCRD("foo", nil)
+			crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true, Storage: tt.consumerVersion == "v1alpha1"},
+				{Name: "v1", Served: true, Storage: tt.consumerVersion == "v1"},
+			}
+			r := &reconciler{
+				getCRD: newGetCRD("foo", crd),
+			}
+			export := newExport("foo", nil)
+			export.Spec.Versions = tt.exportVersions
+			require.NoError(t, r.ensureStorageVersionCondition(export))
+			require.Len(t, export.Status.Conditions, 1)
+			require.Equal(t, kubebindv1alpha1.APIServiceExportConditionStorageVersionInSync, export.Status.Conditions[0].Type)
+			require.Equal(t, tt.expectedStatus, export.Status.Conditions[0].Status)
+		})
+	}
+}
+
 func newGetCRD(name string, crd *apiextensionsv1.CustomResourceDefinition) func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 	return func(n string) (*apiextensionsv1.CustomResourceDefinition, error) {
 		if n == name {
@@ -0,0 +1,129 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReconcileDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		bindingPolicy kubebindv1alpha1.DeletionPolicy
+		annotation    string
+		released      string
+		deleting      bool
+		wantDeleted   bool
+		wantPatch     string
+		wantReason    string
+		wantErr       bool
+	}{
+		{name: "default", deleting: true, wantDeleted: true},
+		{name: "retain", bindingPolicy: kubebindv1alpha1.DeletionPolicyRetain, deleting: true, wantPatch: `{"metadata":{"annotations":{"kube-bind.appscode.com/released":"Retain"}}}`},
+		{name: "annotation overrides binding", bindingPolicy: kubebindv1alpha1.DeletionPolicyRetain, annotation: "Delete", deleting: true, wantDeleted: true},
+		{name: "orphan annotation", annotation: "Orphan", deleting: true, wantPatch: `{"metadata":{"annotations":{"kube-bind.appscode.com/released":"Orphan"}}}`},
+		{name: "invalid annotation", annotation: "Keep", deleting: true, wantErr: true},
+		{name: "released before", released: "Orphan", deleting: true},
+		{name: "adopt retained", released: "Retain", wantPatch: `{"metadata":{"annotations":{"kube-bind.appscode.com/released":null}}}`},
+		{name: "orphaned", released: "Orphan", wantReason: "Orphaned"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstream := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "mongodb.example.com/v1",
+				"kind":       "MongoDB",
+				"spec":       map[string]interface{}{"replicas": int64(1)},
+			}}
+			downstream.SetName("mongo")
+			downstream.SetFinalizers([]string{kubebindv1alpha1.DownstreamFinalizer})
+			if tt.annotation != "" {
+				downstream.SetAnnotations(map[string]string{kubebindv1alpha1.DeletionPolicyAnnotationKey: tt.annotation})
+			}
+			if tt.deleting {
+				now := metav1.Now()
+				downstream.SetDeletionTimestamp(&now)
+			}
+
+			upstream := downstream.DeepCopy()
+			upstream.SetDeletionTimestamp(nil)
+			upstream.SetFinalizers(nil)
+			if tt.released != "" {
+				upstream.SetAnnotations(map[string]string{kubebindv1alpha1.ReleasedAnnotationKey: tt.released})
+			}
+
+			var deleted bool
+			var patch string
+			var finalizers []string
+			var conditions []interface{}
+			r := &reconciler{
+				getBindingDeletionPolicy: func() (kubebindv1alpha1.DeletionPolicy, error) {
+					return tt.bindingPolicy, nil
+				},
+				getBindingPaused: func() (bool, error) {
+					return false, nil
+				},
+				getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+					return &konnectormodels.ProviderInfo{ClusterID: "cluster-a"}, nil
+				},
+				getProviderObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					return upstream, nil
+				},
+				deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
+					deleted = true
+					return nil
+				},
+				patchProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string, pt types.PatchType, data []byte) error {
+					patch = string(data)
+					return nil
+				},
+				updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					finalizers = obj.GetFinalizers()
+					return obj, nil
+				},
+				updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					conditions, _, _ = unstructured.NestedSlice(obj.Object, "status", "conditions")
+					return obj, nil
+				},
+			}
+
+			err := r.reconcile(context.Background(), downstream)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDeleted, deleted)
+			require.Equal(t, tt.wantPatch, patch)
+			if tt.deleting {
+				require.Empty(t, finalizers)
+			}
+			if tt.wantReason != "" {
+				require.Len(t, conditions, 1)
+				require.Equal(t, tt.wantReason, conditions[0].(map[string]interface{})["reason"])
+			}
+		})
+	}
+}
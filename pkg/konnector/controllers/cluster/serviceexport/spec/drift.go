@@ -0,0 +1,171 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/ownership"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+// specHash returns the hash of the spec of obj, without the fields owned by the service provider.
+func specHash(obj *unstructured.Unstructured, providerOwnedPaths []string) (string, error) {
+	spec, _, err := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(ownership.WithoutPaths(spec, "spec", providerOwnedPaths))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// upstreamSpecHash returns the hash of the spec of upstream, restricted to the fields the konnector
+// applies from the spec of obj. Fields defaulted in the service provider cluster, e.g. by webhooks
+// or controllers, do not count as a change of the upstream spec.
+func upstreamSpecHash(upstream, obj *unstructured.Unstructured, providerOwnedPaths []string) (string, error) {
+	spec, _, err := unstructured.NestedFieldNoCopy(upstream.Object, "spec")
+	if err != nil {
+		return "", err
+	}
+	applied, _, err := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+	if err != nil {
+		return "", err
+	}
+	projected := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if spec != nil {
+		projected.Object["spec"] = appliedFields(spec, applied)
+	}
+	return specHash(projected, providerOwnedPaths)
+}
+
+// appliedFields returns the fields of value which are also set in applied. Maps are restricted
+// recursively, any other value is kept as a whole.
+func appliedFields(value, applied interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	am, ok := applied.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	restricted := make(map[string]interface{}, len(am))
+	for k, v := range m {
+		if av, found := am[k]; found {
+			restricted[k] = appliedFields(v, av)
+		}
+	}
+	return restricted
+}
+
+// setSpecHash records the hash of the last synced spec on obj.
+func setSpecHash(obj *unstructured.Unstructured, hash string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[kubebindv1alpha1.LastSyncedSpecHashAnnotationKey] = hash
+	obj.SetAnnotations(annotations)
+}
+
+// resolveSyncConflict resolves a change of the upstream spec in the service provider cluster since
+// the last sync according to the conflict policy, and reports it on the downstream object.
+func (r *reconciler) resolveSyncConflict(ctx context.Context, provider *konnectormodels.ProviderInfo, obj, upstream *unstructured.Unstructured, ns, hash string) error {
+	logger := klog.FromContext(ctx).WithValues("policy", r.conflictPolicy)
+
+	switch r.conflictPolicy {
+	case kubebindv1alpha1.ConflictPolicyProviderWins:
+		logger.Info("Upstream spec changed in the service provider cluster, updating downstream object")
+		updated := obj.DeepCopy()
+		if _, err := ownership.CopyPaths(upstream, updated, []string{"spec"}); err != nil {
+			logger.Error(err, "failed to copy upstream spec")
+			return nil // nothing we can do
+		}
+		updatedHash, err := specHash(updated, r.providerOwnedPaths)
+		if err != nil {
+			logger.Error(err, "failed to hash upstream spec")
+			return nil // nothing we can do
+		}
+		setSpecHash(updated, updatedHash)
+		updated, err = r.updateConsumerObject(ctx, updated)
+		if err != nil {
+			return err
+		}
+		message := "The spec has been changed in the service provider cluster and has been taken over."
+		r.recordEvent(updated, corev1.EventTypeWarning, "SyncConflict", message)
+		return r.ensureConditions(ctx, updated, syncConflict(metav1.ConditionFalse, "ProviderWins", message))
+
+	case kubebindv1alpha1.ConflictPolicyHalt:
+		halted, err := syncconditions.IsConditionTrue(obj, kubebindv1alpha1.DownstreamConditionSyncConflict)
+		if err != nil {
+			logger.Error(err, "failed to get conditions")
+			return nil // nothing we can do
+		}
+		message := "The spec has been changed in the service provider cluster. Syncing is halted until both sides agree."
+		if !halted {
+			logger.Info("Upstream spec changed in the service provider cluster, halting sync")
+			r.recordEvent(obj, corev1.EventTypeWarning, "SyncConflict", message)
+		}
+		return r.ensureConditions(ctx, obj, syncConflict(metav1.ConditionTrue, "Halted", message))
+
+	default:
+		logger.Info("Upstream spec changed in the service provider cluster, overwriting upstream object")
+		conflict, err := r.applyUpstream(ctx, provider, obj, ns, hash, true)
+		if err != nil {
+			return err
+		}
+		message := "The spec has been changed in the service provider cluster and has been overwritten."
+		r.recordEvent(obj, corev1.EventTypeWarning, "SyncConflict", message)
+		return r.ensureConditions(ctx, obj, specInSync(conflict), syncConflict(metav1.ConditionFalse, "ConsumerWins", message))
+	}
+}
+
+// syncConflict sets the SyncConflict condition on the downstream object.
+func syncConflict(status metav1.ConditionStatus, reason, message string) func(obj *unstructured.Unstructured) (bool, error) {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		return syncconditions.SetCondition(obj, metav1.Condition{
+			Type:               kubebindv1alpha1.DownstreamConditionSyncConflict,
+			Status:             status,
+			ObservedGeneration: obj.GetGeneration(),
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+}
+
+// syncConflictResolved marks a halted sync of the downstream object as resolved.
+func syncConflictResolved(obj *unstructured.Unstructured) (bool, error) {
+	halted, err := syncconditions.IsConditionTrue(obj, kubebindv1alpha1.DownstreamConditionSyncConflict)
+	if err != nil || !halted {
+		return false, err
+	}
+	return syncConflict(metav1.ConditionFalse, "Resolved", "Downstream and upstream spec agree again.")(obj)
+}
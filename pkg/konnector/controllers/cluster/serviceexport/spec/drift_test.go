@@ -0,0 +1,133 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestReconcileDrift(t *testing.T) {
+	newObject := func(replicas int64) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "mongodb.example.com/v1",
+			"kind":       "MongoDB",
+			"spec":       map[string]interface{}{"replicas": replicas},
+		}}
+		obj.SetName("mongo")
+		return obj
+	}
+	synced, err := specHash(newObject(1), nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		policy          kubebindv1alpha1.ConflictPolicy
+		upstreamChanged bool
+		// upstreamDefaulted sets a field in the upstream spec that the konnector does not apply.
+		upstreamDefaulted bool
+		wantApplied       bool
+		wantForced        bool
+		wantReplicas      int64
+		wantCondition     string
+		wantEvent         bool
+	}{
+		{name: "consumer change", policy: kubebindv1alpha1.ConflictPolicyHalt, wantApplied: true, wantReplicas: 2},
+		{name: "provider defaulted field", upstreamDefaulted: true, wantApplied: true, wantReplicas: 2},
+		{name: "provider defaulted field and consumer change", policy: kubebindv1alpha1.ConflictPolicyHalt, upstreamDefaulted: true, wantApplied: true, wantReplicas: 2},
+		{name: "consumer wins", upstreamChanged: true, wantApplied: true, wantForced: true, wantReplicas: 2, wantCondition: "False", wantEvent: true},
+		{name: "provider wins", policy: kubebindv1alpha1.ConflictPolicyProviderWins, upstreamChanged: true, wantReplicas: 3, wantCondition: "False", wantEvent: true},
+		{name: "halt", policy: kubebindv1alpha1.ConflictPolicyHalt, upstreamChanged: true, wantReplicas: 2, wantCondition: "True", wantEvent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstream := newObject(2)
+			downstream.SetFinalizers([]string{kubebindv1alpha1.DownstreamFinalizer})
+			setSpecHash(downstream, synced)
+
+			upstream := newObject(1)
+			if tt.upstreamChanged {
+				upstream = newObject(3)
+			}
+			if tt.upstreamDefaulted {
+				require.NoError(t, unstructured.SetNestedField(upstream.Object, "WiredTiger", "spec", "storageEngine"))
+			}
+			setSpecHash(upstream, synced)
+
+			var applied, forced, evented bool
+			current := downstream
+			r := &reconciler{
+				conflictPolicy: tt.policy,
+				getBindingPaused: func() (bool, error) {
+					return false, nil
+				},
+				getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+					return &konnectormodels.ProviderInfo{ClusterID: "cluster-a"}, nil
+				},
+				getProviderObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					return upstream, nil
+				},
+				applyProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool) (*unstructured.Unstructured, error) {
+					applied, forced = true, force
+					hash, err := specHash(downstream, nil)
+					require.NoError(t, err)
+					require.Equal(t, hash, obj.GetAnnotations()[kubebindv1alpha1.LastSyncedSpecHashAnnotationKey])
+					return obj, nil
+				},
+				updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					current = obj
+					return obj, nil
+				},
+				updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					current = obj
+					return obj, nil
+				},
+				recordEvent: func(obj *unstructured.Unstructured, eventType, reason, message string) {
+					evented = true
+					require.Equal(t, "SyncConflict", reason)
+				},
+			}
+
+			require.NoError(t, r.reconcile(context.Background(), downstream))
+			require.Equal(t, tt.wantApplied, applied)
+			require.Equal(t, tt.wantForced, forced)
+			require.Equal(t, tt.wantEvent, evented)
+
+			replicas, _, err := unstructured.NestedInt64(current.Object, "spec", "replicas")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantReplicas, replicas)
+
+			conflict, err := syncconditions.IsConditionTrue(current, kubebindv1alpha1.DownstreamConditionSyncConflict)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCondition == "True", conflict)
+			conditions, _, err := unstructured.NestedSlice(current.Object, "status", "conditions")
+			require.NoError(t, err)
+			if tt.wantCondition == "" {
+				require.Empty(t, conditions)
+			} else {
+				require.Len(t, conditions, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// pausedBy returns the reason why syncing the downstream object is paused, or an empty string if
+// it is not paused.
+func (r *reconciler) pausedBy(obj *unstructured.Unstructured) (string, error) {
+	if obj.GetAnnotations()[kubebindv1alpha1.PausedAnnotationKey] == "true" {
+		return "PausedByAnnotation", nil
+	}
+	paused, err := r.getBindingPaused()
+	if err != nil {
+		return "", err
+	}
+	if paused {
+		return "PausedByAPIServiceBinding", nil
+	}
+	return "", nil
+}
+
+// pausedCondition sets the Paused condition on the downstream object.
+func pausedCondition(reason string) func(obj *unstructured.Unstructured) (bool, error) {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		message := "Syncing is paused by the APIServiceBinding."
+		if reason == "PausedByAnnotation" {
+			message = "Syncing is paused by the " + kubebindv1alpha1.PausedAnnotationKey + " annotation."
+		}
+		return syncconditions.SetCondition(obj, metav1.Condition{
+			Type:               kubebindv1alpha1.DownstreamConditionPaused,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: obj.GetGeneration(),
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+}
+
+// pausedCleared removes the Paused condition from the downstream object.
+func pausedCleared(obj *unstructured.Unstructured) (bool, error) {
+	return syncconditions.RemoveCondition(obj, kubebindv1alpha1.DownstreamConditionPaused)
+}
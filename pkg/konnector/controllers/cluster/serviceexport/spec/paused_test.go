@@ -0,0 +1,106 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestReconcilePaused(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotation    string
+		bindingPaused bool
+		wasPaused     bool
+		wantApplied   bool
+		wantCondition bool
+		wantReason    string
+	}{
+		{name: "not paused", wantApplied: true},
+		{name: "annotation", annotation: "true", wantCondition: true, wantReason: "PausedByAnnotation"},
+		{name: "other annotation value", annotation: "false", wantApplied: true},
+		{name: "binding", bindingPaused: true, wantCondition: true, wantReason: "PausedByAPIServiceBinding"},
+		{name: "resumed", wasPaused: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstream := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "mongodb.example.com/v1",
+				"kind":       "MongoDB",
+				"spec":       map[string]interface{}{"replicas": int64(1)},
+			}}
+			downstream.SetName("mongo")
+			downstream.SetFinalizers([]string{kubebindv1alpha1.DownstreamFinalizer})
+			if tt.annotation != "" {
+				downstream.SetAnnotations(map[string]string{kubebindv1alpha1.PausedAnnotationKey: tt.annotation})
+			}
+			if tt.wasPaused {
+				_, err := pausedCondition("PausedByAnnotation")(downstream)
+				require.NoError(t, err)
+			}
+
+			var applied bool
+			current := downstream
+			r := &reconciler{
+				getBindingPaused: func() (bool, error) {
+					return tt.bindingPaused, nil
+				},
+				getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+					return &konnectormodels.ProviderInfo{ClusterID: "cluster-a"}, nil
+				},
+				getProviderObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					return nil, errors.NewNotFound(schema.GroupResource{Group: "mongodb.example.com", Resource: "mongodbs"}, name)
+				},
+				applyProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool) (*unstructured.Unstructured, error) {
+					applied = true
+					return obj, nil
+				},
+				updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					current = obj
+					return obj, nil
+				},
+			}
+
+			require.NoError(t, r.reconcile(context.Background(), downstream))
+			require.Equal(t, tt.wantApplied, applied)
+
+			paused, err := syncconditions.IsConditionTrue(current, kubebindv1alpha1.DownstreamConditionPaused)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCondition, paused)
+			if tt.wantReason != "" {
+				conditions, _, err := unstructured.NestedSlice(current.Object, "status", "conditions")
+				require.NoError(t, err)
+				require.Equal(t, tt.wantReason, conditions[0].(map[string]interface{})["reason"])
+			}
+			if tt.wasPaused {
+				conditions, _, err := unstructured.NestedSlice(current.Object, "status", "conditions")
+				require.NoError(t, err)
+				require.Empty(t, conditions)
+			}
+		})
+	}
+}
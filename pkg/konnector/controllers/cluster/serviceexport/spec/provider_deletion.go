@@ -0,0 +1,82 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+// handleProviderDeletion applies the provider deletion policy to a downstream object which has been
+// synced before, but whose upstream object is missing. It returns false if the upstream object
+// should be recreated.
+func (r *reconciler) handleProviderDeletion(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, ns string) (bool, error) {
+	logger := klog.FromContext(ctx).WithValues("policy", r.providerDeletionPolicy)
+
+	if r.providerDeletionPolicy == "" || r.providerDeletionPolicy == kubebindv1alpha1.ProviderDeletionPolicyRecreate {
+		return false, nil
+	}
+
+	// the informer might not know the upstream object yet. Don't act on stale caches.
+	if _, err := r.getProviderObjectUncached(ctx, provider, ns, obj.GetName()); err == nil {
+		logger.V(2).Info("upstream object exists, waiting for the informer")
+		return true, r.requeue(obj, time.Second)
+	} else if !errors.IsNotFound(err) {
+		return true, err
+	}
+
+	switch r.providerDeletionPolicy {
+	case kubebindv1alpha1.ProviderDeletionPolicyPropagate:
+		logger.Info("Deleting downstream object because upstream has been deleted in the service provider cluster")
+		obj, err := r.removeDownstreamFinalizer(ctx, obj)
+		if err != nil {
+			return true, err
+		}
+		if err := r.deleteConsumerObject(ctx, obj.GetNamespace(), obj.GetName()); err != nil && !errors.IsNotFound(err) {
+			return true, err
+		}
+		return true, nil
+	case kubebindv1alpha1.ProviderDeletionPolicyMarkDeleted:
+		logger.V(1).Info("upstream has been deleted in the service provider cluster, not recreating it")
+		return true, r.ensureConditions(ctx, obj, func(obj *unstructured.Unstructured) (bool, error) {
+			return syncconditions.SetCondition(obj, metav1.Condition{
+				Type:               kubebindv1alpha1.DownstreamConditionProviderDeleted,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: obj.GetGeneration(),
+				Reason:             "UpstreamDeleted",
+				Message:            fmt.Sprintf("The upstream object has been deleted in the service provider cluster. Remove the %s annotation to recreate it.", kubebindv1alpha1.LastSyncedSpecHashAnnotationKey),
+			})
+		})
+	default:
+		return true, fmt.Errorf("unknown provider deletion policy %q", r.providerDeletionPolicy)
+	}
+}
+
+// providerDeletedCleared removes the ProviderDeleted condition from the downstream object.
+func providerDeletedCleared(obj *unstructured.Unstructured) (bool, error) {
+	return syncconditions.RemoveCondition(obj, kubebindv1alpha1.DownstreamConditionProviderDeleted)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestReconcileProviderDeletion(t *testing.T) {
+	gr := schema.GroupResource{Group: "mongodb.example.com", Resource: "mongodbs"}
+
+	tests := []struct {
+		name          string
+		policy        kubebindv1alpha1.ProviderDeletionPolicy
+		neverSynced   bool
+		staleCache    bool
+		wantApplied   bool
+		wantDeleted   bool
+		wantCondition bool
+		wantRequeue   bool
+	}{
+		{name: "default", wantApplied: true},
+		{name: "recreate", policy: kubebindv1alpha1.ProviderDeletionPolicyRecreate, wantApplied: true},
+		{name: "never synced", policy: kubebindv1alpha1.ProviderDeletionPolicyPropagate, neverSynced: true, wantApplied: true},
+		{name: "propagate", policy: kubebindv1alpha1.ProviderDeletionPolicyPropagate, wantDeleted: true},
+		{name: "mark deleted", policy: kubebindv1alpha1.ProviderDeletionPolicyMarkDeleted, wantCondition: true},
+		{name: "stale cache", policy: kubebindv1alpha1.ProviderDeletionPolicyPropagate, staleCache: true, wantRequeue: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstream := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "mongodb.example.com/v1",
+				"kind":       "MongoDB",
+				"spec":       map[string]interface{}{"replicas": int64(1)},
+			}}
+			downstream.SetName("mongo")
+			downstream.SetFinalizers([]string{kubebindv1alpha1.DownstreamFinalizer})
+			if !tt.neverSynced {
+				setSpecHash(downstream, "0123456789abcdef")
+			}
+
+			var applied, deleted, requeued bool
+			var finalizers []string
+			current := downstream
+			r := &reconciler{
+				providerDeletionPolicy: tt.policy,
+				getBindingPaused: func() (bool, error) {
+					return false, nil
+				},
+				getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+					return &konnectormodels.ProviderInfo{ClusterID: "cluster-a"}, nil
+				},
+				getProviderObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					return nil, errors.NewNotFound(gr, name)
+				},
+				getProviderObjectUncached: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					if tt.staleCache {
+						return downstream.DeepCopy(), nil
+					}
+					return nil, errors.NewNotFound(gr, name)
+				},
+				applyProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool) (*unstructured.Unstructured, error) {
+					applied = true
+					return obj, nil
+				},
+				updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					finalizers = obj.GetFinalizers()
+					return obj, nil
+				},
+				updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					current = obj
+					return obj, nil
+				},
+				deleteConsumerObject: func(ctx context.Context, ns, name string) error {
+					deleted = true
+					return nil
+				},
+				requeue: func(obj *unstructured.Unstructured, after time.Duration) error {
+					requeued = true
+					return nil
+				},
+			}
+
+			require.NoError(t, r.reconcile(context.Background(), downstream))
+			require.Equal(t, tt.wantApplied, applied)
+			require.Equal(t, tt.wantDeleted, deleted)
+			require.Equal(t, tt.wantRequeue, requeued)
+			if tt.wantDeleted {
+				require.Empty(t, finalizers)
+			}
+
+			marked, err := syncconditions.IsConditionTrue(current, kubebindv1alpha1.DownstreamConditionProviderDeleted)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCondition, marked)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const byRelatedResource = "byRelatedResource"
+
+// relatedResourceKey returns the index key of a related resource.
+func relatedResourceKey(kind kubebindv1alpha1.RelatedResourceKind, ns, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, ns, name)
+}
+
+// relatedResourceName returns the name of the related resource referenced by obj.
+func relatedResourceName(obj *unstructured.Unstructured, related kubebindv1alpha1.APIServiceExportRelatedResource) string {
+	name, _, err := unstructured.NestedString(obj.Object, strings.Split(related.NamePath, ".")...)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// indexByRelatedResource returns an index func for downstream objects by the related resources
+// they reference.
+func indexByRelatedResource(relatedResources []kubebindv1alpha1.APIServiceExportRelatedResource) func(obj interface{}) ([]string, error) {
+	return func(obj interface{}) ([]string, error) {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok || u.GetNamespace() == "" {
+			return nil, nil
+		}
+
+		var keys []string
+		for _, related := range relatedResources {
+			if name := relatedResourceName(u, related); name != "" {
+				keys = append(keys, relatedResourceKey(related.Kind, u.GetNamespace(), name))
+			}
+		}
+		return keys, nil
+	}
+}
+
+// ensureRelatedResources syncs the Secrets and ConfigMaps referenced by the downstream object into
+// the namespace of the upstream object, and releases copies which are not referenced anymore. Copies
+// are shared by all upstream objects referencing them, and deleted when the last of them releases them.
+func (r *reconciler) ensureRelatedResources(ctx context.Context, provider *konnectormodels.ProviderInfo, obj, upstream *unstructured.Unstructured) error {
+	logger := klog.FromContext(ctx)
+
+	if len(r.relatedResources) == 0 || obj.GetNamespace() == "" || upstream.GetUID() == "" {
+		return nil
+	}
+
+	var errs []error
+	referenced := map[kubebindv1alpha1.RelatedResourceKind]sets.Set[string]{}
+	for _, related := range r.relatedResources {
+		if referenced[related.Kind] == nil {
+			referenced[related.Kind] = sets.New[string]()
+		}
+
+		name := relatedResourceName(obj, related)
+		if name == "" {
+			continue
+		}
+		referenced[related.Kind].Insert(name)
+
+		downstream, err := r.getConsumerRelatedObject(ctx, related.Kind, obj.GetNamespace(), name)
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		} else if errors.IsNotFound(err) {
+			logger.V(2).Info("waiting for related resource", "kind", related.Kind, "name", name)
+			continue // the related resource will lead to a requeue
+		}
+
+		var owners []metav1.OwnerReference
+		var resourceVersion string
+		existing, err := r.getProviderRelatedObject(ctx, provider, related.Kind, upstream.GetNamespace(), name)
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		} else if err == nil {
+			if !isRelatedResourceCopy(existing) {
+				errs = append(errs, fmt.Errorf("%s %s/%s in the service provider cluster is not owned by %s", related.Kind, upstream.GetNamespace(), name, upstream.GetName()))
+				continue
+			}
+			owners, resourceVersion = relatedResourceOwners(existing), existing.GetResourceVersion()
+		}
+		owners = addRelatedResourceOwner(owners, upstream)
+
+		logger.V(2).Info("Applying related resource", "kind", related.Kind, "name", name, "owners", len(owners))
+		copied := relatedResourceCopy(downstream, upstream.GetNamespace(), owners)
+		copied.SetResourceVersion(resourceVersion) // do not drop owners added concurrently
+		if _, err := r.applyProviderRelatedObject(ctx, provider, copied); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// release copies which are not referenced anymore
+	for kind, names := range referenced {
+		copies, err := r.listProviderRelatedObjects(ctx, provider, kind, upstream.GetNamespace(), string(upstream.GetUID()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for i := range copies {
+			c := &copies[i]
+			if names.Has(c.GetName()) {
+				continue
+			}
+
+			owners := removeRelatedResourceOwner(relatedResourceOwners(c), upstream.GetUID())
+			if len(owners) == 0 {
+				logger.V(1).Info("Deleting related resource not referenced anymore", "kind", kind, "name", c.GetName())
+				if err := r.deleteProviderRelatedObject(ctx, provider, kind, c.GetNamespace(), c.GetName(), c.GetResourceVersion()); err != nil && !errors.IsNotFound(err) {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
+			logger.V(1).Info("Releasing related resource still referenced by other objects", "kind", kind, "name", c.GetName(), "owners", len(owners))
+			released := relatedResourceCopy(c, c.GetNamespace(), owners)
+			released.SetResourceVersion(c.GetResourceVersion())
+			if _, err := r.applyProviderRelatedObject(ctx, provider, released); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// isRelatedResourceCopy returns true if the object in the service provider cluster has been
+// synced as related resource, and is not a foreign object of the same name.
+func isRelatedResourceCopy(obj *unstructured.Unstructured) bool {
+	for key := range obj.GetLabels() {
+		if key == kubebindv1alpha1.RelatedResourceOwnerLabelKey || strings.HasPrefix(key, kubebindv1alpha1.RelatedResourceOwnerLabelPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// relatedResourceOwners returns the owner references of the upstream objects sharing a related
+// resource copy. Copies of older konnectors have a single owner in the legacy label.
+func relatedResourceOwners(obj *unstructured.Unstructured) []metav1.OwnerReference {
+	labels := obj.GetLabels()
+	var owners []metav1.OwnerReference
+	for _, ref := range obj.GetOwnerReferences() {
+		if _, found := labels[kubebindv1alpha1.RelatedResourceOwnerLabelPrefix+string(ref.UID)]; found || labels[kubebindv1alpha1.RelatedResourceOwnerLabelKey] == string(ref.UID) {
+			owners = append(owners, ref)
+		}
+	}
+	return owners
+}
+
+func addRelatedResourceOwner(owners []metav1.OwnerReference, upstream *unstructured.Unstructured) []metav1.OwnerReference {
+	owners = removeRelatedResourceOwner(owners, upstream.GetUID())
+	return append(owners, metav1.OwnerReference{
+		APIVersion: upstream.GetAPIVersion(),
+		Kind:       upstream.GetKind(),
+		Name:       upstream.GetName(),
+		UID:        upstream.GetUID(),
+	})
+}
+
+func removeRelatedResourceOwner(owners []metav1.OwnerReference, uid types.UID) []metav1.OwnerReference {
+	var remaining []metav1.OwnerReference
+	for _, ref := range owners {
+		if ref.UID != uid {
+			remaining = append(remaining, ref)
+		}
+	}
+	return remaining
+}
+
+// relatedResourceCopy returns the upstream copy of a downstream related resource in the given
+// namespace, owned by the given upstream objects to be garbage collected with the last of them.
+func relatedResourceCopy(downstream *unstructured.Unstructured, ns string, owners []metav1.OwnerReference) *unstructured.Unstructured {
+	copied := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range downstream.DeepCopy().Object {
+		if k != "metadata" && k != "status" {
+			copied.Object[k] = v
+		}
+	}
+	copied.SetNamespace(ns)
+	copied.SetName(downstream.GetName())
+	labels := map[string]string{}
+	for _, owner := range owners {
+		labels[kubebindv1alpha1.RelatedResourceOwnerLabelPrefix+string(owner.UID)] = "true"
+	}
+	copied.SetLabels(labels)
+	copied.SetOwnerReferences(owners)
+	return copied
+}
@@ -0,0 +1,133 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	kubebindhelpers "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// relatedInformers watches the related resources of downstream objects in the namespaces of these
+// objects only, started on first use. Hence, the konnector needs no cluster-wide access to Secrets
+// and ConfigMaps.
+type relatedInformers struct {
+	client dynamicclient.Interface
+	// bind grants the konnector access to the related resources in the namespace.
+	bind    func(ctx context.Context, ns string) error
+	handler func(kind kubebindv1alpha1.RelatedResourceKind) cache.ResourceEventHandler
+
+	lock      sync.Mutex
+	ctx       context.Context // nil until started
+	informers map[relatedInformerKey]*relatedInformer
+}
+
+type relatedInformerKey struct {
+	kind kubebindv1alpha1.RelatedResourceKind
+	ns   string
+}
+
+type relatedInformer struct {
+	informer cache.SharedIndexInformer
+	cancel   func()
+}
+
+func newRelatedInformers(client dynamicclient.Interface, bind func(ctx context.Context, ns string) error, handler func(kind kubebindv1alpha1.RelatedResourceKind) cache.ResourceEventHandler) *relatedInformers {
+	return &relatedInformers{
+		client:    client,
+		bind:      bind,
+		handler:   handler,
+		informers: map[relatedInformerKey]*relatedInformer{},
+	}
+}
+
+// start lets informers run from now on until ctx is done.
+func (i *relatedInformers) start(ctx context.Context) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.ctx = ctx
+}
+
+// get returns the related resource from the informer of its namespace, and starts the informer if
+// needed. Until the informer has synced, an error is returned for the caller to retry.
+func (i *relatedInformers) get(ctx context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+	informer, err := i.informerFor(ctx, kind, ns)
+	if err != nil {
+		return nil, err
+	}
+	if !informer.HasSynced() {
+		return nil, fmt.Errorf("waiting for the %s informer in namespace %s to sync", kind, ns)
+	}
+
+	obj, found, err := informer.GetIndexer().GetByKey(ns + "/" + name)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, errors.NewNotFound(kubebindhelpers.RelatedResourceGVR(kind).GroupResource(), name)
+	}
+	return obj.(*unstructured.Unstructured), nil
+}
+
+func (i *relatedInformers) informerFor(ctx context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns string) (cache.SharedIndexInformer, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	key := relatedInformerKey{kind: kind, ns: ns}
+	if existing, found := i.informers[key]; found {
+		return existing.informer, nil
+	}
+	if i.ctx == nil {
+		return nil, fmt.Errorf("related resource informers not started")
+	}
+
+	if err := i.bind(ctx, ns); err != nil {
+		return nil, err
+	}
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(i.client, kubebindhelpers.RelatedResourceGVR(kind), ns, 30*time.Minute, cache.Indexers{}, nil).Informer()
+	if _, err := informer.AddEventHandler(i.handler(kind)); err != nil {
+		return nil, err
+	}
+	informerCtx, cancel := context.WithCancel(i.ctx)
+	go informer.Run(informerCtx.Done())
+
+	i.informers[key] = &relatedInformer{informer: informer, cancel: cancel}
+	return informer, nil
+}
+
+// prune stops the informers of the namespaces not to keep, e.g. without downstream objects.
+func (i *relatedInformers) prune(keep func(ns string) bool) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for key, informer := range i.informers {
+		if !keep(key.ns) {
+			informer.cancel()
+			delete(i.informers, key)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRelatedResources(t *testing.T) {
+	related := []kubebindv1alpha1.APIServiceExportRelatedResource{
+		{Kind: kubebindv1alpha1.RelatedResourceKindSecret, NamePath: "spec.authSecret.name"},
+		{Kind: kubebindv1alpha1.RelatedResourceKindConfigMap, NamePath: "spec.configuration.name"},
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"authSecret": map[string]interface{}{"name": "mongo-auth"},
+		},
+	}}
+	obj.SetNamespace("default")
+	obj.SetName("mongo")
+
+	keys, err := indexByRelatedResource(related)(obj)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Secret/default/mongo-auth"}, keys)
+
+	downstream := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "Opaque",
+		"data":       map[string]interface{}{"password": "c2VjcmV0"},
+	}}
+	downstream.SetNamespace("default")
+	downstream.SetName("mongo-auth")
+	downstream.SetResourceVersion("42")
+
+	upstream := &unstructured.Unstructured{}
+	upstream.SetAPIVersion("mongodb.example.com/v1")
+	upstream.SetKind("MongoDB")
+	upstream.SetNamespace("kube-bind-zlp9m-default")
+	upstream.SetName("mongo")
+	upstream.SetUID("real-uid")
+
+	copied := relatedResourceCopy(downstream, upstream.GetNamespace(), addRelatedResourceOwner(nil, upstream))
+	require.Equal(t, "kube-bind-zlp9m-default", copied.GetNamespace())
+	require.Equal(t, "mongo-auth", copied.GetName())
+	require.Empty(t, copied.GetResourceVersion())
+	require.Equal(t, "true", copied.GetLabels()[kubebindv1alpha1.RelatedResourceOwnerLabelPrefix+"real-uid"])
+	require.Len(t, copied.GetOwnerReferences(), 1)
+	require.Equal(t, downstream.Object["data"], copied.Object["data"])
+	require.True(t, isRelatedResourceCopy(copied))
+	require.Equal(t, copied.GetOwnerReferences(), relatedResourceOwners(copied))
+}
+
+func TestSharedRelatedResources(t *testing.T) {
+	related := []kubebindv1alpha1.APIServiceExportRelatedResource{
+		{Kind: kubebindv1alpha1.RelatedResourceKindSecret, NamePath: "spec.authSecret.name"},
+	}
+
+	downstreamSecret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"data":       map[string]interface{}{"password": "c2VjcmV0"},
+	}}
+	downstreamSecret.SetNamespace("default")
+	downstreamSecret.SetName("shared-auth")
+
+	downstreamObject := func(name, secret string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{},
+		}}
+		if secret != "" {
+			obj.Object["spec"] = map[string]interface{}{"authSecret": map[string]interface{}{"name": secret}}
+		}
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+	upstreamObject := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("mongodb.example.com/v1")
+		obj.SetKind("MongoDB")
+		obj.SetNamespace("kube-bind-zlp9m-default")
+		obj.SetName(name)
+		obj.SetUID(types.UID(name + "-uid"))
+		return obj
+	}
+
+	// the provider cluster
+	var provider *unstructured.Unstructured
+	var deleted bool
+	r := &reconciler{
+		relatedResources: related,
+		getConsumerRelatedObject: func(_ context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+			return downstreamSecret, nil
+		},
+		getProviderRelatedObject: func(ctx context.Context, _ *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+			if provider == nil {
+				return nil, errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+			}
+			return provider.DeepCopy(), nil
+		},
+		listProviderRelatedObjects: func(ctx context.Context, _ *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, ownerUID string) ([]unstructured.Unstructured, error) {
+			if provider == nil {
+				return nil, nil
+			}
+			if _, found := provider.GetLabels()[kubebindv1alpha1.RelatedResourceOwnerLabelPrefix+ownerUID]; !found {
+				return nil, nil
+			}
+			return []unstructured.Unstructured{*provider.DeepCopy()}, nil
+		},
+		applyProviderRelatedObject: func(ctx context.Context, _ *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			provider = obj.DeepCopy()
+			return provider, nil
+		},
+		deleteProviderRelatedObject: func(ctx context.Context, _ *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, name, resourceVersion string) error {
+			provider, deleted = nil, true
+			return nil
+		},
+	}
+
+	ctx := context.Background()
+	a, b := upstreamObject("a"), upstreamObject("b")
+	require.NoError(t, r.ensureRelatedResources(ctx, nil, downstreamObject("a", "shared-auth"), a))
+	require.NoError(t, r.ensureRelatedResources(ctx, nil, downstreamObject("b", "shared-auth"), b))
+	require.Len(t, relatedResourceOwners(provider), 2)
+
+	// a releases the copy, which b still references.
+	require.NoError(t, r.ensureRelatedResources(ctx, nil, downstreamObject("a", ""), a))
+	require.False(t, deleted)
+	require.Len(t, relatedResourceOwners(provider), 1)
+	require.Equal(t, b.GetUID(), relatedResourceOwners(provider)[0].UID)
+	require.NotContains(t, provider.GetLabels(), kubebindv1alpha1.RelatedResourceOwnerLabelPrefix+"a-uid")
+
+	// b releases it as the last owner.
+	require.NoError(t, r.ensureRelatedResources(ctx, nil, downstreamObject("b", ""), b))
+	require.True(t, deleted)
+
+	// a foreign Secret of the same name is not taken over.
+	provider = downstreamSecret.DeepCopy()
+	provider.SetNamespace("kube-bind-zlp9m-default")
+	require.Error(t, r.ensureRelatedResources(ctx, nil, downstreamObject("a", "shared-auth"), a))
+}
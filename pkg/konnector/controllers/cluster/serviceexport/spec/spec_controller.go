@@ -23,11 +23,14 @@ import (
 	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
-	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	kubebindhelpers "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/ownership"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -38,8 +41,12 @@ import (
 	dynamicclient "k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamiclister"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
@@ -48,51 +55,84 @@ import (
 const (
 	controllerName = "kube-bind-konnector-cluster-spec"
 
-	applyManager = "kube-bind.appscode.com"
+	applyManager = "kube-bind-konnector"
+
+	// legacyApplyManager is the field manager used by older konnectors, forcing their applies.
+	legacyApplyManager = "kube-bind.appscode.com"
 )
 
 // NewController returns a new controller reconciling downstream objects to upstream.
 func NewController(
 	gvr schema.GroupVersionResource,
+	isolation kubebindv1alpha1.Isolation,
+	fieldOwnership *kubebindv1alpha1.APIServiceExportFieldOwnership,
+	relatedResources []kubebindv1alpha1.APIServiceExportRelatedResource,
+	conflictPolicy kubebindv1alpha1.ConflictPolicy,
+	providerDeletionPolicy kubebindv1alpha1.ProviderDeletionPolicy,
+	bindingDeletionPolicy func() (kubebindv1alpha1.DeletionPolicy, error),
+	bindingPaused func() (bool, error),
 	consumerConfig *rest.Config,
 	consumerDynamicInformer informers.GenericInformer,
+	bindRelatedResources func(ctx context.Context, ns string) error,
 	providerInfos []*konnectormodels.ProviderInfo,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	metricLabels := metrics.NewLabels(gvr.GroupResource().String(), gvr, konnectormodels.ClusterIDs(providerInfos)...)
+	queue := metrics.NewRateLimitingQueue(controllerName, metricLabels)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
-	for _, provider := range providerInfos {
-		provider.Config = rest.CopyConfig(provider.Config)
-		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
-
-		var err error
-		provider.Client, err = dynamicclient.NewForConfig(provider.Config)
-		if err != nil {
-			return nil, err
-		}
-		provider.BindClient, err = bindclient.NewForConfig(provider.Config)
-		if err != nil {
-			return nil, err
-		}
-	}
 	consumerClient, err := dynamicclient.NewForConfig(consumerConfig)
 	if err != nil {
 		return nil, err
 	}
+	consumerKubeClient, err := kubernetes.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcaster := record.NewBroadcaster()
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerName})
 
 	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr)
-	c := &controller{
-		queue: queue,
+	var c *controller
+	related := newRelatedInformers(consumerClient, bindRelatedResources, func(kind kubebindv1alpha1.RelatedResourceKind) cache.ResourceEventHandler {
+		return cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueRelated(logger, kind, obj)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				c.enqueueRelated(logger, kind, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.enqueueRelated(logger, kind, obj)
+			},
+		}
+	})
+	c = &controller{
+		queue:        queue,
+		metricLabels: metricLabels,
 
-		consumerClient: consumerClient,
+		consumerClient:     consumerClient,
+		consumerKubeClient: consumerKubeClient,
+		broadcaster:        broadcaster,
 
 		consumerDynamicLister:  dynamicConsumerLister,
 		consumerDynamicIndexer: consumerDynamicInformer.Informer().GetIndexer(),
+		relatedInformers:       related,
 
 		providerInfos: providerInfos,
 
+		isolation: isolation,
+
 		reconciler: reconciler{
+			providerOwnedPaths: ownership.ProviderOwnedPaths(fieldOwnership),
+			relatedResources:   relatedResources,
+			conflictPolicy:     conflictPolicy,
+
+			providerDeletionPolicy:   providerDeletionPolicy,
+			getBindingDeletionPolicy: bindingDeletionPolicy,
+			getBindingPaused:         bindingPaused,
+
 			getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
 				anno := obj.GetAnnotations()
 				clusterID := anno[konnectormodels.AnnotationProviderClusterID]
@@ -124,39 +164,27 @@ func NewController(
 					}
 					return obj.(*unstructured.Unstructured), nil
 				}
-				got, err := provider.ProviderDynamicInformer.Get(ns, clusterscoped.Prepend(name, provider.Namespace))
+				got, err := provider.ProviderDynamicInformer.Get(clusterscoped.UpstreamNamespacedName(name, provider.Namespace, isolation))
 				if err != nil {
 					return nil, err
 				}
 				obj := got.(*unstructured.Unstructured).DeepCopy()
-				err = clusterscoped.TranslateFromUpstream(obj)
+				err = clusterscoped.ToDownstream(obj, isolation)
 				if err != nil {
 					return nil, err
 				}
 				return obj, nil
 			},
-			createProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-				if ns := obj.GetNamespace(); ns != "" {
-					return provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
-				}
-				err := clusterscoped.TranslateFromDownstream(obj, provider.Namespace, provider.NamespaceUID)
-				if err != nil {
-					return nil, err
-				}
-				created, err := provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
-				if err != nil {
-					return nil, err
-				}
-				err = clusterscoped.TranslateFromUpstream(created)
-				if err != nil {
-					return nil, err
+			getProviderObjectUncached: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+				if ns == "" {
+					ns, name = clusterscoped.UpstreamNamespacedName(name, provider.Namespace, isolation)
 				}
-				return created, nil
+				return provider.Client.Resource(gvr).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
 			},
-			updateProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			applyProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool) (*unstructured.Unstructured, error) {
 				ns := obj.GetNamespace()
 				if ns == "" {
-					if err := clusterscoped.TranslateFromDownstream(obj, provider.Namespace, provider.NamespaceUID); err != nil {
+					if err := clusterscoped.ToUpstream(obj, provider.Namespace, provider.NamespaceUID, isolation); err != nil {
 						return nil, err
 					}
 				}
@@ -165,29 +193,77 @@ func NewController(
 					return nil, err
 				}
 				patched, err := provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Patch(ctx,
-					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: ptr.To(true)},
+					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: ptr.To(force)},
 				)
 				if err != nil {
 					return nil, err
 				}
 				if ns == "" {
-					err = clusterscoped.TranslateFromUpstream(patched)
-					if err != nil {
+					if err := clusterscoped.ToDownstream(patched, isolation); err != nil {
 						return nil, err
 					}
-					return patched, nil
 				}
 				return patched, nil
 			},
+			patchProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string, pt types.PatchType, data []byte) error {
+				if ns == "" {
+					ns, name = clusterscoped.UpstreamNamespacedName(name, provider.Namespace, isolation)
+				}
+				_, err := provider.Client.Resource(gvr).Namespace(ns).Patch(ctx, name, pt, data, metav1.PatchOptions{})
+				return err
+			},
 			deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
 				if ns == "" {
-					name = clusterscoped.Prepend(name, provider.Namespace)
+					ns, name = clusterscoped.UpstreamNamespacedName(name, provider.Namespace, isolation)
 				}
 				return provider.Client.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
 			},
 			updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 				return consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
 			},
+			updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				return consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+			},
+			deleteConsumerObject: func(ctx context.Context, ns, name string) error {
+				return consumerClient.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			getConsumerRelatedObject: func(ctx context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+				return related.get(ctx, kind, ns, name)
+			},
+			getProviderRelatedObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+				return provider.Client.Resource(kubebindhelpers.RelatedResourceGVR(kind)).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+			},
+			listProviderRelatedObjects: func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, ownerUID string) ([]unstructured.Unstructured, error) {
+				var items []unstructured.Unstructured
+				// copies of older konnectors carry the legacy label with a single owner
+				for _, selector := range []string{kubebindv1alpha1.RelatedResourceOwnerLabelPrefix + ownerUID, kubebindv1alpha1.RelatedResourceOwnerLabelKey + "=" + ownerUID} {
+					list, err := provider.Client.Resource(kubebindhelpers.RelatedResourceGVR(kind)).Namespace(ns).List(ctx, metav1.ListOptions{
+						LabelSelector: selector,
+					})
+					if err != nil {
+						return nil, err
+					}
+					items = append(items, list.Items...)
+				}
+				return items, nil
+			},
+			applyProviderRelatedObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				data, err := json.Marshal(obj.Object)
+				if err != nil {
+					return nil, err
+				}
+				return provider.Client.Resource(kubebindhelpers.RelatedResourceGVR(kubebindv1alpha1.RelatedResourceKind(obj.GetKind()))).Namespace(obj.GetNamespace()).Patch(ctx,
+					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager},
+				)
+			},
+			deleteProviderRelatedObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, name, resourceVersion string) error {
+				return provider.Client.Resource(kubebindhelpers.RelatedResourceGVR(kind)).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{
+					Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion},
+				})
+			},
+			recordEvent: func(obj *unstructured.Unstructured, eventType, reason, message string) {
+				recorder.Event(obj, eventType, reason, message)
+			},
 			requeue: func(obj *unstructured.Unstructured, after time.Duration) error {
 				key, err := cache.MetaNamespaceKeyFunc(obj)
 				if err != nil {
@@ -199,6 +275,14 @@ func NewController(
 		},
 	}
 
+	if len(relatedResources) > 0 {
+		if err := consumerDynamicInformer.Informer().AddIndexers(cache.Indexers{
+			byRelatedResource: indexByRelatedResource(relatedResources),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
 	_, err = consumerDynamicInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			c.enqueueConsumer(logger, obj)
@@ -233,15 +317,21 @@ func NewController(
 
 // controller reconciles downstream objects to upstream.
 type controller struct {
-	queue workqueue.RateLimitingInterface
+	queue        workqueue.RateLimitingInterface
+	metricLabels metrics.Labels
 
-	consumerClient dynamicclient.Interface
+	consumerClient     dynamicclient.Interface
+	consumerKubeClient kubernetes.Interface
+	broadcaster        record.EventBroadcaster
 
 	consumerDynamicLister  dynamiclister.Lister
 	consumerDynamicIndexer cache.Indexer
+	relatedInformers       *relatedInformers
 
 	providerInfos []*konnectormodels.ProviderInfo
 
+	isolation kubebindv1alpha1.Isolation
+
 	reconciler
 }
 
@@ -268,6 +358,12 @@ func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormode
 		return
 	}
 
+	if downstreamName, ok := clusterscoped.DownstreamName(ns, name, provider.Namespace, c.isolation); ok {
+		logger.V(2).Info("queueing Unstructured", "key", downstreamName)
+		c.queue.Add(downstreamName)
+		return
+	}
+
 	if ns != "" {
 		sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, ns)
 		if err != nil {
@@ -288,13 +384,35 @@ func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormode
 		return
 	}
 
-	if clusterscoped.Behead(upstreamKey, provider.Namespace) == upstreamKey {
-		logger.V(3).Info("skipping because consumer mismatch", "upstreamKey", upstreamKey)
+	logger.V(3).Info("skipping because consumer mismatch", "upstreamKey", upstreamKey)
+}
+
+func (c *controller) enqueueRelated(logger klog.Logger, kind kubebindv1alpha1.RelatedResourceKind, obj interface{}) {
+	relatedKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
 		return
 	}
-	downstreamKey := clusterscoped.Behead(upstreamKey, provider.Namespace)
-	logger.V(2).Info("queueing Unstructured", "key", downstreamKey)
-	c.queue.Add(downstreamKey)
+	ns, name, err := cache.SplitMetaNamespaceKey(relatedKey)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	objs, err := c.consumerDynamicIndexer.ByIndex(byRelatedResource, relatedResourceKey(kind, ns, name))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, obj := range objs {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		logger.V(2).Info("queueing Unstructured", "key", key, "reason", kind, "relatedKey", relatedKey)
+		c.queue.Add(key)
+	}
 }
 
 func (c *controller) enqueueServiceNamespace(logger klog.Logger, provider *konnectormodels.ProviderInfo, obj interface{}) {
@@ -331,6 +449,7 @@ func (c *controller) enqueueServiceNamespace(logger klog.Logger, provider *konne
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
+	defer metrics.Forget(controllerName, c.metricLabels)
 	defer c.queue.ShutDown()
 
 	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
@@ -338,6 +457,9 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 	logger.Info("Starting controller")
 	defer logger.Info("Shutting down controller")
 
+	c.broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.consumerKubeClient.CoreV1().Events("")})
+	defer c.broadcaster.Shutdown()
+
 	for _, provider := range c.providerInfos {
 		provider.DynamicServiceNamespaceInformer.Informer().AddDynamicEventHandler(ctx, controllerName, cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
@@ -352,6 +474,9 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 		})
 	}
 
+	c.relatedInformers.start(ctx)
+	go wait.UntilWithContext(ctx, c.pruneRelatedInformers, time.Minute)
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
@@ -359,6 +484,14 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 	<-ctx.Done()
 }
 
+// pruneRelatedInformers stops watching related resources in namespaces without downstream objects.
+func (c *controller) pruneRelatedInformers(_ context.Context) {
+	c.relatedInformers.prune(func(ns string) bool {
+		objs, err := c.consumerDynamicIndexer.ByIndex(cache.NamespaceIndex, ns)
+		return err != nil || len(objs) > 0
+	})
+}
+
 func (c *controller) startWorker(ctx context.Context) {
 	defer runtime.HandleCrash()
 
@@ -382,7 +515,9 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	err := c.process(ctx, key)
+	metrics.ObserveSync(controllerName, c.metricLabels, err)
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
@@ -20,29 +20,51 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/ownership"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
 )
 
 type reconciler struct {
+	providerOwnedPaths []string
+	relatedResources   []v1alpha1.APIServiceExportRelatedResource
+	conflictPolicy     v1alpha1.ConflictPolicy
+
+	providerDeletionPolicy   v1alpha1.ProviderDeletionPolicy
+	getBindingDeletionPolicy func() (v1alpha1.DeletionPolicy, error)
+	getBindingPaused         func() (bool, error)
+
 	getProviderInfo        func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error)
 	getServiceNamespace    func(provider *konnectormodels.ProviderInfo, name string) (*v1alpha1.APIServiceNamespace, error)
 	createServiceNamespace func(ctx context.Context, provider *konnectormodels.ProviderInfo, sn *v1alpha1.APIServiceNamespace) (*v1alpha1.APIServiceNamespace, error)
 
-	getProviderObject    func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
-	createProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
-	updateProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
-	deleteProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
+	getProviderObject         func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
+	getProviderObjectUncached func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
+	applyProviderObject       func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool) (*unstructured.Unstructured, error)
+	patchProviderObject       func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string, pt types.PatchType, data []byte) error
+	deleteProviderObject      func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
+
+	updateConsumerObject       func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	updateConsumerObjectStatus func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	deleteConsumerObject       func(ctx context.Context, ns, name string) error
 
-	updateConsumerObject func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	getConsumerRelatedObject    func(ctx context.Context, kind v1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error)
+	getProviderRelatedObject    func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind v1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error)
+	listProviderRelatedObjects  func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind v1alpha1.RelatedResourceKind, ns, ownerUID string) ([]unstructured.Unstructured, error)
+	applyProviderRelatedObject  func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	deleteProviderRelatedObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind v1alpha1.RelatedResourceKind, ns, name, resourceVersion string) error
+
+	recordEvent func(obj *unstructured.Unstructured, eventType, reason, message string)
 
 	requeue func(obj *unstructured.Unstructured, after time.Duration) error
 }
@@ -59,6 +81,19 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 
 	klog.Infof("reconciling object %s/%s for provider %s", obj.GetNamespace(), obj.GetName(), provider.ClusterID)
 
+	if reason, err := r.pausedBy(obj); err != nil {
+		return err
+	} else if reason != "" {
+		logger.V(2).Info("syncing is paused", "reason", reason)
+		return r.ensureConditions(ctx, obj, pausedCondition(reason))
+	} else if resumed, err := syncconditions.IsConditionTrue(obj, v1alpha1.DownstreamConditionPaused); err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	} else if resumed {
+		logger.Info("Resuming sync of downstream object")
+		return r.ensureConditions(ctx, obj, pausedCleared) // the downstream object will lead to a requeue
+	}
+
 	ns := obj.GetNamespace()
 	if ns != "" {
 		sn, err := r.getServiceNamespace(provider, ns)
@@ -103,49 +138,74 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 			return nil
 		}
 
+		if _, synced := obj.GetAnnotations()[v1alpha1.LastSyncedSpecHashAnnotationKey]; synced {
+			if handled, err := r.handleProviderDeletion(ctx, provider, obj, ns); err != nil || handled {
+				return err
+			}
+		}
+
 		if obj, err = r.ensureDownstreamFinalizer(ctx, obj); err != nil {
 			klog.Errorln(err)
 			return err
 		}
 
-		// clean up object
-		upstream = obj.DeepCopy()
-		upstream.SetUID("")
-		upstream.SetResourceVersion("")
-		upstream.SetNamespace(ns)
-		upstream.SetManagedFields(nil)
-		upstream.SetDeletionTimestamp(nil)
-		upstream.SetDeletionGracePeriodSeconds(nil)
-		upstream.SetOwnerReferences(nil)
-		upstream.SetFinalizers(nil)
-		unstructured.RemoveNestedField(upstream.Object, "status")
+		hash, err := specHash(obj, r.providerOwnedPaths)
+		if err != nil {
+			logger.Error(err, "failed to hash downstream spec")
+			return nil // nothing we can do
+		}
 
 		logger.Info("Creating upstream object")
-		if _, err := r.createProviderObject(ctx, provider, upstream); err != nil && !errors.IsAlreadyExists(err) {
+		conflict, err := r.applyUpstream(ctx, provider, obj, ns, hash, false)
+		if err != nil {
 			return err
-		} else if errors.IsAlreadyExists(err) {
-			logger.Info("Upstream object already exists. Waiting for requeue.") // the upstream object will lead to a requeue
 		}
+		return r.ensureConditions(ctx, obj, specInSync(conflict), providerDeletedCleared)
 	}
 
 	// here the upstream already exists. Update everything but the status.
 
+	released := v1alpha1.DeletionPolicy(upstream.GetAnnotations()[v1alpha1.ReleasedAnnotationKey])
 	if obj.GetDeletionTimestamp() != nil && !obj.GetDeletionTimestamp().IsZero() {
 		if upstream.GetDeletionTimestamp() != nil && !upstream.GetDeletionTimestamp().IsZero() {
 			logger.V(2).Info("upstream is already deleting, wait for it")
 			return nil // we will get an event when the upstream is deleted
 		}
 
-		logger.V(1).Info("object is already deleting downstream, deleting upstream too")
-		if err := r.deleteProviderObject(ctx, provider, ns, obj.GetName()); err != nil && !errors.IsNotFound(err) {
+		policy, err := r.deletionPolicy(obj)
+		if err != nil {
 			return err
 		}
+		switch {
+		case released != "":
+			logger.V(2).Info("upstream has been released before, leaving it alone", "policy", released)
+		case policy == v1alpha1.DeletionPolicyDelete:
+			logger.V(1).Info("object is already deleting downstream, deleting upstream too")
+			if err := r.deleteProviderObject(ctx, provider, ns, obj.GetName()); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		default:
+			logger.V(1).Info("object is already deleting downstream, releasing upstream", "policy", policy)
+			patch, err := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{
+						v1alpha1.ReleasedAnnotationKey: string(policy),
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if err := r.patchProviderObject(ctx, provider, ns, obj.GetName(), types.MergePatchType, patch); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
 
 		if _, err := r.removeDownstreamFinalizer(ctx, obj); err != nil {
 			return err
 		}
 
-		logger.V(2).Info("upstream deleted, finalizer removed in downstream, waiting for downstream deletion to finish")
+		logger.V(2).Info("upstream deleted or released, finalizer removed in downstream, waiting for downstream deletion to finish")
 		return nil // we will get an event when the upstream is deleted
 	}
 
@@ -155,42 +215,219 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		return err
 	}
 
-	downstreamSpec, foundDownstreamSpec, err := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+	switch released {
+	case "":
+	case v1alpha1.DeletionPolicyRetain:
+		logger.Info("Adopting retained upstream object")
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]interface{}{
+					v1alpha1.ReleasedAnnotationKey: nil,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return r.patchProviderObject(ctx, provider, upstream.GetNamespace(), upstream.GetName(), types.MergePatchType, patch) // the upstream object will lead to a requeue
+	default:
+		logger.V(1).Info("upstream object has been orphaned, not syncing")
+		return r.ensureConditions(ctx, obj, func(obj *unstructured.Unstructured) (bool, error) {
+			return syncconditions.SetCondition(obj, metav1.Condition{
+				Type:               v1alpha1.DownstreamConditionSpecInSync,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: obj.GetGeneration(),
+				Reason:             "Orphaned",
+				Message:            "An upstream object of the same name has been orphaned in the service provider cluster and is not adopted.",
+			})
+		})
+	}
+
+	if managedFields, changed := migrateLegacyManagedFields(upstream.GetManagedFields()); changed {
+		logger.V(1).Info("Taking over fields of legacy field manager", "manager", legacyApplyManager)
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"managedFields": managedFields,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		return r.patchProviderObject(ctx, provider, upstream.GetNamespace(), upstream.GetName(), types.MergePatchType, patch) // the upstream object will lead to a requeue
+	}
+
+	// sync fields owned by the service provider down
+	updated := obj.DeepCopy()
+	if changed, err := ownership.CopyPaths(upstream, updated, r.providerOwnedPaths); err != nil {
+		logger.Error(err, "failed to copy provider owned fields")
+		return nil // nothing we can do
+	} else if changed {
+		logger.Info("Updating provider owned fields of downstream object")
+		_, err := r.updateConsumerObject(ctx, updated)
+		return err // the downstream object will lead to a requeue
+	}
+
+	hash, err := specHash(obj, r.providerOwnedPaths)
 	if err != nil {
-		logger.Error(err, "failed to get downstream spec")
-		return nil
+		logger.Error(err, "failed to hash downstream spec")
+		return nil // nothing we can do
 	}
-	upstreamSpec, _, err := unstructured.NestedFieldNoCopy(upstream.Object, "spec")
+	upstreamHash, err := upstreamSpecHash(upstream, obj, r.providerOwnedPaths)
 	if err != nil {
-		logger.Error(err, "failed to get upstream spec")
+		logger.Error(err, "failed to hash upstream spec")
+		return nil // nothing we can do
+	}
+	lastSynced := upstream.GetAnnotations()[v1alpha1.LastSyncedSpecHashAnnotationKey]
+
+	switch {
+	case hash == upstreamHash:
+		var conflict error
+		if lastSynced != hash {
+			logger.V(1).Info("Recording synced spec hash on upstream object")
+			if conflict, err = r.applyUpstream(ctx, provider, obj, ns, hash, false); err != nil {
+				return err
+			}
+		}
+		if obj.GetAnnotations()[v1alpha1.LastSyncedSpecHashAnnotationKey] != hash {
+			logger.V(1).Info("Recording synced spec hash on downstream object")
+			updated := obj.DeepCopy()
+			setSpecHash(updated, hash)
+			_, err := r.updateConsumerObject(ctx, updated)
+			return err // the downstream object will lead to a requeue
+		}
+		if err := r.ensureConditions(ctx, obj, specInSync(conflict), syncConflictResolved); err != nil {
+			return err
+		}
+	case lastSynced != "" && upstreamHash != lastSynced:
+		if err := r.resolveSyncConflict(ctx, provider, obj, upstream, ns, hash); err != nil {
+			return err
+		}
+	default:
+		logger.Info("Applying upstream object")
+		conflict, err := r.applyUpstream(ctx, provider, obj, ns, hash, false)
+		if err != nil {
+			return err
+		}
+		if err := r.ensureConditions(ctx, obj, specInSync(conflict)); err != nil {
+			return err
+		}
+	}
+
+	return r.ensureRelatedResources(ctx, provider, obj, upstream)
+}
+
+// applyUpstream applies the spec of the downstream object to the upstream object, recording the
+// given spec hash. Fields set by other field managers in the service provider cluster, e.g.
+// controllers and webhooks, are kept. Conflicts are returned unless forced.
+func (r *reconciler) applyUpstream(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, ns, hash string, force bool) (conflict error, err error) {
+	_, err = r.applyProviderObject(ctx, provider, upstreamApplyConfiguration(obj, ns, hash, r.providerOwnedPaths), force)
+	if errors.IsConflict(err) {
+		return err, nil
+	}
+	return nil, err
+}
+
+// ensureConditions applies the given condition changes to the downstream object, and updates its
+// status if anything changed.
+func (r *reconciler) ensureConditions(ctx context.Context, obj *unstructured.Unstructured, changes ...func(obj *unstructured.Unstructured) (bool, error)) error {
+	obj = obj.DeepCopy()
+
+	changed := false
+	for _, change := range changes {
+		c, err := change(obj)
+		if err != nil {
+			runtime.HandleError(err)
+			return nil // nothing we can do here
+		}
+		changed = changed || c
+	}
+	if !changed {
 		return nil
 	}
-	if reflect.DeepEqual(downstreamSpec, upstreamSpec) {
-		return nil // nothing to do
+
+	klog.FromContext(ctx).V(1).Info("Updating downstream object conditions")
+	_, err := r.updateConsumerObjectStatus(ctx, obj)
+	return err
+}
+
+// specInSync reports the given apply conflict on the downstream object, or clears a reported
+// conflict if there is none.
+func specInSync(conflict error) func(obj *unstructured.Unstructured) (bool, error) {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		if conflict == nil {
+			return syncconditions.RemoveCondition(obj, v1alpha1.DownstreamConditionSpecInSync)
+		}
+		return syncconditions.SetCondition(obj, metav1.Condition{
+			Type:               v1alpha1.DownstreamConditionSpecInSync,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: obj.GetGeneration(),
+			Reason:             "ApplyConflict",
+			Message:            conflict.Error(),
+		})
+	}
+}
+
+// upstreamApplyConfiguration returns the fields of the upstream object owned by the konnector,
+// recording the given spec hash. Spec fields owned by the service provider are left out.
+func upstreamApplyConfiguration(obj *unstructured.Unstructured, ns, hash string, providerOwnedPaths []string) *unstructured.Unstructured {
+	upstream := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	upstream.SetAPIVersion(obj.GetAPIVersion())
+	upstream.SetKind(obj.GetKind())
+	upstream.SetNamespace(ns)
+	upstream.SetName(obj.GetName())
+	upstream.SetLabels(obj.GetLabels())
+	upstream.SetAnnotations(obj.GetAnnotations())
+	setSpecHash(upstream, hash)
+	if spec, found, err := unstructured.NestedFieldCopy(obj.Object, "spec"); err == nil && found {
+		upstream.Object["spec"] = ownership.WithoutPaths(spec, "spec", providerOwnedPaths)
 	}
+	return upstream
+}
 
-	upstream = upstream.DeepCopy()
-	if foundDownstreamSpec {
-		if err := unstructured.SetNestedField(upstream.Object, downstreamSpec, "spec"); err != nil {
-			bs, err := json.Marshal(downstreamSpec)
-			if err != nil {
-				logger.Error(err, "failed to marshal downstream spec", "spec", fmt.Sprintf("%s", downstreamSpec))
-				return nil // nothing we can do
+// migrateLegacyManagedFields hands over the fields applied by legacyApplyManager to applyManager.
+// Otherwise, they would conflict with every change of the downstream spec.
+func migrateLegacyManagedFields(managedFields []metav1.ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, bool) {
+	hasCurrent := false
+	for _, f := range managedFields {
+		hasCurrent = hasCurrent || (f.Manager == applyManager && f.Operation == metav1.ManagedFieldsOperationApply)
+	}
+
+	var migrated []metav1.ManagedFieldsEntry
+	changed := false
+	for _, f := range managedFields {
+		if f.Manager == legacyApplyManager && f.Operation == metav1.ManagedFieldsOperationApply {
+			changed = true
+			if hasCurrent {
+				continue
 			}
-			logger.Error(err, "failed to set spec", "spec", string(bs))
-			return nil // nothing we can do
+			f.Manager = applyManager
+			hasCurrent = true
 		}
-	} else {
-		unstructured.RemoveNestedField(upstream.Object, "spec")
+		migrated = append(migrated, f)
 	}
+	return migrated, changed
+}
 
-	logger.Info("Updating upstream object")
-	upstream.SetManagedFields(nil) // server side apply does not want this
-	if _, err := r.updateProviderObject(ctx, provider, upstream); err != nil {
-		return err
+// deletionPolicy returns the deletion policy of the downstream object, either from its annotation
+// or from the APIServiceBinding.
+func (r *reconciler) deletionPolicy(obj *unstructured.Unstructured) (v1alpha1.DeletionPolicy, error) {
+	if value, found := obj.GetAnnotations()[v1alpha1.DeletionPolicyAnnotationKey]; found {
+		switch policy := v1alpha1.DeletionPolicy(value); policy {
+		case v1alpha1.DeletionPolicyDelete, v1alpha1.DeletionPolicyOrphan, v1alpha1.DeletionPolicyRetain:
+			return policy, nil
+		default:
+			return "", fmt.Errorf("invalid %s annotation %q", v1alpha1.DeletionPolicyAnnotationKey, value)
+		}
 	}
 
-	return nil
+	policy, err := r.getBindingDeletionPolicy()
+	if err != nil {
+		return "", err
+	}
+	if policy == "" {
+		return v1alpha1.DeletionPolicyDelete, nil
+	}
+	return policy, nil
 }
 
 func (r *reconciler) ensureDownstreamFinalizer(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
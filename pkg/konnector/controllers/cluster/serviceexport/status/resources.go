@@ -0,0 +1,187 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/ownership"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// statusResourceNameData is the data available to status resource name templates.
+type statusResourceNameData struct {
+	Name       string
+	ObjectName string
+	ClusterID  string
+}
+
+// statusResourceName renders the name of the consumer copy of a resource referenced by the status.
+func statusResourceName(res kubebindv1alpha1.APIServiceExportStatusResource, name, objectName, clusterID string) (string, error) {
+	text := res.NameTemplate
+	if text == "" {
+		text = kubebindv1alpha1.DefaultStatusResourceNameTemplate
+	}
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid name template %q: %w", text, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, statusResourceNameData{Name: name, ObjectName: objectName, ClusterID: clusterID}); err != nil {
+		return "", fmt.Errorf("failed to execute name template %q: %w", text, err)
+	}
+	// names without the cluster ID could collide with resources of the consumer or of other providers
+	if !strings.Contains(buf.String(), clusterID) {
+		return "", fmt.Errorf("name template %q does not include {{.ClusterID}}", text)
+	}
+	return buf.String(), nil
+}
+
+// ensureStatusResources copies the resources referenced by the upstream status into the namespace of
+// the downstream object, and points the references in the downstream status to the copies. It
+// returns the references whose resources do not exist in the service provider cluster, and the
+// copies which would overwrite resources in the consumer cluster not owned by the downstream object.
+// Their downstream references are left untouched.
+func (r *reconciler) ensureStatusResources(ctx context.Context, provider *konnectormodels.ProviderInfo, upstream, downstream, orig *unstructured.Unstructured) (missing, conflicts []string, err error) {
+	logger := klog.FromContext(ctx)
+
+	if downstream.GetNamespace() == "" {
+		return nil, nil, nil // Secrets and ConfigMaps cannot be owned by cluster-scoped objects
+	}
+	if len(r.statusResources) == 0 {
+		return nil, nil, nil
+	}
+	if err := r.ensureConsumerAccess(ctx, downstream.GetNamespace()); err != nil {
+		return nil, nil, err
+	}
+
+	var errs []error
+	for _, res := range r.statusResources {
+		fields := strings.Split(res.NamePath, ".")
+		oldName, _, err := unstructured.NestedString(orig.Object, fields...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		upstreamName, _, err := unstructured.NestedString(upstream.Object, fields...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var name string
+		if upstreamName != "" {
+			name, err = statusResourceName(res, upstreamName, upstream.GetName(), provider.ClusterID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			providerObj, err := r.getProviderStatusResource(ctx, provider, res.Kind, upstream.GetNamespace(), upstreamName)
+			if err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			} else if errors.IsNotFound(err) {
+				logger.V(2).Info("waiting for status resource", "kind", res.Kind, "name", upstreamName)
+				missing = append(missing, fmt.Sprintf("%s %s/%s", res.Kind, upstream.GetNamespace(), upstreamName))
+				if _, err := ownership.CopyPaths(orig, downstream, []string{res.NamePath}); err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
+			existing, err := r.getConsumerStatusResource(ctx, res.Kind, downstream.GetNamespace(), name)
+			if err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, err)
+				continue
+			} else if err == nil && existing.GetLabels()[kubebindv1alpha1.RelatedResourceOwnerLabelKey] != string(downstream.GetUID()) {
+				logger.Info("refusing to overwrite status resource not owned by the downstream object", "kind", res.Kind, "name", name)
+				conflicts = append(conflicts, fmt.Sprintf("%s %s/%s", res.Kind, downstream.GetNamespace(), name))
+				if _, err := ownership.CopyPaths(orig, downstream, []string{res.NamePath}); err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
+			logger.V(2).Info("Applying status resource", "kind", res.Kind, "name", name)
+			if _, err := r.applyConsumerStatusResource(ctx, statusResourceCopy(providerObj, downstream, name)); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := unstructured.SetNestedField(downstream.Object, name, fields...); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		if oldName == "" || oldName == name {
+			continue
+		}
+		old, err := r.getConsumerStatusResource(ctx, res.Kind, downstream.GetNamespace(), oldName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if old.GetLabels()[kubebindv1alpha1.RelatedResourceOwnerLabelKey] != string(downstream.GetUID()) {
+			continue // not ours
+		}
+		logger.V(1).Info("Deleting status resource not referenced anymore", "kind", res.Kind, "name", oldName)
+		if err := r.deleteConsumerStatusResource(ctx, res.Kind, downstream.GetNamespace(), oldName); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return missing, conflicts, utilerrors.NewAggregate(errs)
+}
+
+// statusResourceCopy returns the consumer copy of a resource referenced by the upstream status,
+// owned by the downstream object to be garbage collected with it.
+func statusResourceCopy(providerObj, downstream *unstructured.Unstructured, name string) *unstructured.Unstructured {
+	copied := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range providerObj.DeepCopy().Object {
+		if k != "metadata" && k != "status" {
+			copied.Object[k] = v
+		}
+	}
+	copied.SetNamespace(downstream.GetNamespace())
+	copied.SetName(name)
+	copied.SetLabels(map[string]string{
+		kubebindv1alpha1.RelatedResourceOwnerLabelKey: string(downstream.GetUID()),
+	})
+	copied.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion: downstream.GetAPIVersion(),
+			Kind:       downstream.GetKind(),
+			Name:       downstream.GetName(),
+			UID:        downstream.GetUID(),
+		},
+	})
+	return copied
+}
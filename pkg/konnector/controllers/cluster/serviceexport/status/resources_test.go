@@ -0,0 +1,194 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestStatusResourceName(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default", want: "mongo-auth-cluster-a"},
+		{name: "object name", template: "{{.ObjectName}}-{{.ClusterID}}-credentials", want: "mongo-cluster-a-credentials"},
+		{name: "without cluster ID", template: "{{.ObjectName}}-credentials", wantErr: true},
+		{name: "unknown field", template: "{{.Namespace}}", wantErr: true},
+		{name: "invalid", template: "{{.Name", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := kubebindv1alpha1.APIServiceExportStatusResource{
+				Kind:         kubebindv1alpha1.RelatedResourceKindSecret,
+				NamePath:     "status.secretRef.name",
+				NameTemplate: tt.template,
+			}
+			got, err := statusResourceName(res, "mongo-auth", "mongo", "cluster-a")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStatusResourceCopy(t *testing.T) {
+	providerObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       map[string]interface{}{"host": "mongo.example.com"},
+	}}
+	providerObj.SetNamespace("kube-bind-zlp9m-default")
+	providerObj.SetName("mongo-connection")
+	providerObj.SetResourceVersion("42")
+
+	downstream := &unstructured.Unstructured{}
+	downstream.SetAPIVersion("mongodb.example.com/v1")
+	downstream.SetKind("MongoDB")
+	downstream.SetNamespace("default")
+	downstream.SetName("mongo")
+	downstream.SetUID("real-uid")
+
+	copied := statusResourceCopy(providerObj, downstream, "mongo-connection-cluster-a")
+	require.Equal(t, "default", copied.GetNamespace())
+	require.Equal(t, "mongo-connection-cluster-a", copied.GetName())
+	require.Empty(t, copied.GetResourceVersion())
+	require.Equal(t, "real-uid", copied.GetLabels()[kubebindv1alpha1.RelatedResourceOwnerLabelKey])
+	require.Len(t, copied.GetOwnerReferences(), 1)
+	require.Equal(t, providerObj.Object["data"], copied.Object["data"])
+}
+
+func TestEnsureStatusResources(t *testing.T) {
+	newSecret := func(ns, name, owner string) *unstructured.Unstructured {
+		secret := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"data":       map[string]interface{}{"password": "c2VjcmV0"},
+		}}
+		secret.SetNamespace(ns)
+		secret.SetName(name)
+		if owner != "" {
+			secret.SetLabels(map[string]string{kubebindv1alpha1.RelatedResourceOwnerLabelKey: owner})
+		}
+		return secret
+	}
+	newObject := func(ns, secretName string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "mongodb.example.com/v1",
+			"kind":       "MongoDB",
+		}}
+		obj.SetNamespace(ns)
+		obj.SetName("mongo")
+		obj.SetUID("real-uid")
+		if secretName != "" {
+			if err := unstructured.SetNestedField(obj.Object, secretName, "status", "secretRef", "name"); err != nil {
+				panic(err)
+			}
+		}
+		return obj
+	}
+
+	tests := []struct {
+		name     string
+		existing *unstructured.Unstructured
+
+		wantApplied   bool
+		wantConflicts []string
+		wantName      string
+	}{
+		{
+			name:        "not existing",
+			wantApplied: true,
+			wantName:    "mongo-auth-cluster-a",
+		},
+		{
+			name:        "owned by the downstream object",
+			existing:    newSecret("default", "mongo-auth-cluster-a", "real-uid"),
+			wantApplied: true,
+			wantName:    "mongo-auth-cluster-a",
+		},
+		{
+			name:          "owned by another object",
+			existing:      newSecret("default", "mongo-auth-cluster-a", "other-uid"),
+			wantConflicts: []string{"Secret default/mongo-auth-cluster-a"},
+		},
+		{
+			name:          "not owned",
+			existing:      newSecret("default", "mongo-auth-cluster-a", ""),
+			wantConflicts: []string{"Secret default/mongo-auth-cluster-a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied *unstructured.Unstructured
+			r := &reconciler{
+				statusResources: []kubebindv1alpha1.APIServiceExportStatusResource{
+					{Kind: kubebindv1alpha1.RelatedResourceKindSecret, NamePath: "status.secretRef.name"},
+				},
+				ensureConsumerAccess: func(ctx context.Context, ns string) error {
+					return nil
+				},
+				getProviderStatusResource: func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+					return newSecret(ns, name, ""), nil
+				},
+				getConsumerStatusResource: func(ctx context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+					if tt.existing == nil || tt.existing.GetNamespace() != ns || tt.existing.GetName() != name {
+						return nil, errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+					}
+					return tt.existing, nil
+				},
+				applyConsumerStatusResource: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					applied = obj
+					return obj, nil
+				},
+				deleteConsumerStatusResource: func(ctx context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) error {
+					t.Fatalf("unexpected deletion of %s %s/%s", kind, ns, name)
+					return nil
+				},
+			}
+
+			upstream := newObject("kube-bind-zlp9m-default", "mongo-auth")
+			orig := newObject("default", "")
+			downstream := orig.DeepCopy()
+			require.NoError(t, unstructured.SetNestedField(downstream.Object, "mongo-auth", "status", "secretRef", "name"))
+
+			missing, conflicts, err := r.ensureStatusResources(context.Background(), &konnectormodels.ProviderInfo{ClusterID: "cluster-a"}, upstream, downstream, orig)
+			require.NoError(t, err)
+			require.Empty(t, missing)
+			require.Equal(t, tt.wantConflicts, conflicts)
+			require.Equal(t, tt.wantApplied, applied != nil, "applied")
+
+			name, _, err := unstructured.NestedString(downstream.Object, "status", "secretRef", "name")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, name)
+		})
+	}
+}
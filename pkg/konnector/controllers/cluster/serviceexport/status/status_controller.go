@@ -18,21 +18,25 @@ package status
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	kubebindhelpers "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/ownership"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	dynamicclient "k8s.io/client-go/dynamic"
@@ -42,24 +46,30 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
-	kmc "kmodules.xyz/client-go/client"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/utils/ptr"
 )
 
 const (
 	controllerName               = "kube-bind-konnector-cluster-status"
+	applyManager                 = "kube-bind-konnector"
 	errorContextDeadlineExceeded = "context deadline exceeded"
-	errorAlreadyExists           = "already exists"
 )
 
 // NewController returns a new controller reconciling status of upstream to downstream.
 func NewController(
 	gvr schema.GroupVersionResource,
+	isolation v1alpha1.Isolation,
+	fieldOwnership *v1alpha1.APIServiceExportFieldOwnership,
+	statusResources []v1alpha1.APIServiceExportStatusResource,
+	bindingDeletionPolicy func() (v1alpha1.DeletionPolicy, error),
+	bindingPaused func() (bool, error),
 	consumerConfig *rest.Config,
 	consumerDynamicInformer informers.GenericInformer,
+	bindStatusResources func(ctx context.Context, ns string) error,
 	providerInfos []*konnectormodels.ProviderInfo,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	metricLabels := metrics.NewLabels(gvr.GroupResource().String(), gvr, konnectormodels.ClusterIDs(providerInfos)...)
+	queue := metrics.NewRateLimitingQueue(controllerName, metricLabels)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -71,11 +81,6 @@ func NewController(
 		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
 	}
 
-	consumerKubeClient, err := kmc.NewUncachedClient(consumerConfig)
-	if err != nil {
-		return nil, err
-	}
-
 	consumerClient, err := dynamicclient.NewForConfig(consumerConfig)
 	if err != nil {
 		return nil, err
@@ -84,7 +89,8 @@ func NewController(
 
 	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr)
 	c := &controller{
-		queue: queue,
+		queue:        queue,
+		metricLabels: metricLabels,
 
 		gvr: gvr,
 
@@ -96,6 +102,13 @@ func NewController(
 		providerInfos: providerInfos,
 
 		reconciler: reconciler{
+			clusterScopedIsolation: isolation,
+			consumerOwnedPaths:     ownership.ConsumerOwnedPaths(fieldOwnership),
+			statusResources:        statusResources,
+
+			getBindingPaused:         bindingPaused,
+			getBindingDeletionPolicy: bindingDeletionPolicy,
+
 			getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
 				anno := obj.GetAnnotations()
 				if clusterID := anno[konnectormodels.AnnotationProviderClusterID]; clusterID == "" {
@@ -118,6 +131,9 @@ func NewController(
 				if ns != "" {
 					return dynamicConsumerLister.Namespace(ns).Get(name)
 				}
+				if isolation == v1alpha1.IsolationNamespaced || isolation == v1alpha1.IsolationNone {
+					return dynamicConsumerLister.Get(name)
+				}
 				got, err := dynamicConsumerLister.Get(clusterscoped.Behead(name, provider.Namespace))
 				if err != nil {
 					return nil, err
@@ -131,6 +147,9 @@ func NewController(
 			},
 			updateConsumerObjectStatus: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 				ns := obj.GetNamespace()
+				if ns == "" && (isolation == v1alpha1.IsolationNamespaced || isolation == v1alpha1.IsolationNone) {
+					return consumerClient.Resource(gvr).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+				}
 				if ns == "" {
 					if err := clusterscoped.TranslateFromUpstream(obj); err != nil {
 						return nil, err
@@ -149,68 +168,40 @@ func NewController(
 				}
 				return updated, nil
 			},
-			ensureStatusSecret: func(ctx context.Context, provider *konnectormodels.ProviderInfo, upstream, downstream *unstructured.Unstructured, status interface{}, providerNS string) (string, error) {
-				upSecretName, found, err := unstructured.NestedString(upstream.Object, "status", "secretRef", "name")
-				if err != nil {
-					return "", err
-				}
-
-				if !found {
-					klog.Warningf("no secretRef found in object %s status", upstream.GetName())
-					return "", nil
-				}
-				providerSecret, err := provider.KubeClient.CoreV1().Secrets(upstream.GetNamespace()).Get(ctx, upSecretName, metav1.GetOptions{})
-				if err != nil {
-					return "", err
-				}
-				consumerSecret := &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      providerSecret.Name + "-" + provider.ClusterID,
-						Namespace: downstream.GetNamespace(),
-						OwnerReferences: []metav1.OwnerReference{
-							{
-								APIVersion: downstream.GetAPIVersion(),
-								Kind:       downstream.GetKind(),
-								UID:        downstream.GetUID(),
-								Name:       downstream.GetName(),
-							},
-						},
-					},
-					Data: providerSecret.Data,
-				}
-
-				// delete backdated secret from consumer
-				oldSecretName, found, err := unstructured.NestedString(downstream.Object, "status", "secretRef", "name")
+			ensureConsumerAccess: bindStatusResources,
+			getProviderStatusResource: func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind v1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+				return provider.Client.Resource(kubebindhelpers.RelatedResourceGVR(kind)).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+			},
+			getConsumerStatusResource: func(ctx context.Context, kind v1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error) {
+				return consumerClient.Resource(kubebindhelpers.RelatedResourceGVR(kind)).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+			},
+			applyConsumerStatusResource: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				data, err := json.Marshal(obj)
 				if err != nil {
-					return "", err
-				}
-				if found {
-					if err = consumerClient.Resource(schema.GroupVersionResource{
-						Group:    "",
-						Version:  "v1",
-						Resource: "secrets",
-					}).Namespace(downstream.GetNamespace()).Delete(ctx, oldSecretName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
-						return "", err
-					} else if errors.IsNotFound(err) {
-						klog.Infof("%s/%s secret not found in downstream", downstream.GetNamespace(), oldSecretName)
-					} else {
-						klog.Infof("secret %s/%s deleted", downstream.GetNamespace(), oldSecretName)
-					}
-				}
-
-				if _, err = kmc.CreateOrPatch(ctx, consumerKubeClient, consumerSecret, func(_ client.Object, _ bool) client.Object {
-					return consumerSecret
-				}); err != nil && !strings.Contains(err.Error(), errorAlreadyExists) {
-					klog.Errorln(err)
-					return "", err
+					return nil, err
 				}
-
-				klog.Infof("secret %s/%s successfully created", consumerSecret.Namespace, consumerSecret.Name)
-
-				return consumerSecret.Name, nil
+				return consumerClient.Resource(kubebindhelpers.RelatedResourceGVR(v1alpha1.RelatedResourceKind(obj.GetKind()))).Namespace(obj.GetNamespace()).Patch(ctx,
+					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: ptr.To(true)},
+				)
+			},
+			deleteConsumerStatusResource: func(ctx context.Context, kind v1alpha1.RelatedResourceKind, ns, name string) error {
+				return consumerClient.Resource(kubebindhelpers.RelatedResourceGVR(kind)).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			updateProviderObjectStatus: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				return provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+			},
+			patchProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, pt types.PatchType, data []byte) error {
+				_, err := provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Patch(ctx, obj.GetName(), pt, data, metav1.PatchOptions{})
+				return err
 			},
-			deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
-				return provider.Client.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) error {
+				// only delete the object as seen, i.e. not if it has been released in the meantime
+				return provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Delete(ctx, obj.GetName(), metav1.DeleteOptions{
+					Preconditions: &metav1.Preconditions{
+						UID:             ptr.To(obj.GetUID()),
+						ResourceVersion: ptr.To(obj.GetResourceVersion()),
+					},
+				})
 			},
 		},
 	}
@@ -249,7 +240,8 @@ func NewController(
 
 // controller reconciles status of upstream to downstream.
 type controller struct {
-	queue workqueue.RateLimitingInterface
+	queue        workqueue.RateLimitingInterface
+	metricLabels metrics.Labels
 
 	gvr schema.GroupVersionResource
 
@@ -272,11 +264,17 @@ func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormode
 		runtime.HandleError(err)
 		return
 	}
-	ns, _, err := cache.SplitMetaNamespaceKey(key)
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		runtime.HandleError(err)
 		return
 	}
+	if _, ok := clusterscoped.DownstreamName(ns, name, provider.Namespace, c.clusterScopedIsolation); ok {
+		// upstream copy of a cluster-scoped downstream object
+		logger.V(2).Info("queueing Unstructured", "key", key)
+		c.queue.Add(provider.ClusterID + "/" + key)
+		return
+	}
 	if ns != "" {
 		sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, ns)
 		if err != nil {
@@ -295,12 +293,7 @@ func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormode
 		return
 	}
 
-	if clusterscoped.Behead(key, provider.Namespace) == key {
-		logger.V(3).Info("skipping because consumer mismatch", "key", key)
-		return
-	}
-	logger.V(2).Info("queueing Unstructured", "key", key)
-	c.queue.Add(provider.ClusterID + "/" + key)
+	logger.V(3).Info("skipping because consumer mismatch", "key", key)
 }
 
 func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
@@ -338,7 +331,11 @@ func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
 		return
 	}
 
-	upstreamKey := clusterscoped.Prepend(downstreamKey, provider.Namespace)
+	upstreamNs, upstreamName := clusterscoped.UpstreamNamespacedName(name, provider.Namespace, c.clusterScopedIsolation)
+	upstreamKey := upstreamName
+	if upstreamNs != "" {
+		upstreamKey = upstreamNs + "/" + upstreamName
+	}
 	logger.V(2).Info("queueing Unstructured", "key", upstreamKey)
 	c.queue.Add(provider.ClusterID + "/" + upstreamKey)
 }
@@ -385,6 +382,7 @@ func (c *controller) enqueueServiceNamespace(logger klog.Logger, provider *konne
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *controller) Start(ctx context.Context, numThreads int) {
 	defer runtime.HandleCrash()
+	defer metrics.Forget(controllerName, c.metricLabels)
 	defer c.queue.ShutDown()
 
 	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
@@ -435,7 +433,9 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	err := c.process(ctx, key)
+	metrics.ObserveSync(controllerName, c.metricLabels, err)
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		klog.Errorln(err)
 		c.queue.AddRateLimited(key)
@@ -494,7 +494,13 @@ func (c *controller) process(ctx context.Context, key string) error {
 	} else if err != nil && (errors.IsNotFound(err) || strings.Contains(err.Error(), errorContextDeadlineExceeded)) {
 		logger.V(2).Info("Upstream object disappeared")
 
-		downstream, err := c.consumerDynamicLister.Namespace(ns).Get(name)
+		var downstream *unstructured.Unstructured
+		if downstreamName, ok := clusterscoped.DownstreamName(ns, name, provider.Namespace, c.clusterScopedIsolation); ok {
+			// upstream copy of a cluster-scoped downstream object
+			downstream, err = c.consumerDynamicLister.Get(downstreamName)
+		} else {
+			downstream, err = c.consumerDynamicLister.Namespace(ns).Get(name)
+		}
 		if err != nil && !errors.IsNotFound(err) {
 			return err
 		} else if err == nil {
@@ -18,18 +18,34 @@ package status
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/ownership"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/syncconditions"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
 )
 
 type reconciler struct {
+	clusterScopedIsolation kubebindv1alpha1.Isolation
+	consumerOwnedPaths     []string
+	statusResources        []kubebindv1alpha1.APIServiceExportStatusResource
+
+	getBindingPaused         func() (bool, error)
+	getBindingDeletionPolicy func() (kubebindv1alpha1.DeletionPolicy, error)
+
 	getProviderInfo func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error)
 
 	getServiceNamespace func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error)
@@ -37,9 +53,15 @@ type reconciler struct {
 	getConsumerObject          func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
 	updateConsumerObjectStatus func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 
-	ensureStatusSecret func(ctx context.Context, provider *konnectormodels.ProviderInfo, upstream, downstream *unstructured.Unstructured, status interface{}, providerNS string) (string, error)
+	ensureConsumerAccess         func(ctx context.Context, ns string) error
+	getProviderStatusResource    func(ctx context.Context, provider *konnectormodels.ProviderInfo, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error)
+	getConsumerStatusResource    func(ctx context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) (*unstructured.Unstructured, error)
+	applyConsumerStatusResource  func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	deleteConsumerStatusResource func(ctx context.Context, kind kubebindv1alpha1.RelatedResourceKind, ns, name string) error
 
-	deleteProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
+	updateProviderObjectStatus func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	patchProviderObject        func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, pt types.PatchType, data []byte) error
+	deleteProviderObject       func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) error
 }
 
 // reconcile syncs upstream status to consumer objects.
@@ -52,8 +74,23 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		return err
 	}
 
+	if policy := obj.GetAnnotations()[kubebindv1alpha1.ReleasedAnnotationKey]; policy != "" {
+		logger.V(3).Info("skipping released upstream object", "policy", policy)
+		return nil
+	}
+
+	if paused, err := r.getBindingPaused(); err != nil {
+		return err
+	} else if paused {
+		logger.V(3).Info("skipping upstream object, syncing is paused by the APIServiceBinding")
+		return nil // the spec controller reports the Paused condition
+	}
+
 	ns := obj.GetNamespace()
-	if ns != "" {
+	if _, ok := clusterscoped.DownstreamName(ns, obj.GetName(), provider.Namespace, r.clusterScopedIsolation); ok {
+		// upstream copy of a cluster-scoped downstream object
+		ns = ""
+	} else if ns != "" {
 		sn, err := r.getServiceNamespace(provider, ns)
 		if err != nil && !errors.IsNotFound(err) {
 			return err
@@ -78,15 +115,43 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		logger.Info("failed to get downstream object", "error", err, "downstreamNamespace", ns, "downstreamName", obj.GetName())
 		return err
 	} else if errors.IsNotFound(err) {
-		// downstream is gone. Delete upstream too. Note that we cannot rely on the spec controller because
-		// due to konnector restart it might have missed the deletion event.
-		logger.Info("Deleting upstream object because downstream is gone", "downstreamNamespace", ns, "downstreamName", obj.GetName())
-		if err := r.deleteProviderObject(ctx, provider, obj.GetNamespace(), obj.GetName()); err != nil {
+		// downstream is gone. Delete or release upstream too, according to the deletion policy of the
+		// APIServiceBinding. Note that we cannot rely on the spec controller because due to konnector
+		// restart it might have missed the deletion event.
+		policy, err := r.getBindingDeletionPolicy()
+		if err != nil {
 			return err
 		}
+		if policy != "" && policy != kubebindv1alpha1.DeletionPolicyDelete {
+			logger.Info("Releasing upstream object because downstream is gone", "downstreamNamespace", ns, "downstreamName", obj.GetName(), "policy", policy)
+			patch, err := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"resourceVersion": obj.GetResourceVersion(),
+					"annotations": map[string]interface{}{
+						kubebindv1alpha1.ReleasedAnnotationKey: string(policy),
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if err := r.patchProviderObject(ctx, provider, obj, types.MergePatchType, patch); err != nil && !errors.IsNotFound(err) {
+				return err // on conflict, the upstream object is reconciled again
+			}
+			return nil
+		}
+		logger.Info("Deleting upstream object because downstream is gone", "downstreamNamespace", ns, "downstreamName", obj.GetName())
+		if err := r.deleteProviderObject(ctx, provider, obj); err != nil && !errors.IsNotFound(err) {
+			return err // on conflict, the upstream object might have been released in the meantime
+		}
 		return nil
 	}
 
+	if downstream.GetAnnotations()[kubebindv1alpha1.PausedAnnotationKey] == "true" {
+		logger.V(3).Info("skipping upstream object, syncing is paused by annotation")
+		return nil // the spec controller reports the Paused condition
+	}
+
 	orig := downstream
 	downstream = downstream.DeepCopy()
 	newStatus, found, err := unstructured.NestedFieldNoCopy(obj.Object, "status")
@@ -95,25 +160,49 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		return nil // nothing we can do here
 	}
 	if found {
-		newSecretRefName, err := r.ensureStatusSecret(ctx, provider, obj.DeepCopy(), downstream, newStatus, ns)
-		if err != nil {
-			klog.Errorln(err)
-			return err
-		}
-		if err := unstructured.SetNestedField(downstream.Object, newStatus, "status"); err != nil {
+		if err := unstructured.SetNestedField(downstream.Object, k8sruntime.DeepCopyJSONValue(newStatus), "status"); err != nil {
 			runtime.HandleError(err)
-			klog.Errorln(err)
 			return nil // nothing we can do here
 		}
-		if newSecretRefName != "" {
-			if err = unstructured.SetNestedField(downstream.Object, newSecretRefName, "status", "secretRef", "name"); err != nil {
-				klog.Errorln(err)
-				return err
-			}
-		}
 	} else {
 		unstructured.RemoveNestedField(downstream.Object, "status")
 	}
+	missing, conflicts, err := r.ensureStatusResources(ctx, provider, obj, downstream, orig)
+	if err != nil {
+		return err
+	}
+	if err := syncconditions.PreserveConditions(orig, downstream); err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
+	if len(conflicts) > 0 {
+		if _, err := syncconditions.SetCondition(downstream, metav1.Condition{
+			Type:    kubebindv1alpha1.DownstreamConditionStatusResourcesInSync,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ResourceConflict",
+			Message: fmt.Sprintf("Refusing to overwrite %s not owned by this object", strings.Join(conflicts, ", ")),
+		}); err != nil {
+			runtime.HandleError(err)
+			return nil // nothing we can do here
+		}
+	} else if len(missing) > 0 {
+		if _, err := syncconditions.SetCondition(downstream, metav1.Condition{
+			Type:    kubebindv1alpha1.DownstreamConditionStatusResourcesInSync,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ResourceNotFound",
+			Message: fmt.Sprintf("Waiting for %s in the service provider cluster", strings.Join(missing, ", ")),
+		}); err != nil {
+			runtime.HandleError(err)
+			return nil // nothing we can do here
+		}
+	} else if _, err := syncconditions.RemoveCondition(downstream, kubebindv1alpha1.DownstreamConditionStatusResourcesInSync); err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
+	if _, err := ownership.CopyPaths(orig, downstream, r.consumerOwnedPaths); err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
 	if !reflect.DeepEqual(orig, downstream) {
 		logger.Info("Updating downstream object status")
 		if _, err := r.updateConsumerObjectStatus(ctx, provider, downstream); err != nil {
@@ -121,5 +210,26 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		}
 	}
 
+	// sync fields owned by the consumer up
+	upstream := obj.DeepCopy()
+	if changed, err := ownership.CopyPaths(orig, upstream, r.consumerOwnedPaths); err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	} else if changed {
+		logger.Info("Updating consumer owned fields of upstream object status")
+		if _, err := r.updateProviderObjectStatus(ctx, provider, upstream); err != nil {
+			return err
+		}
+	}
+
+	if len(conflicts) > 0 {
+		// retry with backoff until the conflicting resources are removed
+		return fmt.Errorf("refusing to overwrite %s not owned by this object", strings.Join(conflicts, ", "))
+	}
+	if len(missing) > 0 {
+		// retry with backoff until the referenced resources show up
+		return fmt.Errorf("waiting for %s in the service provider cluster", strings.Join(missing, ", "))
+	}
+
 	return nil
 }
@@ -0,0 +1,94 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReconcileDownstreamGone(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       kubebindv1alpha1.DeletionPolicy
+		wantDeleted  bool
+		wantReleased kubebindv1alpha1.DeletionPolicy
+	}{
+		{name: "default", wantDeleted: true},
+		{name: "delete", policy: kubebindv1alpha1.DeletionPolicyDelete, wantDeleted: true},
+		{name: "orphan", policy: kubebindv1alpha1.DeletionPolicyOrphan, wantReleased: kubebindv1alpha1.DeletionPolicyOrphan},
+		{name: "retain", policy: kubebindv1alpha1.DeletionPolicyRetain, wantReleased: kubebindv1alpha1.DeletionPolicyRetain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "mongodb.example.com/v1",
+				"kind":       "MongoDBCluster",
+			}}
+			upstream.SetName("mongo")
+			upstream.SetResourceVersion("42")
+
+			var deleted bool
+			var released kubebindv1alpha1.DeletionPolicy
+			r := &reconciler{
+				getBindingPaused: func() (bool, error) {
+					return false, nil
+				},
+				getBindingDeletionPolicy: func() (kubebindv1alpha1.DeletionPolicy, error) {
+					return tt.policy, nil
+				},
+				getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+					return &konnectormodels.ProviderInfo{ClusterID: "cluster-a"}, nil
+				},
+				getConsumerObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					return nil, errors.NewNotFound(schema.GroupResource{Group: "mongodb.example.com", Resource: "mongodbclusters"}, name)
+				},
+				patchProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, pt types.PatchType, data []byte) error {
+					require.Equal(t, types.MergePatchType, pt)
+					var patch struct {
+						Metadata struct {
+							ResourceVersion string            `json:"resourceVersion"`
+							Annotations     map[string]string `json:"annotations"`
+						} `json:"metadata"`
+					}
+					require.NoError(t, json.Unmarshal(data, &patch))
+					require.Equal(t, "42", patch.Metadata.ResourceVersion)
+					released = kubebindv1alpha1.DeletionPolicy(patch.Metadata.Annotations[kubebindv1alpha1.ReleasedAnnotationKey])
+					return nil
+				},
+				deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) error {
+					deleted = true
+					return nil
+				},
+			}
+
+			require.NoError(t, r.reconcile(context.Background(), upstream))
+			require.Equal(t, tt.wantDeleted, deleted, "deleted")
+			require.Equal(t, tt.wantReleased, released, "released")
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncconditions
+
+import (
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// conditionPrefix is the prefix of condition types owned by the konnector. Conditions with this
+// prefix are set on downstream objects, next to the conditions synced from the upstream object.
+const conditionPrefix = kubebindv1alpha1.GroupName + "/"
+
+// SetCondition sets the given condition in status.conditions of the object. The last transition
+// time is kept if the status does not change. It returns true if the object has been changed.
+func SetCondition(obj *unstructured.Unstructured, condition metav1.Condition) (bool, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	found := false
+	for i, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok || m["type"] != condition.Type {
+			continue
+		}
+		found = true
+		if m["status"] == string(condition.Status) && m["reason"] == condition.Reason && m["message"] == condition.Message {
+			return false, nil
+		}
+		if m["status"] == string(condition.Status) {
+			if t, ok := m["lastTransitionTime"].(string); ok {
+				if err := condition.LastTransitionTime.UnmarshalQueryParameter(t); err != nil {
+					return false, err
+				}
+			}
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&condition)
+		if err != nil {
+			return false, err
+		}
+		conditions[i] = u
+	}
+	if !found {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&condition)
+		if err != nil {
+			return false, err
+		}
+		conditions = append(conditions, u)
+	}
+
+	return true, unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions")
+}
+
+// RemoveCondition removes the condition of the given type from status.conditions of the object.
+// It returns true if the object has been changed.
+func RemoveCondition(obj *unstructured.Unstructured, conditionType string) (bool, error) {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return false, err
+	}
+
+	var kept []interface{}
+	for _, c := range conditions {
+		if m, ok := c.(map[string]interface{}); ok && m["type"] == conditionType {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	if len(kept) == len(conditions) {
+		return false, nil
+	}
+	if len(kept) == 0 {
+		unstructured.RemoveNestedField(obj.Object, "status", "conditions")
+		return true, nil
+	}
+	return true, unstructured.SetNestedSlice(obj.Object, kept, "status", "conditions")
+}
+
+// IsConditionTrue returns true if status.conditions of the object has a condition of the given
+// type with status true.
+func IsConditionTrue(obj *unstructured.Unstructured, conditionType string) (bool, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+	for _, c := range conditions {
+		if m, ok := c.(map[string]interface{}); ok && m["type"] == conditionType {
+			return m["status"] == string(metav1.ConditionTrue), nil
+		}
+	}
+	return false, nil
+}
+
+// PreserveConditions copies the konnector owned conditions of from into to, e.g. when the status
+// of to has been replaced by the upstream status.
+func PreserveConditions(from, to *unstructured.Unstructured) error {
+	conditions, _, err := unstructured.NestedSlice(from.Object, "status", "conditions")
+	if err != nil {
+		return err
+	}
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, ok := m["type"].(string); !ok || !strings.HasPrefix(t, conditionPrefix) {
+			continue
+		}
+
+		existing, _, err := unstructured.NestedSlice(to.Object, "status", "conditions")
+		if err != nil {
+			return err
+		}
+		replaced := false
+		for i, e := range existing {
+			if em, ok := e.(map[string]interface{}); ok && em["type"] == m["type"] {
+				existing[i] = m
+				replaced = true
+			}
+		}
+		if !replaced {
+			existing = append(existing, m)
+		}
+		if err := unstructured.SetNestedSlice(to.Object, existing, "status", "conditions"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncconditions
+
+import (
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConditions(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+
+	conflict := metav1.Condition{
+		Type:    kubebindv1alpha1.DownstreamConditionSpecInSync,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ApplyConflict",
+		Message: "conflict",
+	}
+	changed, err := SetCondition(obj, conflict)
+	require.NoError(t, err)
+	require.True(t, changed)
+	changed, err = SetCondition(obj, conflict)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// the upstream status replaces the downstream one
+	synced := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+			},
+		},
+	}}
+	require.NoError(t, PreserveConditions(obj, synced))
+	conditions, _, err := unstructured.NestedSlice(synced.Object, "status", "conditions")
+	require.NoError(t, err)
+	require.Len(t, conditions, 2)
+	require.Equal(t, "False", conditions[0].(map[string]interface{})["status"])
+	require.Equal(t, kubebindv1alpha1.DownstreamConditionSpecInSync, conditions[1].(map[string]interface{})["type"])
+
+	isTrue, err := IsConditionTrue(synced, kubebindv1alpha1.DownstreamConditionSpecInSync)
+	require.NoError(t, err)
+	require.False(t, isTrue)
+	isTrue, err = IsConditionTrue(obj, "Ready")
+	require.NoError(t, err)
+	require.True(t, isTrue)
+
+	changed, err = RemoveCondition(synced, kubebindv1alpha1.DownstreamConditionSpecInSync)
+	require.NoError(t, err)
+	require.True(t, changed)
+	conditions, _, err = unstructured.NestedSlice(synced.Object, "status", "conditions")
+	require.NoError(t, err)
+	require.Len(t, conditions, 1)
+}
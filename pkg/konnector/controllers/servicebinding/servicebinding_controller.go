@@ -27,13 +27,15 @@ import (
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/secretinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	coreinformers "k8s.io/client-go/informers/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -49,7 +51,8 @@ const (
 func NewController(
 	consumerConfig *rest.Config,
 	serviceBindingInformer bindinformers.APIServiceBindingInformer,
-	consumerSecretInformer coreinformers.SecretInformer,
+	consumerSecretInformer *secretinformer.Informer,
+	sharder *sharding.Sharder,
 ) (*controller, error) {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 
@@ -71,6 +74,8 @@ func NewController(
 
 		consumerSecretLister: consumerSecretInformer.Lister(),
 
+		sharder: sharder,
+
 		reconciler: reconciler{
 			getConsumerSecret: func(ns, name string) (*corev1.Secret, error) {
 				return consumerSecretInformer.Lister().Secrets(ns).Get(name)
@@ -104,7 +109,7 @@ func NewController(
 		return nil, err
 	}
 
-	_, err = consumerSecretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	consumerSecretInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			c.enqueueConsumerSecret(logger, obj)
 		},
@@ -115,9 +120,17 @@ func NewController(
 			c.enqueueConsumerSecret(logger, obj)
 		},
 	})
-	if err != nil {
-		return nil, err
-	}
+
+	sharder.AddEventHandler(func() {
+		bindings, err := c.serviceBindingLister.List(labels.Everything())
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		for _, binding := range bindings {
+			c.enqueueServiceBinding(logger, binding)
+		}
+	})
 
 	return c, nil
 }
@@ -138,6 +151,8 @@ type controller struct {
 
 	consumerSecretLister corelisters.SecretLister
 
+	sharder *sharding.Sharder
+
 	reconciler
 
 	commit CommitFunc
@@ -246,6 +261,13 @@ func (c *controller) process(ctx context.Context, key string) error {
 		logger.Error(err, "APIServiceBinding disappeared")
 		return nil
 	}
+	shardKey, err := sharding.ConnectionKey(obj, c.getConsumerSecret)
+	if err != nil {
+		return err
+	}
+	if !c.sharder.Owns(shardKey) {
+		return nil // another replica syncs it
+	}
 
 	old := obj
 	obj = obj.DeepCopy()
@@ -0,0 +1,113 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/retry"
+)
+
+// servingCertRenewBefore is how long before expiry the self-signed serving certificate is replaced.
+const servingCertRenewBefore = 30 * 24 * time.Hour
+
+// EnsureServingCertSecret returns the self-signed serving certificate of the conversion webhook
+// service from the given kubernetes.io/tls Secret, and generates it if the Secret is missing,
+// incomplete or about to expire. All konnector replicas share the certificate through the Secret.
+// The returned CA bundle is the certificate chain, which ends with the self-signed CA.
+func EnsureServingCertSecret(ctx context.Context, client kubernetes.Interface, ns, name, serviceName string) (tls.Certificate, []byte, error) {
+	host := serviceName + "." + ns + ".svc"
+
+	var keyPair tls.Certificate
+	var caBundle []byte
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}, func() error {
+		secret, err := client.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if errors.IsNotFound(err) {
+			secret = nil
+		}
+
+		if secret != nil {
+			if keyPair, err = validServingCert(secret, host); err == nil {
+				caBundle = secret.Data[corev1.TLSCertKey]
+				return nil
+			}
+		}
+
+		certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey(host, nil, []string{serviceName + "." + ns + ".svc.cluster.local"})
+		if err != nil {
+			return err
+		}
+		if keyPair, err = tls.X509KeyPair(certPEM, keyPEM); err != nil {
+			return err
+		}
+		caBundle = certPEM
+
+		data := map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		}
+		if secret == nil {
+			_, err = client.CoreV1().Secrets(ns).Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+				Type:       corev1.SecretTypeTLS,
+				Data:       data,
+			}, metav1.CreateOptions{})
+			return err
+		}
+		secret = secret.DeepCopy()
+		secret.Data = data
+		_, err = client.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("failed to ensure conversion webhook serving certificate in Secret %s/%s: %w", ns, name, err)
+	}
+
+	return keyPair, caBundle, nil
+}
+
+// validServingCert returns the key pair of the Secret if it is valid for the host and not about to expire.
+func validServingCert(secret *corev1.Secret, host string) (tls.Certificate, error) {
+	keyPair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := leaf.VerifyHostname(host); err != nil {
+		return tls.Certificate{}, err
+	}
+	if time.Until(leaf.NotAfter) < servingCertRenewBefore {
+		return tls.Certificate{}, fmt.Errorf("certificate expires at %s", leaf.NotAfter)
+	}
+	return keyPair, nil
+}
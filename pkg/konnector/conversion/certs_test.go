@@ -0,0 +1,57 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureServingCertSecret(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+
+	keyPair, caBundle, err := EnsureServingCertSecret(ctx, client, "kube-bind", "konnector-conversion-webhook", "konnector")
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(caBundle))
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	require.NoError(t, err)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "konnector.kube-bind.svc", Roots: pool})
+	require.NoError(t, err)
+
+	secret, err := client.CoreV1().Secrets("kube-bind").Get(ctx, "konnector-conversion-webhook", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, corev1.SecretTypeTLS, secret.Type)
+
+	// another replica reuses the certificate.
+	_, again, err := EnsureServingCertSecret(ctx, client, "kube-bind", "konnector-conversion-webhook", "konnector")
+	require.NoError(t, err)
+	require.Equal(t, caBundle, again)
+
+	// a certificate for another service is replaced.
+	_, other, err := EnsureServingCertSecret(ctx, client, "kube-bind", "konnector-conversion-webhook", "other")
+	require.NoError(t, err)
+	require.NotEqual(t, caBundle, other)
+}
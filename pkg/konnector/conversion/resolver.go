@@ -0,0 +1,181 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// resolvedTargetTTL is how long a resolved conversion webhook target is reused before it is
+// looked up again in the service provider cluster.
+const resolvedTargetTTL = 30 * time.Second
+
+// Resolver looks up the service provider conversion webhook of a bound CRD that is not registered
+// locally, i.e. whose APIServiceBinding is synced by another konnector replica.
+type Resolver interface {
+	Resolve(ctx context.Context, crdName string) (kubernetes.Interface, *kubebindv1alpha1.APIServiceExportConversion, error)
+}
+
+// BindingResolver resolves the conversion webhook of a bound CRD through the APIServiceBinding of
+// the same name and its provider kubeconfig Secret. The consumer cluster is the registry shared by
+// all konnector replicas, hence every replica can serve every conversion request.
+type BindingResolver struct {
+	bindingLister bindlisters.APIServiceBindingLister
+	secretLister  corelisters.SecretLister
+
+	newClients func(kubeconfig []byte) (kubernetes.Interface, bindclient.Interface, error)
+	now        func() time.Time
+
+	lock    sync.Mutex
+	clients map[string]providerClients // by CRD name
+	targets map[string]resolvedTarget  // by CRD name
+}
+
+type providerClients struct {
+	kubeconfig string
+	namespace  string
+	kubeClient kubernetes.Interface
+	bindClient bindclient.Interface
+}
+
+type resolvedTarget struct {
+	target
+	expires time.Time
+}
+
+func NewBindingResolver(bindingLister bindlisters.APIServiceBindingLister, secretLister corelisters.SecretLister) *BindingResolver {
+	return &BindingResolver{
+		bindingLister: bindingLister,
+		secretLister:  secretLister,
+		newClients: func(kubeconfig []byte) (kubernetes.Interface, bindclient.Interface, error) {
+			cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+			if err != nil {
+				return nil, nil, err
+			}
+			cfg = rest.AddUserAgent(cfg, "kube-bind-konnector-conversion")
+			kubeClient, err := kubernetes.NewForConfig(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			bindClient, err := bindclient.NewForConfig(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			return kubeClient, bindClient, nil
+		},
+		now:     time.Now,
+		clients: map[string]providerClients{},
+		targets: map[string]resolvedTarget{},
+	}
+}
+
+// Resolve returns the client of the service provider cluster and the conversion webhook of the
+// APIServiceExport for the given CRD.
+func (r *BindingResolver) Resolve(ctx context.Context, crdName string) (kubernetes.Interface, *kubebindv1alpha1.APIServiceExportConversion, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if t, found := r.targets[crdName]; found && r.now().Before(t.expires) {
+		return t.client, t.conversion, nil
+	}
+	delete(r.targets, crdName)
+
+	clients, err := r.providerClients(crdName)
+	if err != nil {
+		return nil, nil, err
+	}
+	export, err := clients.bindClient.KubeBindV1alpha1().APIServiceExports(clients.namespace).Get(ctx, crdName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Spec.Conversion == nil {
+		return nil, nil, fmt.Errorf("APIServiceExport %s/%s has no conversion webhook", clients.namespace, crdName)
+	}
+
+	t := target{client: clients.kubeClient, conversion: export.Spec.Conversion.DeepCopy()}
+	r.targets[crdName] = resolvedTarget{target: t, expires: r.now().Add(resolvedTargetTTL)}
+	return t.client, t.conversion, nil
+}
+
+// providerClients returns the clients of the first provider of the APIServiceBinding, by cluster UID
+// to match Webhook.getTarget. The lock must be held.
+func (r *BindingResolver) providerClients(crdName string) (providerClients, error) {
+	binding, err := r.bindingLister.Get(crdName)
+	if errors.IsNotFound(err) {
+		delete(r.clients, crdName)
+		return providerClients{}, fmt.Errorf("no APIServiceBinding for CustomResourceDefinition %s", crdName)
+	} else if err != nil {
+		return providerClients{}, err
+	}
+
+	providers := append([]kubebindv1alpha1.Provider(nil), binding.Spec.Providers...)
+	sort.SliceStable(providers, func(i, j int) bool {
+		return providers[i].ClusterUID < providers[j].ClusterUID
+	})
+	for _, p := range providers {
+		secret, err := r.secretLister.Secrets(p.Kubeconfig.Namespace).Get(p.Kubeconfig.Name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return providerClients{}, err
+		}
+		kubeconfig := secret.Data[p.Kubeconfig.Key]
+
+		if existing, found := r.clients[crdName]; found && existing.kubeconfig == string(kubeconfig) {
+			return existing, nil
+		}
+
+		cfg, err := clientcmd.Load(kubeconfig)
+		if err != nil {
+			return providerClients{}, fmt.Errorf("invalid kubeconfig in Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		kubeContext, found := cfg.Contexts[cfg.CurrentContext]
+		if !found || kubeContext.Namespace == "" {
+			return providerClients{}, fmt.Errorf("kubeconfig in Secret %s/%s has no namespace in the current context", secret.Namespace, secret.Name)
+		}
+		kubeClient, bindClient, err := r.newClients(kubeconfig)
+		if err != nil {
+			return providerClients{}, err
+		}
+
+		clients := providerClients{
+			kubeconfig: string(kubeconfig),
+			namespace:  kubeContext.Namespace,
+			kubeClient: kubeClient,
+			bindClient: bindClient,
+		}
+		r.clients[crdName] = clients
+		return clients, nil
+	}
+
+	return providerClients{}, fmt.Errorf("no provider kubeconfig found for APIServiceBinding %s", crdName)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	bindfake "go.bytebuilders.dev/kube-bind/client/clientset/versioned/fake"
+	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: provider
+  cluster:
+    server: https://provider:6443
+contexts:
+- name: provider
+  context:
+    cluster: provider
+    namespace: kube-bind-abcde
+    user: provider
+current-context: provider
+users:
+- name: provider
+  user:
+    token: secret
+`
+
+func TestBindingResolver(t *testing.T) {
+	bindings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, bindings.Add(&kubebindv1alpha1.APIServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"},
+		Spec: kubebindv1alpha1.APIServiceBindingSpec{
+			Providers: []kubebindv1alpha1.Provider{{
+				ClusterIdentity: kubebindv1alpha1.ClusterIdentity{ClusterUID: "cluster-a"},
+				Kubeconfig:      kubebindv1alpha1.ClusterSecretKeyRef{LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "kubeconfig", Key: "kubeconfig"}, Namespace: "kube-bind"},
+			}},
+		},
+	}))
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, secrets.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig"},
+		Data:       map[string][]byte{"kubeconfig": []byte(testKubeconfig)},
+	}))
+
+	conversion := &kubebindv1alpha1.APIServiceExportConversion{
+		Service:                  apiextensionsv1.ServiceReference{Namespace: "foo-system", Name: "foo-webhook"},
+		ConversionReviewVersions: []string{"v1"},
+	}
+	bindClient := bindfake.NewSimpleClientset(
+		&kubebindv1alpha1.APIServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind-abcde", Name: "foos.example.com"},
+			Spec:       kubebindv1alpha1.APIServiceExportSpec{APIServiceExportCRDSpec: kubebindv1alpha1.APIServiceExportCRDSpec{Conversion: conversion}},
+		},
+		&kubebindv1alpha1.APIServiceExport{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind-abcde", Name: "bars.example.com"},
+		},
+	)
+
+	now := time.Now()
+	clientsCreated := 0
+	r := NewBindingResolver(bindlisters.NewAPIServiceBindingLister(bindings), corelisters.NewSecretLister(secrets))
+	r.now = func() time.Time { return now }
+	r.newClients = func(kubeconfig []byte) (kubernetes.Interface, bindclient.Interface, error) {
+		clientsCreated++
+		return nil, bindClient, nil
+	}
+
+	_, got, err := r.Resolve(context.Background(), "foos.example.com")
+	require.NoError(t, err)
+	require.Equal(t, conversion, got)
+
+	// cached until the TTL is over, with the clients reused afterwards.
+	require.NoError(t, bindClient.Tracker().Delete(kubebindv1alpha1.SchemeGroupVersion.WithResource("apiserviceexports"), "kube-bind-abcde", "foos.example.com"))
+	_, _, err = r.Resolve(context.Background(), "foos.example.com")
+	require.NoError(t, err)
+	now = now.Add(resolvedTargetTTL)
+	_, _, err = r.Resolve(context.Background(), "foos.example.com")
+	require.Error(t, err)
+	require.Equal(t, 1, clientsCreated)
+
+	_, _, err = r.Resolve(context.Background(), "bars.example.com")
+	require.ErrorContains(t, err, "no APIServiceBinding")
+}
@@ -0,0 +1,222 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	kubebindhelpers "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// PathPrefix is the path prefix under which the conversion webhook of a bound CRD
+// is served. The CRD name follows the prefix.
+const PathPrefix = "/convert/"
+
+// maxRequestSize limits the size of ConversionReview requests read from the consumer
+// API server.
+const maxRequestSize = 32 << 20
+
+// Webhook serves conversion webhooks of bound CRDs in the consumer cluster by proxying
+// the ConversionReview requests to the conversion webhook service of the service provider
+// cluster through the services/proxy subresource.
+//
+// A nil Webhook is valid and means conversion webhooks are not served.
+type Webhook struct {
+	// ServiceNamespace, ServiceName and ServicePort reference the service of the konnector
+	// in the consumer cluster.
+	ServiceNamespace string
+	ServiceName      string
+	ServicePort      int32
+	// CABundle is the PEM encoded CA bundle the consumer API server uses to verify the
+	// serving certificate of the konnector.
+	CABundle []byte
+	// Resolver looks up the targets of CRDs not registered by this konnector replica. The
+	// consumer API server calls any replica behind the service.
+	Resolver Resolver
+
+	lock    sync.RWMutex
+	targets map[string]map[string]target // by CRD name and cluster ID
+}
+
+type target struct {
+	client     kubernetes.Interface
+	conversion *kubebindv1alpha1.APIServiceExportConversion
+}
+
+// Register sets the service provider conversion webhook for the given CRD of the given
+// provider cluster.
+func (w *Webhook) Register(crdName, clusterID string, client kubernetes.Interface, conversion *kubebindv1alpha1.APIServiceExportConversion) {
+	if w == nil {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.targets == nil {
+		w.targets = map[string]map[string]target{}
+	}
+	if w.targets[crdName] == nil {
+		w.targets[crdName] = map[string]target{}
+	}
+	w.targets[crdName][clusterID] = target{client: client, conversion: conversion.DeepCopy()}
+}
+
+// Unregister removes the service provider conversion webhook for the given CRD of the
+// given provider cluster.
+func (w *Webhook) Unregister(crdName, clusterID string) {
+	if w == nil {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.targets[crdName], clusterID)
+	if len(w.targets[crdName]) == 0 {
+		delete(w.targets, crdName)
+	}
+}
+
+// ClientConfig returns the webhook client config the consumer CRD with the given name
+// has to use, or nil if conversion webhooks are not served.
+func (w *Webhook) ClientConfig(crdName string) *apiextensionsv1.WebhookClientConfig {
+	if w == nil {
+		return nil
+	}
+
+	port := w.ServicePort
+	path := PathPrefix + crdName
+	return &apiextensionsv1.WebhookClientConfig{
+		Service: &apiextensionsv1.ServiceReference{
+			Namespace: w.ServiceNamespace,
+			Name:      w.ServiceName,
+			Path:      &path,
+			Port:      &port,
+		},
+		CABundle: w.CABundle,
+	}
+}
+
+func (w *Webhook) getTarget(crdName string) (target, bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	// all providers serve the same CRD. Take a stable one.
+	clusterIDs := make([]string, 0, len(w.targets[crdName]))
+	for clusterID := range w.targets[crdName] {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	if len(clusterIDs) == 0 {
+		return target{}, false
+	}
+	sort.Strings(clusterIDs)
+	return w.targets[crdName][clusterIDs[0]], true
+}
+
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := klog.FromContext(req.Context())
+
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	crdName := strings.TrimPrefix(req.URL.Path, PathPrefix)
+	if crdName == req.URL.Path || crdName == "" || strings.Contains(crdName, "/") {
+		http.NotFound(rw, req)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t, found := w.getTarget(crdName)
+	if !found && w.Resolver != nil {
+		client, conversion, err := w.Resolver.Resolve(req.Context(), crdName)
+		if err != nil {
+			logger.V(2).Info("failed to resolve service provider conversion webhook", "crd", crdName, "err", err)
+		} else {
+			t, found = target{client: client, conversion: conversion}, true
+		}
+	}
+	if !found {
+		writeFailure(rw, body, fmt.Sprintf("no service provider conversion webhook known for CustomResourceDefinition %s", crdName))
+		return
+	}
+
+	path := "/"
+	if t.conversion.Service.Path != nil {
+		path = *t.conversion.Service.Path
+	}
+	resp, err := t.client.CoreV1().RESTClient().Post().
+		Namespace(t.conversion.Service.Namespace).
+		Resource("services").
+		Name(kubebindhelpers.ConversionServiceProxyName(t.conversion)).
+		SubResource("proxy").
+		Suffix(path).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		DoRaw(req.Context())
+	if err != nil {
+		logger.Error(err, "failed to proxy ConversionReview", "crd", crdName)
+		writeFailure(rw, body, fmt.Sprintf("failed to call service provider conversion webhook: %v", err))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if _, err := rw.Write(resp); err != nil {
+		logger.Error(err, "failed to write ConversionReview response", "crd", crdName)
+	}
+}
+
+// writeFailure answers the given ConversionReview request with a failure.
+func writeFailure(rw http.ResponseWriter, body []byte, message string) {
+	var review apiextensionsv1.ConversionReview
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(rw, "invalid ConversionReview", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = &apiextensionsv1.ConversionResponse{
+		UID: review.Request.UID,
+		Result: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: message,
+		},
+	}
+	review.Request = nil
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(&review); err != nil {
+		klog.Background().Error(err, "failed to write ConversionReview response")
+	}
+}
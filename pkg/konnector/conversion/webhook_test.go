@@ -0,0 +1,71 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWebhook(t *testing.T) {
+	var w *Webhook
+	require.Nil(t, w.ClientConfig("foos.example.com"))
+	w.Register("foos.example.com", "cluster-a", nil, nil) // no-op
+
+	w = &Webhook{ServiceNamespace: "kube-bind", ServiceName: "konnector", ServicePort: 443}
+	clientConfig := w.ClientConfig("foos.example.com")
+	require.Equal(t, "/convert/foos.example.com", *clientConfig.Service.Path)
+
+	conversion := &kubebindv1alpha1.APIServiceExportConversion{
+		Service: apiextensionsv1.ServiceReference{Namespace: "foo-system", Name: "foo-webhook"},
+	}
+	w.Register("foos.example.com", "cluster-b", nil, conversion)
+	w.Register("foos.example.com", "cluster-a", nil, conversion)
+	_, found := w.getTarget("foos.example.com")
+	require.True(t, found)
+
+	w.Unregister("foos.example.com", "cluster-a")
+	w.Unregister("foos.example.com", "cluster-b")
+	_, found = w.getTarget("foos.example.com")
+	require.False(t, found)
+
+	review := apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  &apiextensionsv1.ConversionRequest{UID: "123", DesiredAPIVersion: "example.com/v1"},
+	}
+	body, err := json.Marshal(&review)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	w.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/convert/foos.example.com", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response apiextensionsv1.ConversionReview
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.NotNil(t, response.Response)
+	require.Equal(t, review.Request.UID, response.Response.UID)
+	require.Equal(t, metav1.StatusFailure, response.Response.Result.Status)
+}
@@ -30,12 +30,17 @@ import (
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/servicebinding"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/providerpool"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/secretinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	corev1 "k8s.io/api/core/v1"
 	crdinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -55,9 +60,12 @@ const (
 func New(
 	consumerConfig *rest.Config,
 	serviceBindingInformer bindinformers.APIServiceBindingInformer,
-	secretInformer coreinformers.SecretInformer,
+	secretInformer *secretinformer.Informer,
 	namespaceInformer coreinformers.NamespaceInformer,
 	crdInformer crdinformers.CustomResourceDefinitionInformer,
+	conversionWebhook *conversion.Webhook,
+	heartbeatInterval time.Duration,
+	sharder *sharding.Sharder,
 ) (*Controller, error) {
 	// queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 	queue := workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{
@@ -74,7 +82,7 @@ func New(
 		return nil, err
 	}
 
-	servicebindingCtrl, err := servicebinding.NewController(consumerConfig, serviceBindingInformer, secretInformer)
+	servicebindingCtrl, err := servicebinding.NewController(consumerConfig, serviceBindingInformer, secretInformer, sharder)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +90,7 @@ func New(
 	namespaceDynamicInformer := dynamic.NewDynamicInformer[corelisters.NamespaceLister](namespaceInformer)
 	serviceBindingDynamicInformer := dynamic.NewDynamicInformer[bindlisters.APIServiceBindingLister](serviceBindingInformer)
 	crdDynamicInformer := dynamic.NewDynamicInformer[apiextensionslisters.CustomResourceDefinitionLister](crdInformer)
+	providerPool := providerpool.New()
 	c := &Controller{
 		queue: queue,
 
@@ -91,8 +100,9 @@ func New(
 		serviceBindingLister:  serviceBindingInformer.Lister(),
 		serviceBindingIndexer: serviceBindingInformer.Informer().GetIndexer(),
 
-		secretLister:  secretInformer.Lister(),
-		secretIndexer: secretInformer.Informer().GetIndexer(),
+		secretLister: secretInformer.Lister(),
+
+		sharder: sharder,
 
 		ServiceBindingCtrl: servicebindingCtrl,
 
@@ -112,9 +122,13 @@ func New(
 					reconcileServiceBinding,
 					consumerConfig,
 					providerInfos,
+					providerPool,
 					namespaceDynamicInformer,
 					serviceBindingDynamicInformer,
 					crdDynamicInformer,
+					secretInformer,
+					conversionWebhook,
+					heartbeatInterval,
 				)
 			},
 		},
@@ -150,7 +164,7 @@ func New(
 		return nil, err
 	}
 
-	_, err = secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	secretInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			c.enqueueSecret(logger, obj)
 		},
@@ -161,9 +175,10 @@ func New(
 			c.enqueueSecret(logger, obj)
 		},
 	})
-	if err != nil {
-		return nil, err
-	}
+
+	sharder.AddEventHandler(func() {
+		c.enqueueAllServiceBindings(logger)
+	})
 
 	return c, nil
 }
@@ -189,8 +204,9 @@ type Controller struct {
 	serviceBindingLister  bindlisters.APIServiceBindingLister
 	serviceBindingIndexer cache.Indexer
 
-	secretLister  corelisters.SecretLister
-	secretIndexer cache.Indexer
+	secretLister corelisters.SecretLister
+
+	sharder *sharding.Sharder
 
 	ServiceBindingCtrl GenericController
 
@@ -210,6 +226,18 @@ func (c *Controller) enqueueServiceBinding(logger klog.Logger, obj interface{})
 	c.queue.Add(key)
 }
 
+// enqueueAllServiceBindings queues all APIServiceBindings, e.g. when they move between shards.
+func (c *Controller) enqueueAllServiceBindings(logger klog.Logger) {
+	bindings, err := c.serviceBindingLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, binding := range bindings {
+		c.enqueueServiceBinding(logger, binding)
+	}
+}
+
 func (c *Controller) enqueueSecret(logger klog.Logger, obj interface{}) {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -308,9 +336,21 @@ func (c *Controller) process(ctx context.Context, key string) error {
 		return nil
 	}
 
+	shardKey, err := sharding.ConnectionKey(obj, c.reconciler.getSecret)
+	if err != nil {
+		return err
+	}
+	if !c.sharder.Owns(shardKey) {
+		// another replica syncs it
+		c.reconciler.stopController(ctx, name)
+		return nil
+	}
+
 	old := obj
 	obj = obj.DeepCopy()
 
+	obj.Status.Shard = c.sharder.Identity()
+
 	var errs []error
 	if err := c.reconcile(ctx, obj); err != nil {
 		errs = append(errs, err)
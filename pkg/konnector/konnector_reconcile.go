@@ -18,8 +18,12 @@ package konnector
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
@@ -27,13 +31,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 )
 
+// handOverTimeout is how long the old Controller of an APIServiceBinding keeps syncing at most,
+// when the kubeconfig changed, until the new Controller has synced.
+const handOverTimeout = time.Minute
+
 // const namespaceKubeSystem = "kube-system"
 type startable interface {
 	Start(ctx context.Context)
+	Synced() bool
 }
 
 type reconciler struct {
@@ -49,6 +59,7 @@ type reconciler struct {
 type controllerContext struct {
 	kubeconfig      []string
 	cancel          func()
+	controller      startable
 	serviceBindings sets.Set[string] // when this is empty, the Controller should be stopped by closing the context
 }
 
@@ -87,14 +98,17 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 	defer r.lock.Unlock()
 	ctrlContext, found := r.controllers[binding.Name]
 
-	// stop existing with old kubeconfig
+	// replace existing with old kubeconfig, e.g. after rotation of the provider credentials. The
+	// old Controller keeps syncing until the new one has synced.
 	if found && !reflect.DeepEqual(ctrlContext.kubeconfig, kubeconfigs) {
-		logger.V(2).Info("stopping old Controller for APIServiceBinding", "apiservicebinding", binding.Namespace+"/"+binding.Name)
-		ctrlContext.serviceBindings.Delete(binding.Name)
-		if len(ctrlContext.serviceBindings) == 0 {
-			ctrlContext.cancel()
-		}
 		delete(r.controllers, binding.Name)
+		if kubeconfigs == nil {
+			logger.V(2).Info("stopping old Controller for APIServiceBinding", "apiservicebinding", binding.Namespace+"/"+binding.Name)
+			r.retire(ctrlContext, binding.Name)
+			return nil
+		}
+		logger.V(2).Info("replacing old Controller for APIServiceBinding", "apiservicebinding", binding.Namespace+"/"+binding.Name)
+		defer r.handOver(ctx, ctrlContext, binding.Name)
 	}
 
 	// no need to start a new one
@@ -139,6 +153,7 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 			logger.Error(err, "invalid kubeconfig in secret", "namespace", identifier.secretRefNamespace, "name", identifier.secretRefName)
 			return nil // nothing we can do here. The APIServiceBinding Controller will set a condition
 		}
+		provider.Kubeconfig = identifier.kubeconfig
 		provider.ConsumerSecretRefKey = identifier.secretRefNamespace + "/" + identifier.secretRefName
 
 		provider.ClusterID = identifier.clusterUID
@@ -147,11 +162,12 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 	}
 
 	ctrlCtx, cancel := context.WithCancel(ctx)
-	r.controllers[binding.Name] = &controllerContext{
+	newContext := &controllerContext{
 		kubeconfig:      kubeconfigs,
 		cancel:          cancel,
 		serviceBindings: sets.New[string](binding.Name),
 	}
+	r.controllers[binding.Name] = newContext
 
 	// create new because there is none yet for this kubeconfig
 	logger.V(2).Info("starting new Controller", "binding", binding.Namespace+"/"+binding.Name)
@@ -160,15 +176,94 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 		func(svcBinding *kubebindv1alpha1.APIServiceBinding) bool {
 			r.lock.RLock()
 			defer r.lock.RUnlock()
-			return r.controllers[binding.Name].serviceBindings.Has(svcBinding.Name)
+			return newContext.serviceBindings.Has(svcBinding.Name)
 		},
 	)
 	if err != nil {
 		logger.Error(err, "failed to start new cluster Controller")
 		return err
 	}
+	newContext.controller = ctrl
 
 	go ctrl.Start(ctrlCtx)
 
 	return nil
 }
+
+// stopController stops syncing the APIServiceBinding of the given name, e.g. when another
+// konnector replica owns it.
+func (r *reconciler) stopController(ctx context.Context, name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.controllers[name]; found {
+		klog.FromContext(ctx).V(2).Info("stopping Controller for APIServiceBinding", "reason", "NotOwned")
+		r.removeServiceBinding(name)
+	}
+}
+
+// removeServiceBinding removes the APIServiceBinding from its Controller, and stops the Controller
+// when no APIServiceBinding is left. The lock must be held.
+func (r *reconciler) removeServiceBinding(name string) {
+	ctrlContext, found := r.controllers[name]
+	if !found {
+		return
+	}
+	r.retire(ctrlContext, name)
+	delete(r.controllers, name)
+}
+
+// retire removes the APIServiceBinding from the given Controller, and stops the Controller when no
+// APIServiceBinding is left. The lock must be held.
+func (r *reconciler) retire(ctrlContext *controllerContext, name string) {
+	ctrlContext.serviceBindings.Delete(name)
+	if len(ctrlContext.serviceBindings) == 0 {
+		ctrlContext.cancel()
+	}
+}
+
+// handOver retires the APIServiceBinding of the given name from the old Controller in the
+// background, as soon as its new Controller has synced, or the new Controller is replaced or stopped
+// itself, or after handOverTimeout. The lock must be held.
+func (r *reconciler) handOver(ctx context.Context, old *controllerContext, name string) {
+	successor := r.controllers[name]
+
+	go func() {
+		logger := klog.FromContext(ctx)
+
+		err := wait.PollUntilContextTimeout(ctx, time.Second, handOverTimeout, true, func(ctx context.Context) (bool, error) {
+			r.lock.RLock()
+			defer r.lock.RUnlock()
+			if current, found := r.controllers[name]; !found || current != successor {
+				return true, nil
+			}
+			return successor.controller != nil && successor.controller.Synced(), nil
+		})
+		if err != nil && ctx.Err() == nil {
+			logger.Info("new Controller did not sync in time, stopping old Controller anyway", "apiservicebinding", name)
+		}
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		logger.V(2).Info("stopping old Controller for APIServiceBinding", "apiservicebinding", name)
+		r.retire(old, name)
+	}()
+}
+
+// providersSynced returns an error naming the APIServiceBindings whose provider informers have not synced yet.
+func (r *reconciler) providersSynced() error {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var notSynced []string
+	for name, ctrlContext := range r.controllers {
+		if ctrlContext.controller == nil || !ctrlContext.controller.Synced() {
+			notSynced = append(notSynced, name)
+		}
+	}
+	if len(notSynced) > 0 {
+		sort.Strings(notSynced)
+		return fmt.Errorf("provider informers not synced for APIServiceBindings: %s", strings.Join(notSynced, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeController struct {
+	synced  atomic.Bool
+	stopped atomic.Bool
+}
+
+func (c *fakeController) Start(ctx context.Context) {
+	<-ctx.Done()
+	c.stopped.Store(true)
+}
+
+func (c *fakeController) Synced() bool {
+	return c.synced.Load()
+}
+
+func kubeconfig(token string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: provider
+  cluster:
+    server: https://provider:6443
+contexts:
+- name: default
+  context:
+    cluster: provider
+    user: default
+    namespace: kube-bind-abc
+current-context: default
+users:
+- name: default
+  user:
+    token: %s
+`, token)
+}
+
+func TestReconcileRotatedKubeconfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var controllers []*fakeController
+	secret := &corev1.Secret{Data: map[string][]byte{"kubeconfig": []byte(kubeconfig("old"))}}
+	r := &reconciler{
+		controllers: map[string]*controllerContext{},
+		newClusterController: func(providerInfos []*konnectormodels.ProviderInfo, reconcileServiceBinding func(binding *kubebindv1alpha1.APIServiceBinding) bool) (startable, error) {
+			c := &fakeController{}
+			controllers = append(controllers, c)
+			return c, nil
+		},
+		getSecret: func(ns, name string) (*corev1.Secret, error) {
+			return secret, nil
+		},
+	}
+
+	binding := &kubebindv1alpha1.APIServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "mongodbs.mongodb.example.com"},
+		Spec: kubebindv1alpha1.APIServiceBindingSpec{
+			Providers: []kubebindv1alpha1.Provider{{
+				Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+					LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "kubeconfig-abc", Key: "kubeconfig"},
+					Namespace:         "kube-bind",
+				},
+			}},
+		},
+	}
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, controllers, 1)
+	old := controllers[0]
+	old.synced.Store(true)
+
+	secret = &corev1.Secret{Data: map[string][]byte{"kubeconfig": []byte(kubeconfig("rotated"))}}
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, controllers, 2)
+	rotated := controllers[1]
+
+	time.Sleep(1500 * time.Millisecond)
+	require.False(t, old.stopped.Load(), "old controller should sync until the new one has synced")
+
+	rotated.synced.Store(true)
+	require.Eventually(t, old.stopped.Load, 5*time.Second, 100*time.Millisecond, "old controller should stop once the new one has synced")
+	require.False(t, rotated.stopped.Load())
+}
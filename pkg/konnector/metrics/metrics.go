@@ -0,0 +1,208 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	namespace = "kube_bind"
+	subsystem = "konnector"
+)
+
+// Labels identify the binding, the GVR and the service provider clusters a metric is recorded for.
+// Empty values mean that the metric is not specific to a binding or a GVR.
+type Labels struct {
+	Binding   string
+	GVR       string
+	ClusterID string
+}
+
+// NewLabels returns the labels of the given binding, GVR and service provider clusters.
+func NewLabels(binding string, gvr schema.GroupVersionResource, clusterIDs ...string) Labels {
+	return Labels{
+		Binding:   binding,
+		GVR:       GVRLabel(gvr),
+		ClusterID: ClusterIDLabel(clusterIDs...),
+	}
+}
+
+// ClusterIDLabel joins the given cluster IDs in a stable order.
+func ClusterIDLabel(clusterIDs ...string) string {
+	ids := append([]string(nil), clusterIDs...)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// GVRLabel formats gvr as group/version/resource, or version/resource for the core group.
+func GVRLabel(gvr schema.GroupVersionResource) string {
+	if gvr.Empty() {
+		return ""
+	}
+	if gvr.Group == "" {
+		return gvr.Version + "/" + gvr.Resource
+	}
+	return gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+}
+
+var labelNames = []string{"binding", "gvr", "cluster_id"}
+
+func (l Labels) values(extra ...string) []string {
+	return append([]string{l.Binding, l.GVR, l.ClusterID}, extra...)
+}
+
+var (
+	// Registry is the registry of the konnector metrics served on /metrics.
+	Registry = prometheus.NewRegistry()
+
+	syncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "syncs_total",
+		Help:      "Total number of reconciliations by controller and result.",
+	}, append([]string{"controller"}, append(labelNames, "result")...))
+
+	upstreamRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of requests to the service provider cluster by GVR, verb and status code.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"gvr", "cluster_id", "verb", "code"})
+
+	informersSynced = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "informers_synced",
+		Help:      "Whether the informers have synced (1) or not (0).",
+	}, labelNames)
+
+	leader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "leader",
+		Help:      "Whether this replica runs the controllers (1), as leader or sharded, or stands by (0).",
+	})
+
+	heartbeats = &heartbeatCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "seconds_since_last_heartbeat"),
+			"Seconds since the last successful heartbeat of the ClusterBinding in the service provider cluster.",
+			[]string{"binding", "cluster_id"}, nil,
+		),
+		last: map[[2]string]time.Time{},
+	}
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		syncs,
+		upstreamRequestDuration,
+		informersSynced,
+		leader,
+		heartbeats,
+	)
+	for _, vec := range workqueueVecs {
+		Registry.MustRegister(vec)
+	}
+}
+
+// ObserveSync counts a reconciliation of the given controller, failed if err is not nil.
+func ObserveSync(controller string, labels Labels, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	syncs.WithLabelValues(append([]string{controller}, labels.values(result)...)...).Inc()
+}
+
+// SetInformersSynced records whether the informers for the given labels have synced.
+func SetInformersSynced(labels Labels, synced bool) {
+	value := 0.0
+	if synced {
+		value = 1
+	}
+	informersSynced.WithLabelValues(labels.values()...).Set(value)
+}
+
+// Forget deletes the series of the given controller with the given labels. Call it when the
+// controller stops, so that the series of removed bindings do not stay around.
+func Forget(controller string, labels Labels) {
+	match := prometheus.Labels{"controller": controller, "binding": labels.Binding, "gvr": labels.GVR, "cluster_id": labels.ClusterID}
+	syncs.DeletePartialMatch(match)
+	for _, vec := range workqueueVecs {
+		vec.DeletePartialMatch(match)
+	}
+}
+
+// ForgetInformersSynced deletes the series recorded by SetInformersSynced for the given labels.
+func ForgetInformersSynced(labels Labels) {
+	informersSynced.DeleteLabelValues(labels.values()...)
+}
+
+// SetLeader records whether this replica runs the controllers.
+func SetLeader(leading bool) {
+	value := 0.0
+	if leading {
+		value = 1
+	}
+	leader.Set(value)
+}
+
+// HeartbeatSucceeded records a successful heartbeat of the binding to the given service provider cluster.
+func HeartbeatSucceeded(binding, clusterID string) {
+	heartbeats.lock.Lock()
+	defer heartbeats.lock.Unlock()
+	heartbeats.last[[2]string{binding, clusterID}] = time.Now()
+}
+
+// ForgetHeartbeat deletes the heartbeat of the binding to the given service provider cluster.
+func ForgetHeartbeat(binding, clusterID string) {
+	heartbeats.lock.Lock()
+	defer heartbeats.lock.Unlock()
+	delete(heartbeats.last, [2]string{binding, clusterID})
+}
+
+// heartbeatCollector reports the time since the last successful heartbeat at scrape time.
+type heartbeatCollector struct {
+	desc *prometheus.Desc
+
+	lock sync.Mutex
+	last map[[2]string]time.Time // by binding and cluster ID
+}
+
+func (c *heartbeatCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *heartbeatCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key, last := range c.last {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, time.Since(last).Seconds(), key[0], key[1])
+	}
+}
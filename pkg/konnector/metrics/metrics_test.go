@@ -0,0 +1,140 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGVRFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/apis/mongodb.example.com/v1/namespaces/kube-bind-abc/mongodbs/mongo":        "mongodb.example.com/v1/mongodbs",
+		"/apis/mongodb.example.com/v1/namespaces/kube-bind-abc/mongodbs/mongo/status": "mongodb.example.com/v1/mongodbs",
+		"/apis/mongodb.example.com/v1/mongodbs":                                       "mongodb.example.com/v1/mongodbs",
+		"/api/v1/namespaces/kube-bind-abc/secrets":                                    "v1/secrets",
+		"/api/v1/namespaces/kube-bind-abc":                                            "v1/namespaces",
+		"/api/v1/namespaces":                                                          "v1/namespaces",
+		"/apis/mongodb.example.com/v1":                                                "",
+		"/version":                                                                    "",
+	}
+	for path, want := range tests {
+		require.Equal(t, want, gvrFromPath(path), path)
+	}
+}
+
+func TestInstrumentUpstream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: InstrumentUpstream("cluster-a")(http.DefaultTransport)}
+	resp, err := client.Get(server.URL + "/apis/mongodb.example.com/v1/namespaces/kube-bind-abc/mongodbs/mongo")
+	require.NoError(t, err)
+	resp.Body.Close() // nolint:errcheck
+
+	require.Equal(t, 1, testutil.CollectAndCount(upstreamRequestDuration.MustCurryWith(map[string]string{
+		"gvr": "mongodb.example.com/v1/mongodbs", "cluster_id": "cluster-a", "verb": "GET", "code": "404",
+	})))
+}
+
+func TestWorkqueueAndSyncs(t *testing.T) {
+	labels := NewLabels("mongodbs.mongodb.example.com", schema.GroupVersionResource{Group: "mongodb.example.com", Version: "v1", Resource: "mongodbs"}, "cluster-b", "cluster-a")
+	require.Equal(t, "cluster-a,cluster-b", labels.ClusterID)
+
+	queue := NewRateLimitingQueue("test", labels)
+	defer queue.ShutDown()
+	queue.Add("default/mongo")
+	queue.Add("default/other")
+	require.Equal(t, 2.0, testutil.ToFloat64(workqueueDepth.WithLabelValues("test", labels.Binding, labels.GVR, labels.ClusterID)))
+
+	ObserveSync("test", labels, nil)
+	ObserveSync("test", labels, errors.New("failed"))
+	ObserveSync("test", labels, errors.New("failed"))
+	require.Equal(t, 1.0, testutil.ToFloat64(syncs.WithLabelValues("test", labels.Binding, labels.GVR, labels.ClusterID, "success")))
+	require.Equal(t, 2.0, testutil.ToFloat64(syncs.WithLabelValues("test", labels.Binding, labels.GVR, labels.ClusterID, "error")))
+}
+
+func TestHeartbeats(t *testing.T) {
+	HeartbeatSucceeded("mongodbs.mongodb.example.com", "cluster-a")
+	require.Equal(t, 1, testutil.CollectAndCount(heartbeats))
+	require.Less(t, testutil.ToFloat64(heartbeats), 1.0)
+}
+
+func TestForget(t *testing.T) {
+	labels := NewLabels("foos.example.com", schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}, "cluster-c")
+	other := NewLabels("bars.example.com", schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "bars"}, "cluster-c")
+
+	for _, l := range []Labels{labels, other} {
+		queue := NewRateLimitingQueue("forget", l)
+		queue.Add("default/foo")
+		queue.ShutDown()
+		ObserveSync("forget", l, nil)
+		SetInformersSynced(l, true)
+		HeartbeatSucceeded(l.Binding, "cluster-c")
+	}
+
+	Forget("forget", labels)
+	ForgetInformersSynced(labels)
+	ForgetHeartbeat(labels.Binding, "cluster-c")
+
+	require.Equal(t, []string{other.Binding}, bindingsOf(t, syncs, "cluster-c"))
+	require.Equal(t, []string{other.Binding}, bindingsOf(t, workqueueAdds, "cluster-c"))
+	require.Equal(t, []string{other.Binding}, bindingsOf(t, informersSynced, "cluster-c"))
+
+	heartbeats.lock.Lock()
+	defer heartbeats.lock.Unlock()
+	require.NotContains(t, heartbeats.last, [2]string{labels.Binding, "cluster-c"})
+	require.Contains(t, heartbeats.last, [2]string{other.Binding, "cluster-c"})
+}
+
+// bindingsOf returns the binding labels of the series of c recorded for the given cluster ID.
+func bindingsOf(t *testing.T, c prometheus.Collector, clusterID string) []string {
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+
+	var bindings []string
+	for m := range ch {
+		var metric dto.Metric
+		require.NoError(t, m.Write(&metric))
+		labels := map[string]string{}
+		for _, pair := range metric.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if labels["cluster_id"] == clusterID {
+			bindings = append(bindings, labels["binding"])
+		}
+	}
+	return bindings
+}
+
+func TestLeader(t *testing.T) {
+	SetLeader(true)
+	require.Equal(t, 1.0, testutil.ToFloat64(leader))
+	SetLeader(false)
+	require.Equal(t, 0.0, testutil.ToFloat64(leader))
+}
@@ -0,0 +1,78 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// InstrumentUpstream returns a transport wrapper recording the latency of requests to the given
+// service provider cluster. Use it with rest.Config.Wrap.
+func InstrumentUpstream(clusterID string) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &upstreamRoundTripper{clusterID: clusterID, delegate: rt}
+	}
+}
+
+type upstreamRoundTripper struct {
+	clusterID string
+	delegate  http.RoundTripper
+}
+
+func (rt *upstreamRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := rt.delegate.RoundTrip(req)
+
+	verb := req.Method
+	if req.URL.Query().Get("watch") == "true" {
+		verb = "WATCH"
+	}
+	code := "<error>"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	upstreamRequestDuration.WithLabelValues(gvrFromPath(req.URL.Path), rt.clusterID, verb, code).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
+// gvrFromPath returns the GVR label of a Kubernetes API request path, or an empty string for
+// non-resource requests.
+func gvrFromPath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	var gvr schema.GroupVersionResource
+	switch {
+	case len(parts) >= 4 && parts[0] == "apis":
+		gvr.Group, gvr.Version, parts = parts[1], parts[2], parts[3:]
+	case len(parts) >= 3 && parts[0] == "api":
+		gvr.Version, parts = parts[1], parts[2:]
+	default:
+		return ""
+	}
+	if len(parts) > 2 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+	gvr.Resource = parts[0]
+
+	return GVRLabel(gvr)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var (
+	workqueueLabelNames = append([]string{"controller"}, labelNames...)
+
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "workqueue_depth",
+		Help:      "Current depth of the workqueue.",
+	}, workqueueLabelNames)
+
+	workqueueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "workqueue_adds_total",
+		Help:      "Total number of adds handled by the workqueue.",
+	}, workqueueLabelNames)
+
+	workqueueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "workqueue_queue_duration_seconds",
+		Help:      "How long in seconds an item stays in the workqueue before being requested.",
+		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
+	}, workqueueLabelNames)
+
+	workqueueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "workqueue_work_duration_seconds",
+		Help:      "How long in seconds processing an item from the workqueue takes.",
+		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
+	}, workqueueLabelNames)
+
+	workqueueUnfinishedWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "workqueue_unfinished_work_seconds",
+		Help:      "How many seconds of work has been done that is in progress and hasn't been observed by work_duration.",
+	}, workqueueLabelNames)
+
+	workqueueLongestRunningProcessor = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "workqueue_longest_running_processor_seconds",
+		Help:      "How many seconds the longest running processor of the workqueue has been running.",
+	}, workqueueLabelNames)
+
+	workqueueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "workqueue_retries_total",
+		Help:      "Total number of retries handled by the workqueue.",
+	}, workqueueLabelNames)
+
+	workqueueVecs = []interface {
+		prometheus.Collector
+		DeletePartialMatch(labels prometheus.Labels) int
+	}{
+		workqueueDepth,
+		workqueueAdds,
+		workqueueLatency,
+		workqueueWorkDuration,
+		workqueueUnfinishedWork,
+		workqueueLongestRunningProcessor,
+		workqueueRetries,
+	}
+)
+
+// NewRateLimitingQueue returns a rate limited workqueue of the given controller, recording its
+// metrics with the given labels.
+func NewRateLimitingQueue(controller string, labels Labels) workqueue.RateLimitingInterface {
+	return workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{
+		Name:            controller,
+		MetricsProvider: workqueueMetricsProvider{labels: labels},
+	})
+}
+
+// workqueueMetricsProvider provides the metrics of a workqueue, labeled with the queue name as controller.
+type workqueueMetricsProvider struct {
+	labels Labels
+}
+
+func (p workqueueMetricsProvider) values(name string) []string {
+	return append([]string{name}, p.labels.values()...)
+}
+
+func (p workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return workqueueDepth.WithLabelValues(p.values(name)...)
+}
+
+func (p workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return workqueueAdds.WithLabelValues(p.values(name)...)
+}
+
+func (p workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return workqueueLatency.WithLabelValues(p.values(name)...)
+}
+
+func (p workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return workqueueWorkDuration.WithLabelValues(p.values(name)...)
+}
+
+func (p workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueUnfinishedWork.WithLabelValues(p.values(name)...)
+}
+
+func (p workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueLongestRunningProcessor.WithLabelValues(p.values(name)...)
+}
+
+func (p workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return workqueueRetries.WithLabelValues(p.values(name)...)
+}
@@ -19,4 +19,11 @@ package models
 const (
 	AnnotationProviderClusterID = "provider.kube-bind.appscode.com/cluster-id"
 	KonnectorNamespace          = "ace"
+
+	// KonnectorServiceAccountName is the ServiceAccount the konnector runs as in KonnectorNamespace.
+	KonnectorServiceAccountName = "konnector"
+
+	// KonnectorClusterRoleAggregationLabelKey marks ClusterRoles whose rules are aggregated into the
+	// konnector's ClusterRole, e.g. the access to the resources of an APIServiceBinding.
+	KonnectorClusterRoleAggregationLabelKey = "kube-bind.appscode.com/aggregate-to-konnector"
 )
@@ -18,18 +18,21 @@ package models
 
 import (
 	"fmt"
+	"sync"
 
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/providerpool"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	dynamicclient "k8s.io/client-go/dynamic"
 	kubernetesinformers "k8s.io/client-go/informers"
 	kubernetesclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 )
 
 type ProviderInfo struct {
@@ -43,6 +46,46 @@ type ProviderInfo struct {
 	KubeInformer                                             kubernetesinformers.SharedInformerFactory
 	DynamicServiceNamespaceInformer                          dynamic.Informer[bindlisters.APIServiceNamespaceLister]
 	ProviderDynamicInformer                                  multinsinformer.GetterInformer
+
+	// Kubeconfig is the kubeconfig the provider is connected with.
+	Kubeconfig string
+	// Connection is the pooled connection the clients and informers above are taken from.
+	Connection *providerpool.Connection
+
+	lock          sync.Mutex
+	registrations []registration
+}
+
+type registration struct {
+	informer     cache.SharedInformer
+	registration cache.ResourceEventHandlerRegistration
+}
+
+// AddEventHandler adds a handler to an informer of the pooled provider connection, which is
+// shared with other bindings. It is removed again by RemoveEventHandlers.
+func (p *ProviderInfo) AddEventHandler(informer cache.SharedInformer, handler cache.ResourceEventHandler) error {
+	r, err := informer.AddEventHandler(handler)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.registrations = append(p.registrations, registration{informer: informer, registration: r})
+
+	return nil
+}
+
+// RemoveEventHandlers removes the handlers added by AddEventHandler, e.g. when the controllers
+// of the provider stop.
+func (p *ProviderInfo) RemoveEventHandlers() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, r := range p.registrations {
+		r.informer.RemoveEventHandler(r.registration) // nolint:errcheck
+	}
+	p.registrations = nil
 }
 
 func GetProviderInfoWithClusterID(providerInfos []*ProviderInfo, clusterID string) (*ProviderInfo, error) {
@@ -86,3 +129,12 @@ func GetProviderFromObjectInterface(providerInfos []*ProviderInfo, obj interface
 	}
 	return nil, fmt.Errorf("no provider found for object %s", unstr.GetName())
 }
+
+// ClusterIDs returns the cluster IDs of the given providers.
+func ClusterIDs(providerInfos []*ProviderInfo) []string {
+	ids := make([]string, 0, len(providerInfos))
+	for _, info := range providerInfos {
+		ids = append(ids, info.ClusterID)
+	}
+	return ids
+}
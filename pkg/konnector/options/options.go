@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"time"
 
 	"github.com/spf13/pflag"
 	"k8s.io/component-base/logs"
@@ -38,6 +39,24 @@ type ExtraOptions struct {
 	LeaseLockName      string
 	LeaseLockNamespace string
 	LeaseLockIdentity  string
+
+	Sharding bool
+
+	HeartbeatInterval time.Duration
+
+	MetricsBindAddress string
+
+	HealthBindAddress string
+	HealthPort        int
+
+	ConversionWebhookBindAddress      string
+	ConversionWebhookCertFile         string
+	ConversionWebhookKeyFile          string
+	ConversionWebhookCAFile           string
+	ConversionWebhookTLSSecretName    string
+	ConversionWebhookServiceName      string
+	ConversionWebhookServiceNamespace string
+	ConversionWebhookServicePort      int32
 }
 
 type completedOptions struct {
@@ -62,12 +81,25 @@ func NewOptions() *Options {
 			LeaseLockName:      "kube-bind",
 			LeaseLockNamespace: os.Getenv("POD_NAMESPACE"),
 			LeaseLockIdentity:  os.Getenv("POD_NAME"),
+
+			HeartbeatInterval: 5 * time.Minute,
+
+			MetricsBindAddress: ":8080",
+			HealthBindAddress:  "0.0.0.0",
+			HealthPort:         8081,
+
+			ConversionWebhookServiceName:      "konnector",
+			ConversionWebhookServiceNamespace: os.Getenv("POD_NAMESPACE"),
+			ConversionWebhookServicePort:      443,
 		},
 	}
 
 	if opts.LeaseLockNamespace == "" {
 		opts.LeaseLockNamespace = "kube-system"
 	}
+	if opts.ConversionWebhookServiceNamespace == "" {
+		opts.ConversionWebhookServiceNamespace = "kube-bind"
+	}
 
 	return opts
 }
@@ -78,6 +110,23 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&options.KubeConfigPath, "kubeconfig", options.KubeConfigPath, "Kubeconfig file for the local cluster.")
 	fs.StringVar(&options.LeaseLockName, "lease-name", options.LeaseLockName, "Name of lease lock")
 	fs.StringVar(&options.LeaseLockNamespace, "lease-namespace", options.LeaseLockNamespace, "Name of lease lock namespace")
+	fs.BoolVar(&options.Sharding, "sharding", options.Sharding, "Run all replicas actively instead of electing a leader, each syncing a consistent-hash share of the service provider connections and their APIServiceBindings. Replicas announce themselves through member leases named after --lease-name in --lease-namespace.")
+
+	fs.DurationVar(&options.HeartbeatInterval, "heartbeat-interval", options.HeartbeatInterval, "Maximal interval between heartbeats to the service provider clusters. It can be overridden per APIServiceBinding, and the service provider can suggest its own in the ClusterBinding.")
+
+	fs.StringVar(&options.MetricsBindAddress, "metrics-bind-address", options.MetricsBindAddress, "Address to serve Prometheus metrics on /metrics, e.g. :8080. If empty, metrics are not served.")
+
+	fs.StringVar(&options.HealthBindAddress, "health-bind-address", options.HealthBindAddress, "IP address to serve /healthz and /readyz on.")
+	fs.IntVar(&options.HealthPort, "health-port", options.HealthPort, "Port to serve /healthz and /readyz on. If 0, health checks are not served.")
+
+	fs.StringVar(&options.ConversionWebhookBindAddress, "conversion-webhook-bind-address", options.ConversionWebhookBindAddress, "Address to serve conversion webhooks of bound CRDs on, e.g. :9443. If empty, bound CRDs with conversion webhooks serve only their storage version.")
+	fs.StringVar(&options.ConversionWebhookCertFile, "conversion-webhook-tls-cert-file", options.ConversionWebhookCertFile, "Serving certificate file of the conversion webhook.")
+	fs.StringVar(&options.ConversionWebhookKeyFile, "conversion-webhook-tls-key-file", options.ConversionWebhookKeyFile, "Serving private key file of the conversion webhook.")
+	fs.StringVar(&options.ConversionWebhookCAFile, "conversion-webhook-ca-file", options.ConversionWebhookCAFile, "CA bundle file the consumer API server uses to verify the conversion webhook serving certificate.")
+	fs.StringVar(&options.ConversionWebhookTLSSecretName, "conversion-webhook-tls-secret-name", options.ConversionWebhookTLSSecretName, "Name of a kubernetes.io/tls Secret in --conversion-webhook-service-namespace holding a self-signed serving certificate of the conversion webhook, shared by all replicas. It is generated if missing or about to expire. Used instead of the certificate, key and CA files.")
+	fs.StringVar(&options.ConversionWebhookServiceName, "conversion-webhook-service-name", options.ConversionWebhookServiceName, "Name of the service pointing to the conversion webhook.")
+	fs.StringVar(&options.ConversionWebhookServiceNamespace, "conversion-webhook-service-namespace", options.ConversionWebhookServiceNamespace, "Namespace of the service pointing to the conversion webhook.")
+	fs.Int32Var(&options.ConversionWebhookServicePort, "conversion-webhook-service-port", options.ConversionWebhookServicePort, "Port of the service pointing to the conversion webhook.")
 }
 
 func (options *Options) Complete() (*CompletedOptions, error) {
@@ -94,5 +143,22 @@ func (options *Options) Complete() (*CompletedOptions, error) {
 }
 
 func (options *CompletedOptions) Validate() error {
+	if options.ConversionWebhookTLSSecretName != "" && (options.ConversionWebhookCertFile != "" || options.ConversionWebhookKeyFile != "" || options.ConversionWebhookCAFile != "") {
+		return fmt.Errorf("--conversion-webhook-tls-secret-name is mutually exclusive with the conversion webhook certificate, key and CA files")
+	}
+	if options.ConversionWebhookBindAddress != "" && options.ConversionWebhookTLSSecretName == "" {
+		if options.ConversionWebhookCertFile == "" || options.ConversionWebhookKeyFile == "" {
+			return fmt.Errorf("--conversion-webhook-tls-cert-file and --conversion-webhook-tls-key-file or --conversion-webhook-tls-secret-name are required with --conversion-webhook-bind-address")
+		}
+		if options.ConversionWebhookCAFile == "" {
+			return fmt.Errorf("--conversion-webhook-ca-file is required with --conversion-webhook-bind-address")
+		}
+	}
+	if options.HeartbeatInterval <= 0 {
+		return fmt.Errorf("--heartbeat-interval must be positive")
+	}
+	if options.HealthPort < 0 || options.HealthPort > 65535 {
+		return fmt.Errorf("--health-port must be between 0 and 65535")
+	}
 	return nil
 }
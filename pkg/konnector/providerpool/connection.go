@@ -0,0 +1,232 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providerpool
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
+	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	kubernetesinformers "k8s.io/client-go/informers"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Connection holds the clients and informers of one service provider namespace. Its informers
+// run from the first Start until the last reference is released.
+type Connection struct {
+	Key    Key
+	Config *rest.Config
+
+	Client     dynamicclient.Interface
+	BindClient *bindclient.Clientset
+	KubeClient *kubernetesclient.Clientset
+
+	BindInformer             bindinformers.SharedInformerFactory
+	KubeInformer             kubernetesinformers.SharedInformerFactory
+	ServiceNamespaceInformer dynamic.Informer[bindlisters.APIServiceNamespaceLister]
+
+	kubeconfig string
+	refs       int // guarded by the lock of the Pool
+
+	ctx    context.Context
+	cancel func()
+
+	lock             sync.Mutex
+	dynamicInformers map[DynamicInformerKey]*sharedDynamicInformer
+}
+
+func newConnection(key Key, config *rest.Config) (*Connection, error) {
+	conn := &Connection{
+		Key:              key,
+		Config:           config,
+		dynamicInformers: map[DynamicInformerKey]*sharedDynamicInformer{},
+	}
+
+	var err error
+	if conn.Client, err = dynamicclient.NewForConfig(config); err != nil {
+		return nil, err
+	}
+	if conn.BindClient, err = bindclient.NewForConfig(config); err != nil {
+		return nil, err
+	}
+	if conn.KubeClient, err = kubernetesclient.NewForConfig(config); err != nil {
+		return nil, err
+	}
+
+	conn.BindInformer = bindinformers.NewSharedInformerFactoryWithOptions(conn.BindClient, time.Minute*30, bindinformers.WithNamespace(key.Namespace))
+	conn.KubeInformer = kubernetesinformers.NewSharedInformerFactoryWithOptions(conn.KubeClient, time.Minute*30, kubernetesinformers.WithNamespace(key.Namespace))
+
+	// wire up all informers and indexers upfront. Indexers cannot be added anymore once the
+	// informers of a connection used by other bindings have started.
+	conn.BindInformer.KubeBind().V1alpha1().ClusterBindings().Informer()
+	conn.KubeInformer.Core().V1().Secrets().Informer()
+	indexers.AddIfNotPresentOrDie(conn.BindInformer.KubeBind().V1alpha1().APIServiceExports().Informer().GetIndexer(), cache.Indexers{
+		indexers.ServiceExportByCustomResourceDefinition: indexers.IndexServiceExportByCustomResourceDefinition,
+	})
+	indexers.AddIfNotPresentOrDie(conn.BindInformer.KubeBind().V1alpha1().APIServiceNamespaces().Informer().GetIndexer(), cache.Indexers{
+		indexers.ServiceNamespaceByNamespace: indexers.IndexServiceNamespaceByNamespace,
+	})
+	conn.ServiceNamespaceInformer = dynamic.NewDynamicInformer[bindlisters.APIServiceNamespaceLister](conn.BindInformer.KubeBind().V1alpha1().APIServiceNamespaces())
+
+	conn.ctx, conn.cancel = context.WithCancel(context.Background())
+
+	return conn, nil
+}
+
+// Start starts the informers of the connection. Informers requested after a Start are started
+// by the next one.
+func (c *Connection) Start() {
+	c.BindInformer.Start(c.ctx.Done())
+	c.KubeInformer.Start(c.ctx.Done())
+}
+
+// WaitForCacheSync waits for the started informers of the connection to sync.
+func (c *Connection) WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool {
+	synced := c.BindInformer.WaitForCacheSync(stopCh)
+	for t, s := range c.KubeInformer.WaitForCacheSync(stopCh) {
+		synced[t] = s
+	}
+	return synced
+}
+
+func (c *Connection) stop() {
+	c.cancel()
+}
+
+// DynamicInformerScope is the set of namespaces a dynamic informer watches.
+type DynamicInformerScope int
+
+const (
+	// ProviderNamespace watches the provider namespace of the connection.
+	ProviderNamespace DynamicInformerScope = iota
+	// AllNamespaces watches the whole provider cluster.
+	AllNamespaces
+	// ServiceNamespaces watches the namespaces of the APIServiceNamespaces in the provider namespace.
+	ServiceNamespaces
+)
+
+// DynamicInformerKey identifies a dynamic informer shared by the APIServiceExports of a connection.
+type DynamicInformerKey struct {
+	GVR   schema.GroupVersionResource
+	Scope DynamicInformerScope
+
+	// TweakName names the list options tweak of the informer. Informers with equal keys are
+	// expected to use the same tweak.
+	TweakName string
+}
+
+type sharedDynamicInformer struct {
+	informer multinsinformer.GetterInformer
+	refs     int
+	cancel   func()
+}
+
+// DynamicInformer returns a started informer for the given key, shared with the other users of the
+// connection, and a func to release it. Event handlers added to the returned informer are removed
+// on release, and the informer stops with the last release.
+func (c *Connection) DynamicInformer(key DynamicInformerKey, tweakListOptions dynamicinformer.TweakListOptionsFunc) (multinsinformer.GetterInformer, func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	shared, found := c.dynamicInformers[key]
+	if !found {
+		var informer multinsinformer.GetterInformer
+		switch key.Scope {
+		case ProviderNamespace, AllNamespaces:
+			namespace := c.Key.Namespace
+			if key.Scope == AllNamespaces {
+				namespace = metav1.NamespaceAll
+			}
+			factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.Client, time.Minute*30, namespace, tweakListOptions)
+			factory.ForResource(key.GVR).Lister() // wire the GVR up in the informer factory
+			informer = multinsinformer.GetterInformerWrapper{
+				GVR:      key.GVR,
+				Delegate: factory,
+			}
+		case ServiceNamespaces:
+			informer = multinsinformer.NewFilteredDynamicMultiNamespaceInformer(key.GVR, c.Key.Namespace, c.Client, c.ServiceNamespaceInformer, tweakListOptions)
+		}
+
+		ctx, cancel := context.WithCancel(c.ctx)
+		informer.Start(ctx)
+
+		shared = &sharedDynamicInformer{informer: informer, cancel: cancel}
+		c.dynamicInformers[key] = shared
+	}
+	shared.refs++
+
+	ref := &dynamicInformerRef{GetterInformer: shared.informer}
+	var once sync.Once
+	return ref, func() {
+		once.Do(func() {
+			ref.removeEventHandlers()
+
+			c.lock.Lock()
+			defer c.lock.Unlock()
+			shared.refs--
+			if shared.refs == 0 {
+				shared.cancel()
+				delete(c.dynamicInformers, key)
+			}
+		})
+	}
+}
+
+// dynamicInformerRef is one reference to a shared dynamic informer. It is started by the
+// Connection, and remembers its event handlers to remove them on release.
+type dynamicInformerRef struct {
+	multinsinformer.GetterInformer
+
+	lock    sync.Mutex
+	removes []func()
+}
+
+func (r *dynamicInformerRef) AddEventHandler(handler cache.ResourceEventHandler) func() {
+	remove := r.GetterInformer.AddEventHandler(handler)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.removes = append(r.removes, remove)
+
+	return remove
+}
+
+func (r *dynamicInformerRef) Start(_ context.Context) {}
+
+func (r *dynamicInformerRef) removeEventHandlers() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, remove := range r.removes {
+		remove()
+	}
+	r.removes = nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providerpool
+
+import (
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// Key identifies a connection to a service provider by the provider cluster UID and the
+// provider namespace the kubeconfig points to.
+type Key struct {
+	ClusterUID string
+	Namespace  string
+}
+
+// Pool shares the connections to service providers, i.e. clients and informers, between all
+// APIServiceBindings and APIServiceExports of the same provider namespace. Connections are
+// reference-counted and stopped when the last reference is released.
+type Pool struct {
+	lock        sync.Mutex
+	connections map[Key]*Connection
+}
+
+// New returns an empty Pool.
+func New() *Pool {
+	return &Pool{
+		connections: map[Key]*Connection{},
+	}
+}
+
+// Acquire returns the connection for the given key, creating it from config if there is none
+// yet. A connection created from a different kubeconfig, e.g. before rotating credentials, is not
+// returned anymore, but kept until all its references are released. Every Acquire must be
+// followed by a Release of the returned connection.
+func (p *Pool) Acquire(key Key, kubeconfig string, config *rest.Config) (*Connection, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if conn, found := p.connections[key]; found && conn.kubeconfig == kubeconfig {
+		conn.refs++
+		return conn, nil
+	}
+
+	conn, err := newConnection(key, config)
+	if err != nil {
+		return nil, err
+	}
+	conn.kubeconfig = kubeconfig
+	conn.refs = 1
+	p.connections[key] = conn
+
+	klog.Background().V(2).Info("opened provider connection", "clusterUID", key.ClusterUID, "namespace", key.Namespace)
+
+	return conn, nil
+}
+
+// Release drops a reference to the given connection, and stops it with the last reference.
+func (p *Pool) Release(conn *Connection) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	conn.refs--
+	if conn.refs > 0 {
+		return
+	}
+
+	if p.connections[conn.Key] == conn {
+		delete(p.connections, conn.Key)
+	}
+	conn.stop()
+
+	klog.Background().V(2).Info("closed provider connection", "clusterUID", conn.Key.ClusterUID, "namespace", conn.Key.Namespace)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providerpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	pool := New()
+	key := Key{ClusterUID: "cluster-a", Namespace: "kube-bind-abc"}
+	config := &rest.Config{Host: "https://127.0.0.1:1"}
+
+	a, err := pool.Acquire(key, "kubeconfig-1", config)
+	require.NoError(t, err)
+	b, err := pool.Acquire(key, "kubeconfig-1", config)
+	require.NoError(t, err)
+	require.Same(t, a, b, "same provider namespace should share the connection")
+
+	other, err := pool.Acquire(Key{ClusterUID: "cluster-a", Namespace: "kube-bind-def"}, "kubeconfig-1", config)
+	require.NoError(t, err)
+	require.NotSame(t, a, other)
+
+	pool.Release(a)
+	require.NoError(t, a.ctx.Err(), "connection should run while referenced")
+	require.Contains(t, pool.connections, key)
+
+	pool.Release(b)
+	require.Error(t, a.ctx.Err(), "connection should stop with the last reference")
+	require.NotContains(t, pool.connections, key)
+
+	pool.Release(other)
+	require.Empty(t, pool.connections)
+}
+
+func TestAcquireChangedKubeconfig(t *testing.T) {
+	pool := New()
+	key := Key{ClusterUID: "cluster-a", Namespace: "kube-bind-abc"}
+	config := &rest.Config{Host: "https://127.0.0.1:1"}
+
+	old, err := pool.Acquire(key, "kubeconfig-1", config)
+	require.NoError(t, err)
+	rotated, err := pool.Acquire(key, "kubeconfig-2", config)
+	require.NoError(t, err)
+	require.NotSame(t, old, rotated, "a changed kubeconfig should open a new connection")
+	require.Same(t, rotated, pool.connections[key])
+
+	pool.Release(old)
+	require.Error(t, old.ctx.Err())
+	require.Same(t, rotated, pool.connections[key], "releasing the old connection should keep the new one")
+	require.NoError(t, rotated.ctx.Err())
+
+	pool.Release(rotated)
+	require.Empty(t, pool.connections)
+}
+
+func TestDynamicInformer(t *testing.T) {
+	pool := New()
+	conn, err := pool.Acquire(Key{ClusterUID: "cluster-a", Namespace: "kube-bind-abc"}, "kubeconfig", &rest.Config{Host: "https://127.0.0.1:1"})
+	require.NoError(t, err)
+	defer pool.Release(conn)
+
+	key := DynamicInformerKey{GVR: schema.GroupVersionResource{Group: "mongodb.example.com", Version: "v1", Resource: "mongodbs"}, Scope: ProviderNamespace}
+	_, releaseA := conn.DynamicInformer(key, nil)
+	_, releaseB := conn.DynamicInformer(key, nil)
+	require.Len(t, conn.dynamicInformers, 1, "exports of the same GVR should share the informer")
+	shared := conn.dynamicInformers[key]
+
+	releaseA()
+	releaseA() // releasing twice is a no-op
+	require.Equal(t, 1, shared.refs)
+
+	releaseB()
+	require.Empty(t, conn.dynamicInformers)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// RelatedResourcesClusterRoleName grants access to Secrets and ConfigMaps. The konnector binds it
+	// through a RoleBinding of the same name in every namespace with downstream objects referencing
+	// related or status resources. It is never bound cluster-wide.
+	RelatedResourcesClusterRoleName = "ace-konnector-related"
+
+	// LegacyRelatedResourcesClusterRoleName is the ClusterRole older konnector deployments aggregated
+	// into the konnector ClusterRole, granting access to all Secrets and ConfigMaps. The konnector
+	// deletes it.
+	LegacyRelatedResourcesClusterRoleName = "ace-konnector-related-resources"
+)
+
+// BindingClusterRoleName returns the name of the ClusterRole granting the konnector access to the
+// resource of the APIServiceBinding with the given name.
+func BindingClusterRoleName(binding string) string {
+	return "ace-konnector-" + binding
+}
+
+// BindingClusterRole returns the ClusterRole granting the konnector access to the bound resource. It is
+// created by kubectl bind-apiservice, aggregated into the konnector ClusterRole, and owned by the
+// APIServiceBinding to be garbage collected with it.
+func BindingClusterRole(binding *v1alpha1.APIServiceBinding, group, resource string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: BindingClusterRoleName(binding.Name),
+			Labels: map[string]string{
+				models.KonnectorClusterRoleAggregationLabelKey: "true",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: v1alpha1.SchemeGroupVersion.String(),
+					Kind:       "APIServiceBinding",
+					Name:       binding.Name,
+					UID:        binding.UID,
+				},
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{group},
+				Resources: []string{resource, resource + "/status"},
+				Verbs:     []string{rbacv1.VerbAll},
+			},
+		},
+	}
+}
+
+// NeedsUpdate returns whether the existing ClusterRole of a binding differs from the expected one.
+func NeedsUpdate(existing, expected *rbacv1.ClusterRole) bool {
+	return !reflect.DeepEqual(existing.Labels, expected.Labels) ||
+		!reflect.DeepEqual(existing.OwnerReferences, expected.OwnerReferences) ||
+		!reflect.DeepEqual(existing.Rules, expected.Rules)
+}
+
+// RoleBinder binds the RelatedResourcesClusterRoleName ClusterRole to the konnector in the namespaces
+// of downstream objects on demand. Namespaces are only bound once per konnector process.
+type RoleBinder struct {
+	client kubernetes.Interface
+
+	lock  sync.Mutex
+	bound sets.Set[string]
+}
+
+// NewRoleBinder returns a RoleBinder creating RoleBindings with the given client.
+func NewRoleBinder(client kubernetes.Interface) *RoleBinder {
+	return &RoleBinder{
+		client: client,
+		bound:  sets.New[string](),
+	}
+}
+
+// Ensure makes sure the konnector can access Secrets and ConfigMaps in the given namespace.
+func (b *RoleBinder) Ensure(ctx context.Context, ns string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.bound.Has(ns) {
+		return nil
+	}
+
+	_, err := b.client.RbacV1().RoleBindings(ns).Get(ctx, RelatedResourcesClusterRoleName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = b.client.RbacV1().RoleBindings(ns).Create(ctx, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      RelatedResourcesClusterRoleName,
+				Namespace: ns,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      "ServiceAccount",
+					Namespace: models.KonnectorNamespace,
+					Name:      models.KonnectorServiceAccountName,
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     RelatedResourcesClusterRoleName,
+			},
+		}, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to bind ClusterRole %s in namespace %s: %w", RelatedResourcesClusterRoleName, ns, err)
+	}
+
+	b.bound.Insert(ns)
+	return nil
+}
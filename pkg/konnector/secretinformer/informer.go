@@ -0,0 +1,324 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretinformer
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Informer watches only the Secrets referenced as kubeconfigs by APIServiceBindings, each with
+// its own watch selected by name, instead of all Secrets of the cluster.
+type Informer struct {
+	client kubernetesclient.Interface
+	resync time.Duration
+
+	lock      sync.RWMutex
+	stopCh    <-chan struct{}             // nil until started
+	secrets   map[string]*secretInformer  // by namespace/name
+	bindings  map[string]sets.Set[string] // secret keys by binding name
+	handlers  map[int]cache.ResourceEventHandler
+	handlerID int
+}
+
+type secretInformer struct {
+	informer cache.SharedIndexInformer
+	stop     chan struct{}
+}
+
+// New returns an Informer watching the kubeconfig Secrets of the APIServiceBindings of the given
+// informer. Secrets are watched as soon as a binding references them, and not anymore when no
+// binding does.
+func New(client kubernetesclient.Interface, serviceBindingInformer bindinformers.APIServiceBindingInformer, resync time.Duration) (*Informer, error) {
+	i := newInformer(client, resync)
+
+	_, err := serviceBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			i.watchServiceBinding(obj, false)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			i.watchServiceBinding(newObj, false)
+		},
+		DeleteFunc: func(obj interface{}) {
+			i.watchServiceBinding(obj, true)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func newInformer(client kubernetesclient.Interface, resync time.Duration) *Informer {
+	return &Informer{
+		client:   client,
+		resync:   resync,
+		secrets:  map[string]*secretInformer{},
+		bindings: map[string]sets.Set[string]{},
+		handlers: map[int]cache.ResourceEventHandler{},
+	}
+}
+
+func (i *Informer) watchServiceBinding(obj interface{}, deleted bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*kubebindv1alpha1.APIServiceBinding)
+	if !ok {
+		return
+	}
+
+	keys := sets.New[string]()
+	if !deleted {
+		keys.Insert(indexers.ByServiceBindingKubeconfigSecretKey(binding)...)
+	}
+	i.Watch(binding.Name, keys)
+}
+
+// Watch sets the Secret keys, i.e. namespace/name, the given binding references. Secrets not
+// referenced by any binding anymore are not watched anymore.
+func (i *Informer) Watch(binding string, keys sets.Set[string]) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if keys.Len() == 0 {
+		delete(i.bindings, binding)
+	} else {
+		i.bindings[binding] = keys
+	}
+
+	wanted := sets.New[string]()
+	for _, keys := range i.bindings {
+		wanted = wanted.Union(keys)
+	}
+	for key, s := range i.secrets {
+		if !wanted.Has(key) {
+			close(s.stop)
+			delete(i.secrets, key)
+		}
+	}
+	for key := range wanted {
+		if _, found := i.secrets[key]; !found {
+			i.secrets[key] = i.newSecretInformer(key)
+		}
+	}
+}
+
+func (i *Informer) newSecretInformer(key string) *secretInformer {
+	ns, name, _ := cache.SplitMetaNamespaceKey(key) // nolint:errcheck
+	selector := fields.OneTermEqualSelector(metav1.ObjectNameField, name).String()
+
+	s := &secretInformer{
+		informer: cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					options.FieldSelector = selector
+					return i.client.CoreV1().Secrets(ns).List(context.Background(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					options.FieldSelector = selector
+					return i.client.CoreV1().Secrets(ns).Watch(context.Background(), options)
+				},
+			},
+			&corev1.Secret{},
+			i.resync,
+			cache.Indexers{},
+		),
+		stop: make(chan struct{}),
+	}
+
+	// nolint:errcheck
+	s.informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			for _, h := range i.currentHandlers() {
+				h.OnAdd(obj, isInInitialList)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			for _, h := range i.currentHandlers() {
+				h.OnUpdate(oldObj, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			for _, h := range i.currentHandlers() {
+				h.OnDelete(obj)
+			}
+		},
+	})
+
+	if i.stopCh != nil {
+		i.run(s)
+	}
+
+	return s
+}
+
+func (i *Informer) run(s *secretInformer) {
+	stopCh := i.stopCh
+	stop := make(chan struct{})
+	go func() {
+		defer close(stop)
+		select {
+		case <-stopCh:
+		case <-s.stop:
+		}
+	}()
+	go s.informer.Run(stop)
+}
+
+func (i *Informer) currentHandlers() []cache.ResourceEventHandler {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	handlers := make([]cache.ResourceEventHandler, 0, len(i.handlers))
+	for _, h := range i.handlers {
+		handlers = append(handlers, h)
+	}
+	return handlers
+}
+
+// Start starts watching the referenced Secrets until stopCh is closed.
+func (i *Informer) Start(stopCh <-chan struct{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.stopCh != nil {
+		return
+	}
+	i.stopCh = stopCh
+	for _, s := range i.secrets {
+		i.run(s)
+	}
+}
+
+// WaitForCacheSync waits for the currently watched Secrets to sync, like a shared informer factory.
+func (i *Informer) WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool {
+	i.lock.RLock()
+	started := i.stopCh != nil
+	synced := make([]cache.InformerSynced, 0, len(i.secrets))
+	for _, s := range i.secrets {
+		synced = append(synced, s.informer.HasSynced)
+	}
+	i.lock.RUnlock()
+
+	if !started {
+		return map[reflect.Type]bool{}
+	}
+	return map[reflect.Type]bool{
+		reflect.TypeOf(&corev1.Secret{}): cache.WaitForCacheSync(stopCh, synced...),
+	}
+}
+
+// AddEventHandler adds a handler for the events of all watched Secrets.
+func (i *Informer) AddEventHandler(handler cache.ResourceEventHandler) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.handlers[i.handlerID] = handler
+	i.handlerID++
+}
+
+// AddDynamicEventHandler is like AddEventHandler, but the handler is removed when ctx is done.
+// The handler receives add events for the Secrets known already.
+func (i *Informer) AddDynamicEventHandler(ctx context.Context, handler cache.ResourceEventHandler) {
+	i.lock.Lock()
+	id := i.handlerID
+	i.handlers[id] = handler
+	i.handlerID++
+	i.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		i.lock.Lock()
+		defer i.lock.Unlock()
+		delete(i.handlers, id)
+	}()
+
+	secrets, _ := i.Lister().List(labels.Everything()) // nolint:errcheck
+	for _, secret := range secrets {
+		handler.OnAdd(secret, true)
+	}
+}
+
+// Lister returns a lister of the watched Secrets.
+func (i *Informer) Lister() corelisters.SecretLister {
+	return &lister{informer: i}
+}
+
+type lister struct {
+	informer  *Informer
+	namespace string
+}
+
+func (l *lister) List(selector labels.Selector) ([]*corev1.Secret, error) {
+	l.informer.lock.RLock()
+	defer l.informer.lock.RUnlock()
+
+	var ret []*corev1.Secret
+	for _, s := range l.informer.secrets {
+		for _, obj := range s.informer.GetStore().List() {
+			secret := obj.(*corev1.Secret)
+			if l.namespace != "" && secret.Namespace != l.namespace {
+				continue
+			}
+			if selector.Matches(labels.Set(secret.Labels)) {
+				ret = append(ret, secret)
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (l *lister) Secrets(namespace string) corelisters.SecretNamespaceLister {
+	return &lister{informer: l.informer, namespace: namespace}
+}
+
+func (l *lister) Get(name string) (*corev1.Secret, error) {
+	l.informer.lock.RLock()
+	defer l.informer.lock.RUnlock()
+
+	key := l.namespace + "/" + name
+	if s, found := l.informer.secrets[key]; found {
+		obj, exists, err := s.informer.GetStore().GetByKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return obj.(*corev1.Secret), nil
+		}
+	}
+	return nil, errors.NewNotFound(corev1.Resource("secret"), name)
+}
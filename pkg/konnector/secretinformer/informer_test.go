@@ -0,0 +1,87 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretinformer
+
+import (
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+func binding(name string, secrets ...string) *kubebindv1alpha1.APIServiceBinding {
+	b := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, secret := range secrets {
+		b.Spec.Providers = append(b.Spec.Providers, kubebindv1alpha1.Provider{
+			Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+				LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: secret, Key: "kubeconfig"},
+				Namespace:         "kube-bind",
+			},
+		})
+	}
+	return b
+}
+
+func TestWatch(t *testing.T) {
+	i := newInformer(nil, time.Minute)
+
+	i.watchServiceBinding(binding("foo", "kubeconfig-a"), false)
+	i.watchServiceBinding(binding("bar", "kubeconfig-a", "kubeconfig-b"), false)
+	require.ElementsMatch(t, []string{"kube-bind/kubeconfig-a", "kube-bind/kubeconfig-b"}, sets.List(sets.KeySet(i.secrets)))
+
+	i.watchServiceBinding(binding("bar", "kubeconfig-b"), false)
+	require.ElementsMatch(t, []string{"kube-bind/kubeconfig-a", "kube-bind/kubeconfig-b"}, sets.List(sets.KeySet(i.secrets)))
+
+	i.watchServiceBinding(cache.DeletedFinalStateUnknown{Obj: binding("foo", "kubeconfig-a")}, true)
+	require.ElementsMatch(t, []string{"kube-bind/kubeconfig-b"}, sets.List(sets.KeySet(i.secrets)))
+
+	i.watchServiceBinding(binding("bar"), false)
+	require.Empty(t, i.secrets)
+}
+
+func TestLister(t *testing.T) {
+	i := newInformer(nil, time.Minute)
+	i.watchServiceBinding(binding("foo", "kubeconfig-a", "kubeconfig-b"), false)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig-a"}}
+	require.NoError(t, i.secrets["kube-bind/kubeconfig-a"].informer.GetStore().Add(secret))
+
+	got, err := i.Lister().Secrets("kube-bind").Get("kubeconfig-a")
+	require.NoError(t, err)
+	require.Equal(t, secret, got)
+
+	_, err = i.Lister().Secrets("kube-bind").Get("kubeconfig-b")
+	require.True(t, errors.IsNotFound(err))
+	_, err = i.Lister().Secrets("default").Get("kubeconfig-a")
+	require.True(t, errors.IsNotFound(err))
+
+	secrets, err := i.Lister().List(labels.Everything())
+	require.NoError(t, err)
+	require.Equal(t, []*corev1.Secret{secret}, secrets)
+
+	i.watchServiceBinding(binding("foo", "kubeconfig-b"), false)
+	_, err = i.Lister().Secrets("kube-bind").Get("kubeconfig-a")
+	require.True(t, errors.IsNotFound(err))
+}
@@ -18,35 +18,90 @@ package konnector
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/health"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
+	konnectorrbac "go.bytebuilders.dev/kube-bind/pkg/konnector/rbac"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/klog/v2"
 	"kmodules.xyz/client-go/apiextensions"
 )
 
 type Server struct {
-	Config     *Config
-	Controller *Controller
+	Config            *Config
+	Controller        *Controller
+	ConversionWebhook *conversion.Webhook
+
+	// conversionWebhookCert is the serving certificate of the conversion webhook if taken from
+	// the shared Secret instead of files.
+	conversionWebhookCert *tls.Certificate
 }
 
 func NewServer(config *Config) (*Server, error) {
+	var webhook *conversion.Webhook
+	var webhookCert *tls.Certificate
+	if config.Options.ConversionWebhookBindAddress != "" {
+		var caBundle []byte
+		if name := config.Options.ConversionWebhookTLSSecretName; name != "" {
+			keyPair, bundle, err := conversion.EnsureServingCertSecret(context.Background(), config.KubeClient,
+				config.Options.ConversionWebhookServiceNamespace, name, config.Options.ConversionWebhookServiceName)
+			if err != nil {
+				return nil, err
+			}
+			webhookCert, caBundle = &keyPair, bundle
+		} else {
+			var err error
+			if caBundle, err = os.ReadFile(config.Options.ConversionWebhookCAFile); err != nil {
+				return nil, fmt.Errorf("failed to read conversion webhook CA file: %w", err)
+			}
+		}
+		webhook = &conversion.Webhook{
+			ServiceNamespace: config.Options.ConversionWebhookServiceNamespace,
+			ServiceName:      config.Options.ConversionWebhookServiceName,
+			ServicePort:      config.Options.ConversionWebhookServicePort,
+			CABundle:         caBundle,
+			// every replica behind the service answers for all APIServiceBindings, also those
+			// synced by other replicas.
+			Resolver: conversion.NewBindingResolver(
+				config.BindInformers.KubeBind().V1alpha1().APIServiceBindings().Lister(),
+				config.SecretInformer.Lister(),
+			),
+		}
+	}
+
 	// construct controllers
 	k, err := New(
 		config.ClientConfig,
 		config.BindInformers.KubeBind().V1alpha1().APIServiceBindings(),
-		config.KubeInformers.Core().V1().Secrets(), // TODO(sttts): watch individual secrets for security and memory consumption
+		config.SecretInformer,
 		config.KubeInformers.Core().V1().Namespaces(),
 		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		webhook,
+		config.Options.HeartbeatInterval,
+		config.Sharder,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	s := &Server{
-		Config:     config,
-		Controller: k,
+		Config:            config,
+		Controller:        k,
+		ConversionWebhook: webhook,
+
+		conversionWebhookCert: webhookCert,
 	}
 
 	return s, nil
@@ -67,6 +122,12 @@ func (s *Server) PrepareRun(ctx context.Context) (Prepared, error) {
 	}); err != nil {
 		return Prepared{}, err
 	}
+
+	// older deployments granted access to all Secrets and ConfigMaps through an aggregated ClusterRole
+	if err := s.Config.KubeClient.RbacV1().ClusterRoles().Delete(ctx, konnectorrbac.LegacyRelatedResourcesClusterRoleName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return Prepared{}, fmt.Errorf("failed to delete legacy ClusterRole %s: %w", konnectorrbac.LegacyRelatedResourcesClusterRoleName, err)
+	}
+
 	return Prepared{
 		prepared: &prepared{
 			Server: *s,
@@ -82,18 +143,116 @@ func (s *Prepared) OptionallyStartInformers(ctx context.Context) {
 	s.Config.KubeInformers.Start(ctx.Done())
 	s.Config.BindInformers.Start(ctx.Done())
 	s.Config.ApiextensionsInformers.Start(ctx.Done())
+	s.Config.SecretInformer.Start(ctx.Done())
 	kubeSynced := s.Config.KubeInformers.WaitForCacheSync(ctx.Done())
 	kubeBindSynced := s.Config.BindInformers.WaitForCacheSync(ctx.Done())
 	apiextensionsSynced := s.Config.ApiextensionsInformers.WaitForCacheSync(ctx.Done())
+	secretsSynced := s.Config.SecretInformer.WaitForCacheSync(ctx.Done())
 
 	logger.Info("local informers are synced",
 		"kubeSynced", fmt.Sprintf("%v", kubeSynced),
 		"kubeBindSynced", fmt.Sprintf("%v", kubeBindSynced),
 		"apiextensionsSynced", fmt.Sprintf("%v", apiextensionsSynced),
+		"secretsSynced", fmt.Sprintf("%v", secretsSynced),
+	)
+}
+
+// StartMetricsServer serves the konnector metrics in the background, independently of leader election.
+func (s Prepared) StartMetricsServer(ctx context.Context) {
+	if s.Config.Options.MetricsBindAddress == "" {
+		return
+	}
+	go s.serveMetrics(ctx)
+}
+
+// StartConversionWebhook serves the conversion webhooks of bound CRDs in the background, independently
+// of leader election and sharding, as the consumer API server calls any replica behind the service.
+func (s Prepared) StartConversionWebhook(ctx context.Context) {
+	if s.ConversionWebhook == nil {
+		return
+	}
+	go s.serveConversionWebhook(ctx)
+}
+
+// StartHealthServer serves /healthz and /readyz in the background, independently of leader election.
+// The konnector is ready when the local and provider informers have synced. Standby replicas are ready
+// too, as they serve conversion webhooks and must not block rollouts. Leadership is reported by the
+// kube_bind_konnector_leader metric instead.
+func (s Prepared) StartHealthServer(ctx context.Context) {
+	if s.Config.Options.HealthPort == 0 {
+		return
+	}
+	go health.Serve(ctx, s.Config.Options.HealthBindAddress, s.Config.Options.HealthPort,
+		health.InformersSynced("kube-informers", s.Config.KubeInformers),
+		health.InformersSynced("bind-informers", s.Config.BindInformers),
+		health.InformersSynced("apiextensions-informers", s.Config.ApiextensionsInformers),
+		health.InformersSynced("secret-informers", s.Config.SecretInformer),
+		healthz.NamedCheck("provider-informers", func(_ *http.Request) error {
+			return s.Controller.providersSynced()
+		}),
 	)
 }
 
 func (s Prepared) Run(ctx context.Context) error {
+	metrics.SetLeader(true)
+	defer metrics.SetLeader(false)
+
+	if s.Config.Sharder != nil {
+		go s.Config.Sharder.Run(ctx)
+	}
+
 	s.Controller.Start(ctx, 2)
 	return nil
 }
+
+func (s Prepared) serveMetrics(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Addr:              s.Config.Options.MetricsBindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+
+	logger.Info("serving metrics", "address", server.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error(err, "failed to serve metrics")
+	}
+}
+
+func (s Prepared) serveConversionWebhook(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle(conversion.PathPrefix, s.ConversionWebhook)
+	server := &http.Server{
+		Addr:              s.Config.Options.ConversionWebhookBindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Close() // nolint:errcheck
+	}()
+
+	certFile, keyFile := s.Config.Options.ConversionWebhookCertFile, s.Config.Options.ConversionWebhookKeyFile
+	if s.conversionWebhookCert != nil {
+		server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{*s.conversionWebhookCert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		certFile, keyFile = "", ""
+	}
+
+	logger.Info("serving conversion webhooks", "address", server.Addr)
+	err := server.ListenAndServeTLS(certFile, keyFile)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error(err, "failed to serve conversion webhooks")
+	}
+}
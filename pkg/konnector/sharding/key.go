@@ -0,0 +1,65 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"sort"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ConnectionKey returns the key an APIServiceBinding is sharded by, i.e. its service provider
+// connections as API server and namespace of the provider kubeconfigs. The cluster controllers and
+// the APIServiceExport syncers run per provider connection and sync the exports of all bindings to
+// that provider, hence these bindings must be owned by the same replica. The key does not change
+// when credentials are rotated. Without any readable kubeconfig, the binding name is the key.
+func ConnectionKey(binding *kubebindv1alpha1.APIServiceBinding, getSecret func(ns, name string) (*corev1.Secret, error)) (string, error) {
+	var connections []string
+	for _, p := range binding.Spec.Providers {
+		secret, err := getSecret(p.Kubeconfig.Namespace, p.Kubeconfig.Name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		cfg, err := clientcmd.Load(secret.Data[p.Kubeconfig.Key])
+		if err != nil {
+			continue // the APIServiceBinding controller sets a condition
+		}
+		kubeContext, found := cfg.Contexts[cfg.CurrentContext]
+		if !found {
+			continue
+		}
+		cluster, found := cfg.Clusters[kubeContext.Cluster]
+		if !found {
+			continue
+		}
+		connections = append(connections, cluster.Server+"/"+kubeContext.Namespace)
+	}
+	if len(connections) == 0 {
+		return binding.Name, nil
+	}
+
+	sort.Strings(connections)
+	return strings.Join(connections, ","), nil
+}
@@ -0,0 +1,298 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// LabelKey marks the member Leases of the konnector replicas. The value is the name of the
+	// group of replicas sharing the APIServiceBindings.
+	LabelKey = "kube-bind.appscode.com/konnector-shards"
+
+	leaseDuration = 15 * time.Second
+	renewPeriod   = 5 * time.Second
+
+	// claimDelay is how long a joining replica waits before claiming its keys. By then every other
+	// replica has seen it, and released these keys.
+	claimDelay = 2 * renewPeriod
+)
+
+// Sharder assigns APIServiceBindings to the konnector replicas of a group by consistent hashing of
+// their ConnectionKey. Every replica announces itself through a member Lease it renews, and owns the
+// keys for which it has the highest hash among the replicas with unexpired Leases. When replicas
+// join or leave, only the keys of these replicas move. A joining replica claims its keys only after
+// claimDelay, such that no key is owned by two replicas during the handoff.
+//
+// Leases are considered expired leaseDuration after their renewal has been observed locally, not
+// after their renew time, such that the clocks of the replicas do not need to be in sync.
+//
+// A nil Sharder owns all bindings, i.e. the konnector is not sharded.
+type Sharder struct {
+	client    coordinationv1client.LeasesGetter
+	namespace string
+	group     string
+	identity  string
+	now       func() time.Time
+
+	// observed is only accessed by sync.
+	observed map[string]*observation
+
+	lock     sync.RWMutex
+	members  []string // sorted
+	claiming bool
+	handlers []func()
+}
+
+// observation is the local view of the member Lease of a replica.
+type observation struct {
+	renewTime  metav1.MicroTime
+	observedAt time.Time // when renewTime has changed last
+	joinedAt   time.Time // when the Lease became live last
+}
+
+// New returns a Sharder for the replica with the given identity in the group of replicas with
+// member Leases in the given namespace.
+func New(client coordinationv1client.LeasesGetter, namespace, group, identity string) *Sharder {
+	return &Sharder{
+		client:    client,
+		namespace: namespace,
+		group:     group,
+		identity:  identity,
+		now:       time.Now,
+		observed:  map[string]*observation{},
+	}
+}
+
+// Identity returns the identity of this replica, or an empty string if not sharded.
+func (s *Sharder) Identity() string {
+	if s == nil {
+		return ""
+	}
+	return s.identity
+}
+
+// Owns returns whether this replica owns the given key, usually the ConnectionKey of an
+// APIServiceBinding. Before the member Leases are known, and for claimDelay after joining, it owns
+// none.
+func (s *Sharder) Owns(key string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.claiming && owner(s.members, key) == s.identity
+}
+
+// AddEventHandler adds a handler called when replicas join or leave, i.e. when bindings move
+// between replicas.
+func (s *Sharder) AddEventHandler(handler func()) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Run renews the member Lease of this replica and watches the other members until ctx is done.
+// Then the member Lease is deleted for the other replicas to take over right away.
+func (s *Sharder) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("group", s.group, "identity", s.identity)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("joining konnector shards")
+	wait.UntilWithContext(ctx, s.sync, renewPeriod)
+
+	logger.Info("leaving konnector shards")
+	deleteCtx, cancel := context.WithTimeout(context.Background(), renewPeriod)
+	defer cancel()
+	if err := s.client.Leases(s.namespace).Delete(deleteCtx, s.leaseName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "failed to delete member lease")
+	}
+}
+
+func (s *Sharder) sync(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+
+	now := s.now()
+	renewErr := s.renew(ctx, now)
+	if renewErr != nil {
+		logger.Error(renewErr, "failed to renew member lease")
+	}
+
+	leases, err := s.client.Leases(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{LabelKey: s.group}).String(),
+	})
+	if err != nil {
+		logger.Error(err, "failed to list member leases")
+		return
+	}
+
+	members := s.observe(leases.Items, now)
+	claiming := false
+	if self, found := s.observed[s.identity]; found && sets.New(members...).Has(s.identity) {
+		claiming = !now.Before(self.joinedAt.Add(claimDelay))
+	}
+	if renewErr != nil {
+		// without a renewed lease the others will drop this replica soon. Drop the bindings first,
+		// and join again when renewing works again.
+		members = removeMember(members, s.identity)
+		delete(s.observed, s.identity)
+		claiming = false
+	}
+
+	s.lock.Lock()
+	changed := !reflect.DeepEqual(s.members, members) || s.claiming != claiming
+	s.members = members
+	s.claiming = claiming
+	handlers := append([]func(){}, s.handlers...)
+	s.lock.Unlock()
+
+	if changed {
+		logger.Info("konnector shards changed", "members", members, "claiming", claiming)
+		for _, h := range handlers {
+			h()
+		}
+	}
+}
+
+func (s *Sharder) leaseName() string {
+	return s.group + "-shard-" + s.identity
+}
+
+func (s *Sharder) renew(ctx context.Context, now time.Time) error {
+	lease, err := s.client.Leases(s.namespace).Get(ctx, s.leaseName(), metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.leaseName(),
+				Namespace: s.namespace,
+				Labels:    map[string]string{LabelKey: s.group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.identity),
+				LeaseDurationSeconds: ptr.To(int32(leaseDuration.Seconds())),
+				AcquireTime:          ptr.To(metav1.NewMicroTime(now)),
+				RenewTime:            ptr.To(metav1.NewMicroTime(now)),
+			},
+		}
+		_, err = s.client.Leases(s.namespace).Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = ptr.To(s.identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(leaseDuration.Seconds()))
+	lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(now))
+	_, err = s.client.Leases(s.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// observe records the renewals of the given leases at the local time now, and returns the sorted
+// holder identities of the unexpired leases.
+func (s *Sharder) observe(leases []coordinationv1.Lease, now time.Time) []string {
+	var members []string
+	seen := sets.New[string]()
+	for _, lease := range leases {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		identity := *lease.Spec.HolderIdentity
+		duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+		seen.Insert(identity)
+
+		o, found := s.observed[identity]
+		switch {
+		case !found:
+			o = &observation{renewTime: *lease.Spec.RenewTime, observedAt: now, joinedAt: now}
+			s.observed[identity] = o
+		case !o.renewTime.Equal(lease.Spec.RenewTime):
+			if !now.Before(o.observedAt.Add(duration)) {
+				o.joinedAt = now // expired in the meantime
+			}
+			o.renewTime = *lease.Spec.RenewTime
+			o.observedAt = now
+		}
+		if now.Before(o.observedAt.Add(duration)) {
+			members = append(members, identity)
+		}
+	}
+	for identity := range s.observed {
+		if !seen.Has(identity) {
+			delete(s.observed, identity)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+func removeMember(members []string, identity string) []string {
+	var ret []string
+	for _, m := range members {
+		if m != identity {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// owner returns the member owning the given name by rendezvous hashing, or an empty string
+// without members.
+func owner(members []string, name string) string {
+	var best string
+	var bestScore uint64
+	for _, m := range members {
+		if score := hash(m + "/" + name); best == "" || score > bestScore {
+			best, bestScore = m, score
+		}
+	}
+	return best
+}
+
+// hash returns FNV-1a of s, with the bits mixed to spread similar strings evenly.
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s)) // nolint:errcheck
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
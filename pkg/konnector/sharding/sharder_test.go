@@ -0,0 +1,250 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestOwner(t *testing.T) {
+	require.Equal(t, "", owner(nil, "mongodbs.mongodb.example.com"))
+
+	members := []string{"konnector-a", "konnector-b", "konnector-c"}
+	owned := map[string]int{}
+	before := map[string]string{}
+	for i := 0; i < 300; i++ {
+		name := fmt.Sprintf("binding-%d", i)
+		before[name] = owner(members, name)
+		owned[before[name]]++
+	}
+	for _, m := range members {
+		require.Greater(t, owned[m], 50, "every member should own a fair share")
+	}
+
+	// only the bindings of a leaving member move
+	remaining := []string{"konnector-a", "konnector-c"}
+	for name, was := range before {
+		if was != "konnector-b" {
+			require.Equal(t, was, owner(remaining, name), name)
+		}
+	}
+}
+
+func TestObserve(t *testing.T) {
+	now := time.Now()
+	lease := func(identity string, renewed time.Time) coordinationv1.Lease {
+		return coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(identity),
+			LeaseDurationSeconds: ptr.To(int32(15)),
+			RenewTime:            ptr.To(metav1.NewMicroTime(renewed)),
+		}}
+	}
+
+	s := New(nil, "kube-bind", "konnector", "konnector-a")
+	require.Equal(t, []string{"konnector-a", "konnector-b", "konnector-c"}, s.observe([]coordinationv1.Lease{
+		lease("konnector-c", now),
+		lease("konnector-b", now.Add(-time.Hour)), // clock of konnector-b is behind
+		lease("konnector-a", now),
+		{},
+	}, now))
+
+	// konnector-b stops renewing, konnector-c leaves
+	now = now.Add(10 * time.Second)
+	require.Equal(t, []string{"konnector-a", "konnector-b"}, s.observe([]coordinationv1.Lease{
+		lease("konnector-b", now.Add(-time.Hour-10*time.Second)),
+		lease("konnector-a", now),
+	}, now))
+	now = now.Add(10 * time.Second)
+	require.Equal(t, []string{"konnector-a"}, s.observe([]coordinationv1.Lease{
+		lease("konnector-b", now.Add(-time.Hour-20*time.Second)),
+		lease("konnector-a", now),
+	}, now))
+	require.Len(t, s.observed, 2)
+
+	// konnector-b renews again and joins anew
+	require.Equal(t, []string{"konnector-a", "konnector-b"}, s.observe([]coordinationv1.Lease{
+		lease("konnector-b", now.Add(-time.Hour)),
+		lease("konnector-a", now),
+	}, now))
+	require.Equal(t, now, s.observed["konnector-b"].joinedAt)
+}
+
+func TestHandoff(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	now := time.Now()
+	newReplica := func(identity string, skew time.Duration) *Sharder {
+		s := New(client.CoordinationV1(), "kube-bind", "konnector", identity)
+		s.now = func() time.Time { return now.Add(skew) }
+		return s
+	}
+	var keys []string
+	for i := 0; i < 50; i++ {
+		keys = append(keys, fmt.Sprintf("https://provider-%d:6443/kube-bind-abc", i))
+	}
+	requireOwners := func(replicas ...*Sharder) {
+		t.Helper()
+		for _, key := range keys {
+			var owners []string
+			for _, replica := range replicas {
+				if replica.Owns(key) {
+					owners = append(owners, replica.Identity())
+				}
+			}
+			require.LessOrEqual(t, len(owners), 1, "key %s must not be owned by more than one replica: %v", key, owners)
+		}
+	}
+	owned := func(replica *Sharder) int {
+		n := 0
+		for _, key := range keys {
+			if replica.Owns(key) {
+				n++
+			}
+		}
+		return n
+	}
+	sync := func(replicas ...*Sharder) {
+		for _, replica := range replicas {
+			replica.sync(context.Background())
+		}
+	}
+
+	a := newReplica("konnector-a", 0)
+	sync(a)
+	require.Zero(t, owned(a), "a joining replica must not claim keys right away")
+	now = now.Add(claimDelay)
+	sync(a)
+	require.Equal(t, len(keys), owned(a))
+
+	// konnector-b joins with a clock far behind
+	b := newReplica("konnector-b", -time.Hour)
+	for elapsed := time.Duration(0); elapsed <= claimDelay; elapsed += renewPeriod {
+		sync(b)
+		requireOwners(a, b)
+		sync(a)
+		requireOwners(a, b)
+		now = now.Add(renewPeriod)
+	}
+	sync(b)
+	requireOwners(a, b)
+	require.NotZero(t, owned(a))
+	require.NotZero(t, owned(b))
+	require.Equal(t, len(keys), owned(a)+owned(b), "every key must be owned after the handoff")
+}
+
+func TestNilSharder(t *testing.T) {
+	var s *Sharder
+	require.True(t, s.Owns("mongodbs.mongodb.example.com"))
+	require.Equal(t, "", s.Identity())
+	s.AddEventHandler(func() {})
+}
+
+func TestTwoBindingsOnOneProvider(t *testing.T) {
+	kubeconfig := func(server, ns, token string) []byte {
+		return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: provider
+  cluster:
+    server: %s
+contexts:
+- name: provider
+  context:
+    cluster: provider
+    namespace: %s
+    user: provider
+current-context: provider
+users:
+- name: provider
+  user:
+    token: %s
+`, server, ns, token))
+	}
+	secrets := map[string]*corev1.Secret{
+		"kube-bind/kubeconfig-a": {Data: map[string][]byte{"kubeconfig": kubeconfig("https://provider:6443", "kube-bind-abc", "token-1")}},
+		// the same provider connection with rotated credentials
+		"kube-bind/kubeconfig-b": {Data: map[string][]byte{"kubeconfig": kubeconfig("https://provider:6443", "kube-bind-abc", "token-2")}},
+	}
+	getSecret := func(ns, name string) (*corev1.Secret, error) {
+		if secret, found := secrets[ns+"/"+name]; found {
+			return secret, nil
+		}
+		return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+	}
+	binding := func(name, secret string) *kubebindv1alpha1.APIServiceBinding {
+		return &kubebindv1alpha1.APIServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kubebindv1alpha1.APIServiceBindingSpec{
+				Providers: []kubebindv1alpha1.Provider{{
+					Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+						LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: secret, Key: "kubeconfig"},
+						Namespace:         "kube-bind",
+					},
+				}},
+			},
+		}
+	}
+
+	client := fake.NewSimpleClientset()
+	now := time.Now()
+	var replicas []*Sharder
+	for i := 0; i < 3; i++ {
+		replica := New(client.CoordinationV1(), "kube-bind", "konnector", fmt.Sprintf("konnector-%d", i))
+		replica.now = func() time.Time { return now }
+		replicas = append(replicas, replica)
+	}
+	for _, replica := range replicas {
+		replica.sync(context.Background())
+	}
+	now = now.Add(claimDelay)
+	for _, replica := range replicas {
+		replica.sync(context.Background()) // see the replicas that joined later, and claim
+	}
+
+	for i := 0; i < 20; i++ {
+		first, err := ConnectionKey(binding(fmt.Sprintf("foos-%d.example.com", i), "kubeconfig-a"), getSecret)
+		require.NoError(t, err)
+		second, err := ConnectionKey(binding(fmt.Sprintf("bars-%d.example.com", i), "kubeconfig-b"), getSecret)
+		require.NoError(t, err)
+		require.Equal(t, "https://provider:6443/kube-bind-abc", first)
+
+		var owners []string
+		for _, replica := range replicas {
+			require.Equal(t, replica.Owns(first), replica.Owns(second), replica.Identity())
+			if replica.Owns(first) {
+				owners = append(owners, replica.Identity())
+			}
+		}
+		require.Len(t, owners, 1, "exactly one replica must sync the provider connection")
+	}
+
+	key, err := ConnectionKey(binding("bazs.example.com", "missing"), getSecret)
+	require.NoError(t, err)
+	require.Equal(t, "bazs.example.com", key)
+}
@@ -27,6 +27,9 @@ type Options struct {
 	// OptOutOfDefaultKubectlFlags indicates that the standard kubectl/kubeconfig-related flags should not be bound
 	// by default.
 	OptOutOfDefaultKubectlFlags bool
+	// OptOutOfKubectlTokenFlag indicates that the kubeconfig --token flag should not be bound, e.g. because
+	// the plugin has a --token flag of its own.
+	OptOutOfKubectlTokenFlag bool
 	// Kubeconfig specifies kubeconfig file(s).
 	Kubeconfig string
 	// KubectlOverrides stores the extra client connection fields, such as context, user, etc.
@@ -64,6 +67,9 @@ func (o *Options) BindFlags(cmd *cobra.Command) {
 	kubectlConfigOverrideFlags.AuthOverrideFlags.ImpersonateGroups.LongName = ""
 	kubectlConfigOverrideFlags.ContextOverrideFlags.ClusterName.LongName = ""
 	kubectlConfigOverrideFlags.Timeout.LongName = ""
+	if o.OptOutOfKubectlTokenFlag {
+		kubectlConfigOverrideFlags.AuthOverrideFlags.Token.LongName = ""
+	}
 
 	clientcmd.BindOverrideFlags(o.KubectlOverrides, cmd.PersistentFlags(), kubectlConfigOverrideFlags)
 }
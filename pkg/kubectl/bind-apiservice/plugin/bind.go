@@ -56,6 +56,7 @@ type BindAPIServiceOptions struct {
 	remoteKubeconfigName      string
 	remoteNamespace           string
 	file                      string
+	versions                  []string
 
 	// skipKonnector skips the deployment of the konnector.
 	SkipKonnector          bool
@@ -84,6 +85,7 @@ func (b *BindAPIServiceOptions) AddCmdFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&b.remoteKubeconfigNamespace, "remote-kubeconfig-namespace", b.remoteKubeconfigNamespace, "The namespace of the remote kubeconfig secret to read from")
 	cmd.Flags().StringVar(&b.remoteKubeconfigName, "remote-kubeconfig-name", b.remoteKubeconfigNamespace, "The name of the remote kubeconfig secret to read from")
 	cmd.Flags().StringVarP(&b.file, "file", "f", b.file, "A file with an APIServiceExportRequest manifest. Use - to read from stdin")
+	cmd.Flags().StringSliceVar(&b.versions, "versions", b.versions, "The versions to bind for each resource in the request, e.g. v1,v1beta1. Defaults to the versions in the request, or all served versions")
 	cmd.Flags().StringVar(&b.remoteNamespace, "remote-namespace", b.remoteNamespace, "The namespace in the remote cluster where the konnector is deployed")
 	cmd.Flags().BoolVar(&b.SkipKonnector, "skip-konnector", b.SkipKonnector, "Skip the deployment of the konnector")
 	cmd.Flags().BoolVar(&b.DowngradeKonnector, "downgrade-konnector", b.DowngradeKonnector, "Downgrade the konnector to the version of the kubectl-bind-apiservice binary")
@@ -158,6 +160,11 @@ func (b *BindAPIServiceOptions) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if len(b.versions) > 0 {
+		for i := range request.Spec.Resources {
+			request.Spec.Resources[i].Versions = b.versions
+		}
+	}
 	result, err := b.createServiceExportRequest(ctx, remoteConfig, remoteNamespace, request)
 	if err != nil {
 		return err
@@ -173,6 +180,9 @@ func (b *BindAPIServiceOptions) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := b.ensureKonnectorClusterRoles(ctx, config, result); err != nil {
+		return err
+	}
 
 	fmt.Fprintln(b.Options.ErrOut) // nolint: errcheck
 	return b.printTable(ctx, config, bindings)
@@ -0,0 +1,77 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	konnectorrbac "go.bytebuilders.dev/kube-bind/pkg/konnector/rbac"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ensureKonnectorClusterRoles grants the konnector access to the bound resources of the request. The
+// konnector's own ClusterRole does not cover arbitrary resources, but aggregates these ClusterRoles.
+// They are owned by the APIServiceBindings and are garbage collected with them.
+func (b *BindAPIServiceOptions) ensureKonnectorClusterRoles(ctx context.Context, config *rest.Config, request *v1alpha1.APIServiceExportRequest) error {
+	kubeClient, err := kubeclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range request.Spec.Resources {
+		binding, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, resource.Resource+"."+resource.Group, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		expected := konnectorrbac.BindingClusterRole(binding, resource.Group, resource.Resource)
+		existing, err := kubeClient.RbacV1().ClusterRoles().Get(ctx, expected.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		} else if apierrors.IsNotFound(err) {
+			if _, err := kubeClient.RbacV1().ClusterRoles().Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("failed to create ClusterRole %s: %w", expected.Name, err)
+			}
+			fmt.Fprintf(b.Options.IOStreams.ErrOut, "🔑 Granted the konnector access to %s.\n", binding.Name) // nolint: errcheck
+			continue
+		}
+		if !konnectorrbac.NeedsUpdate(existing, expected) {
+			continue
+		}
+
+		existing = existing.DeepCopy()
+		existing.Labels = expected.Labels
+		existing.OwnerReferences = expected.OwnerReferences
+		existing.Rules = expected.Rules
+		if _, err := kubeClient.RbacV1().ClusterRoles().Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update ClusterRole %s: %w", expected.Name, err)
+		}
+	}
+
+	return nil
+}
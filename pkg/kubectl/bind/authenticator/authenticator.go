@@ -22,9 +22,12 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -159,3 +162,33 @@ func (d *LocalhostCallbackAuthenticator) callback(w http.ResponseWriter, r *http
 	}
 	w.Write(bs.Bytes()) // nolint: errcheck
 }
+
+type formResponse struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+// postForm posts the form values to the service provider, with the bearer token if not empty.
+func postForm(ctx context.Context, client *http.Client, u string, values url.Values, bearerToken string) (*formResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &formResponse{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BindTokenAuthenticator exchanges a bind token pre-issued by the service provider for the
+// BindingResponse, without any user interaction.
+type BindTokenAuthenticator struct {
+	method *kubebindv1alpha1.BindToken
+	client *http.Client
+
+	token string
+}
+
+func NewBindTokenAuthenticator(method *kubebindv1alpha1.BindToken, token string) *BindTokenAuthenticator {
+	return &BindTokenAuthenticator{
+		method: method,
+		client: http.DefaultClient,
+		token:  token,
+	}
+}
+
+// Bind posts the bind token for the given session and cluster, and returns the BindingResponse.
+func (b *BindTokenAuthenticator) Bind(ctx context.Context, sessionID, clusterID string) (runtime.Object, *schema.GroupVersionKind, error) {
+	resp, err := postForm(ctx, b.client, b.method.BindURL, url.Values{"s": {sessionID}, "c": {clusterID}}, b.token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("binding with token failed: %s: %s", resp.Status, strings.TrimSpace(string(resp.Body)))
+	}
+
+	response, gvk, err := kubebindCodecs.UniversalDeserializer().Decode(resp.Body, nil, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding authResponse: %w", err)
+	}
+	return response, gvk, nil
+}
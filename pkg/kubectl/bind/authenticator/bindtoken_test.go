@@ -0,0 +1,53 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindTokenAuthenticator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "invalid bind token", http.StatusUnauthorized)
+			return
+		}
+		require.Equal(t, "session", r.FormValue("s"))
+		require.Equal(t, "cluster", r.FormValue("c"))
+		w.Write([]byte(`{"apiVersion":"kube-bind.appscode.com/v1alpha1","kind":"BindingResponse","authentication":{"bindToken":{"sid":"session","consumer":"ci"}}}`)) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	method := &kubebindv1alpha1.BindToken{BindURL: server.URL}
+
+	response, gvk, err := NewBindTokenAuthenticator(method, "secret").Bind(context.Background(), "session", "cluster")
+	require.NoError(t, err)
+	require.Equal(t, "BindingResponse", gvk.Kind)
+	bindingResponse, ok := response.(*kubebindv1alpha1.BindingResponse)
+	require.True(t, ok)
+	require.Equal(t, "ci", bindingResponse.Authentication.BindToken.Consumer)
+
+	_, _, err = NewBindTokenAuthenticator(method, "wrong").Bind(context.Background(), "session", "cluster")
+	require.ErrorContains(t, err, "invalid bind token")
+}
@@ -0,0 +1,124 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DeviceGrantAuthenticator authenticates through the OAuth2 device authorization grant of the
+// service provider. The user authorizes in a web browser on any device, while the authenticator
+// polls for the BindingResponse.
+type DeviceGrantAuthenticator struct {
+	method *kubebindv1alpha1.OAuth2DeviceGrant
+	client *http.Client
+
+	authorization *kubebindv1alpha1.OAuth2DeviceAuthorization
+}
+
+func NewDeviceGrantAuthenticator(method *kubebindv1alpha1.OAuth2DeviceGrant) *DeviceGrantAuthenticator {
+	return &DeviceGrantAuthenticator{
+		method: method,
+		client: http.DefaultClient,
+	}
+}
+
+// Start requests a device code and a user code for the given session and cluster.
+func (d *DeviceGrantAuthenticator) Start(ctx context.Context, sessionID, clusterID string) (*kubebindv1alpha1.OAuth2DeviceAuthorization, error) {
+	if d.authorization != nil {
+		return nil, errors.New("already started")
+	}
+
+	resp, err := postForm(ctx, d.client, d.method.DeviceAuthorizationURL, url.Values{"s": {sessionID}, "c": {clusterID}}, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization failed: %s", resp.Status)
+	}
+
+	authorization := &kubebindv1alpha1.OAuth2DeviceAuthorization{}
+	if err := json.Unmarshal(resp.Body, authorization); err != nil {
+		return nil, fmt.Errorf("failed to decode device authorization: %w", err)
+	}
+	if authorization.DeviceCode == "" || authorization.UserCode == "" || authorization.VerificationURI == "" {
+		return nil, errors.New("incomplete device authorization")
+	}
+	d.authorization = authorization
+
+	return authorization, nil
+}
+
+// WaitForResponse polls the service provider until the user has authorized and bound, or the
+// device code expires. Start() must be called prior to this.
+func (d *DeviceGrantAuthenticator) WaitForResponse(ctx context.Context) (runtime.Object, *schema.GroupVersionKind, error) {
+	if d.authorization == nil {
+		return nil, nil, errors.New("not started")
+	}
+
+	interval := time.Duration(d.authorization.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(d.authorization.ExpiresIn)*time.Second)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("error while waiting for response: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		resp, err := postForm(ctx, d.client, d.method.TokenURL, url.Values{"device_code": {d.authorization.DeviceCode}}, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			response, gvk, err := kubebindCodecs.UniversalDeserializer().Decode(resp.Body, nil, nil)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error decoding authResponse: %w", err)
+			}
+			return response, gvk, nil
+		}
+
+		var oauthErr struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(resp.Body, &oauthErr); err != nil {
+			return nil, nil, fmt.Errorf("device token request failed: %s", resp.Status)
+		}
+		switch oauthErr.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, nil, fmt.Errorf("device token request failed: %s", oauthErr.Error)
+		}
+	}
+}
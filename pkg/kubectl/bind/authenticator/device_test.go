@@ -0,0 +1,96 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceGrantAuthenticator(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device/authorize", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "session", r.FormValue("s"))
+		require.Equal(t, "cluster", r.FormValue("c"))
+		json.NewEncoder(w).Encode(&kubebindv1alpha1.OAuth2DeviceAuthorization{ // nolint:errcheck
+			DeviceCode:      "device",
+			UserCode:        "BCDF-GHJK",
+			VerificationURI: "https://example.com/device",
+			ExpiresIn:       60,
+			Interval:        1,
+		})
+	})
+	mux.HandleFunc("/device/token", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "device", r.FormValue("device_code"))
+		polls++
+		if polls == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"authorization_pending"}`)) // nolint:errcheck
+			return
+		}
+		w.Write([]byte(`{"apiVersion":"kube-bind.appscode.com/v1alpha1","kind":"BindingResponse","authentication":{"oauth2CodeGrant":{"sid":"session","id":"user"}}}`)) // nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	auth := NewDeviceGrantAuthenticator(&kubebindv1alpha1.OAuth2DeviceGrant{
+		DeviceAuthorizationURL: server.URL + "/device/authorize",
+		TokenURL:               server.URL + "/device/token",
+	})
+	authorization, err := auth.Start(context.Background(), "session", "cluster")
+	require.NoError(t, err)
+	require.Equal(t, "BCDF-GHJK", authorization.UserCode)
+
+	response, gvk, err := auth.WaitForResponse(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "BindingResponse", gvk.Kind)
+	require.Equal(t, 2, polls)
+	bindingResponse, ok := response.(*kubebindv1alpha1.BindingResponse)
+	require.True(t, ok)
+	require.Equal(t, "session", bindingResponse.Authentication.OAuth2CodeGrant.SessionID)
+}
+
+func TestDeviceGrantAuthenticatorExpired(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device/authorize", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"device_code":"device","user_code":"BCDF-GHJK","verification_uri":"https://example.com/device","expires_in":60,"interval":1}`)) // nolint:errcheck
+	})
+	mux.HandleFunc("/device/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"expired_token"}`)) // nolint:errcheck
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	auth := NewDeviceGrantAuthenticator(&kubebindv1alpha1.OAuth2DeviceGrant{
+		DeviceAuthorizationURL: server.URL + "/device/authorize",
+		TokenURL:               server.URL + "/device/token",
+	})
+	_, err := auth.Start(context.Background(), "session", "cluster")
+	require.NoError(t, err)
+
+	_, _, err = auth.WaitForResponse(context.Background())
+	require.ErrorContains(t, err, "expired_token")
+}
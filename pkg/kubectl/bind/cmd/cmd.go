@@ -34,6 +34,12 @@ var bindExampleUses = `
 	# select a kube-bind.appscode.com compatible service from the given URL, e.g. an API service.
 	%[1]s bind https://mangodb.com/exports
 
+	# authenticate without a local browser, e.g. over SSH, by entering a code on any device.
+	%[1]s bind https://mangodb.com/exports --device
+
+	# bind without login with a bind token issued by the service provider, e.g. in GitOps or CI.
+	%[1]s bind https://mangodb.com/exports --token=$BIND_TOKEN
+
 	# authenticate and configure the services to bind, but don't actually bind them.
 	%[1]s bind https://mangodb.com/exports --dry-run -o yaml > apiservice-export-requests.yaml
 
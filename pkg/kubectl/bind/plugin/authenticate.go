@@ -17,6 +17,7 @@ limitations under the License.
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,10 +28,13 @@ import (
 	"strings"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/kubectl/bind/authenticator"
 	"go.bytebuilders.dev/kube-bind/pkg/version"
 
 	"github.com/blang/semver/v4"
 	"github.com/mdp/qrterminal/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgoversion "k8s.io/client-go/pkg/version"
 )
 
@@ -142,3 +146,52 @@ func (b *BindOptions) authenticate(provider *kubebindv1alpha1.BindingProvider, c
 
 	return nil
 }
+
+func (b *BindOptions) authenticateDevice(ctx context.Context, provider *kubebindv1alpha1.BindingProvider, sessionID, clusterID string, urlCh chan<- string) (*authenticator.DeviceGrantAuthenticator, error) {
+	var deviceMethod *kubebindv1alpha1.OAuth2DeviceGrant
+	for _, m := range provider.AuthenticationMethods {
+		if m.Method == "OAuth2DeviceGrant" {
+			deviceMethod = m.OAuth2DeviceGrant
+			break
+		}
+	}
+	if deviceMethod == nil {
+		return nil, errors.New("server does not support OAuth2 device grant flow")
+	}
+
+	auth := authenticator.NewDeviceGrantAuthenticator(deviceMethod)
+	authorization, err := auth.Start(ctx, sessionID, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	fmt.Fprintf(b.Options.ErrOut, "\nTo authenticate, visit on any device:\n\n\t%s\n\nand enter the code:\n\n\t%s\n", authorization.VerificationURI, authorization.UserCode) // nolint: errcheck
+
+	if urlCh != nil {
+		if authorization.VerificationURIComplete != "" {
+			urlCh <- authorization.VerificationURIComplete
+		} else {
+			urlCh <- authorization.VerificationURI
+		}
+	}
+
+	return auth, nil
+}
+
+func (b *BindOptions) authenticateToken(provider *kubebindv1alpha1.BindingProvider, sessionID, clusterID string) (func(ctx context.Context) (runtime.Object, *schema.GroupVersionKind, error), error) {
+	var tokenMethod *kubebindv1alpha1.BindToken
+	for _, m := range provider.AuthenticationMethods {
+		if m.Method == "BindToken" {
+			tokenMethod = m.BindToken
+			break
+		}
+	}
+	if tokenMethod == nil {
+		return nil, errors.New("server does not support bind tokens")
+	}
+
+	auth := authenticator.NewBindTokenAuthenticator(tokenMethod, b.Token)
+	return func(ctx context.Context) (runtime.Object, *schema.GroupVersionKind, error) {
+		return auth.Bind(ctx, sessionID, clusterID)
+	}, nil
+}
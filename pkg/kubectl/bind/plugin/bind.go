@@ -41,6 +41,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	kubeclient "k8s.io/client-go/kubernetes"
@@ -67,6 +69,13 @@ type BindOptions struct {
 	// The konnector image to use and override default konnector image
 	KonnectorImageOverride string
 
+	// Device authenticates with the OAuth2 device grant instead of a localhost callback.
+	Device bool
+
+	// Token is a bind token pre-issued by the service provider, exchanged for the binding
+	// without any login.
+	Token string
+
 	// Runner is runs the command. It can be replaced in tests.
 	Runner func(cmd *exec.Cmd) error
 
@@ -85,6 +94,9 @@ func NewBindOptions(streams genericclioptions.IOStreams) *BindOptions {
 		},
 	}
 
+	// --token is the bind token, not the bearer token of the consumer cluster.
+	opts.OptOutOfKubectlTokenFlag = true
+
 	return opts
 }
 
@@ -99,6 +111,8 @@ func (b *BindOptions) AddCmdFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&b.SkipKonnector, "skip-konnector", b.SkipKonnector, "Skip the deployment of the konnector")
 	cmd.Flags().BoolVarP(&b.DryRun, "dry-run", "d", b.DryRun, "If true, only print the requests that would be sent to the service provider after authentication, without actually binding.")
 	cmd.Flags().StringVar(&b.KonnectorImageOverride, "konnector-image", b.KonnectorImageOverride, "The konnector image to use")
+	cmd.Flags().BoolVar(&b.Device, "device", b.Device, "Authenticate with the OAuth2 device grant, without a local browser, e.g. over SSH or in CI")
+	cmd.Flags().StringVar(&b.Token, "token", b.Token, "A bind token pre-issued by the service provider, to bind without login, e.g. in GitOps or CI")
 }
 
 // Complete ensures all fields are initialized.
@@ -131,6 +145,10 @@ func (b *BindOptions) Validate() error {
 		return fmt.Errorf("invalid url %q: %w", b.URL, err)
 	}
 
+	if b.Device && b.Token != "" {
+		return errors.New("--device and --token are mutually exclusive")
+	}
+
 	return b.Options.Validate()
 }
 
@@ -158,7 +176,7 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 
 	providerClusterName := exportURL.Query().Get("cluster")
 	user := exportURL.Query().Get("user")
-	if user == "" {
+	if user == "" && b.Token == "" {
 		return fmt.Errorf("missing user in the connect url")
 	}
 
@@ -187,21 +205,38 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 		}
 	}
 
-	auth := authenticator.NewLocalhostCallbackAuthenticator(redirectUrl(exportURL.Host, user, providerClusterName))
-	err = auth.Start()
-	fmt.Fprintf(b.Options.ErrOut, "\n\n")
-	if err != nil {
-		return err
-	}
-
 	sessionID := SessionID()
-	if err := b.authenticate(provider, auth.Endpoint(), sessionID, ClusterID(ns), providerClusterName, user, urlCh); err != nil {
-		return err
-	}
-
 	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
-	response, gvk, err := auth.WaitForResponse(timeoutCtx)
+
+	var waitForResponse func(ctx context.Context) (runtime.Object, *schema.GroupVersionKind, error)
+	if b.Token != "" {
+		auth, err := b.authenticateToken(provider, sessionID, ClusterID(ns))
+		if err != nil {
+			return err
+		}
+		waitForResponse = auth
+	} else if b.Device {
+		auth, err := b.authenticateDevice(timeoutCtx, provider, sessionID, ClusterID(ns), urlCh)
+		if err != nil {
+			return err
+		}
+		waitForResponse = auth.WaitForResponse
+	} else {
+		auth := authenticator.NewLocalhostCallbackAuthenticator(redirectUrl(exportURL.Host, user, providerClusterName))
+		err = auth.Start()
+		fmt.Fprintf(b.Options.ErrOut, "\n\n")
+		if err != nil {
+			return err
+		}
+
+		if err := b.authenticate(provider, auth.Endpoint(), sessionID, ClusterID(ns), providerClusterName, user, urlCh); err != nil {
+			return err
+		}
+		waitForResponse = auth.WaitForResponse
+	}
+
+	response, gvk, err := waitForResponse(timeoutCtx)
 	if err != nil {
 		return err
 	}
@@ -216,10 +251,18 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 	if !ok {
 		return fmt.Errorf("unexpected response type %T", response)
 	}
-	if bindingResponse.Authentication.OAuth2CodeGrant == nil {
+	var responseSessionID string
+	switch {
+	case bindingResponse.Authentication.OAuth2CodeGrant != nil && b.Token == "":
+		responseSessionID = bindingResponse.Authentication.OAuth2CodeGrant.SessionID
+	case bindingResponse.Authentication.BindToken != nil && b.Token != "":
+		responseSessionID = bindingResponse.Authentication.BindToken.SessionID
+	case b.Token != "":
+		return fmt.Errorf("unexpected response: authentication.bindToken is nil")
+	default:
 		return fmt.Errorf("unexpected response: authentication.oauth2CodeGrant is nil")
 	}
-	if bindingResponse.Authentication.OAuth2CodeGrant.SessionID != sessionID {
+	if responseSessionID != sessionID {
 		return fmt.Errorf("unexpected response: sessionID does not match")
 	}
 
@@ -41,5 +41,7 @@ var (
 	LocalFlags = sets.New[string](
 		"d",
 		"dry-run",
+		"device",
+		"token",
 	)
 )